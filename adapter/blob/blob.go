@@ -0,0 +1,56 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"myapp/config"
+	"path"
+	"strings"
+)
+
+// ErrNotFound is returned by Get for keys which have no blob.
+var ErrNotFound = errors.New("blob not found")
+
+// Object is a blob read from a store; its Body must be closed.
+type Object struct {
+	Body io.ReadCloser
+	Size int64
+}
+
+// Store keeps blobs by key. Keys are slash-separated relative paths.
+type Store interface {
+	// Put creates or replaces the blob of key.
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (*Object, error)
+	// Delete removes the blob of key; deleting a missing blob isn't an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the store selected by the cover configuration.
+func New(conf *config.Conf) (Store, error) {
+	switch conf.Cover.Storage {
+	case "local":
+		return NewFileStore(conf.Cover.LocalDir)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  conf.Cover.S3Endpoint,
+			Region:    conf.Cover.S3Region,
+			Bucket:    conf.Cover.S3Bucket,
+			AccessKey: conf.Cover.S3AccessKey,
+			SecretKey: conf.Cover.S3SecretKey,
+		})
+	}
+
+	return nil, fmt.Errorf("unknown cover storage %q", conf.Cover.Storage)
+}
+
+// checkKey rejects keys which could escape the root of a store.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+
+	return nil
+}
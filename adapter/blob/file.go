@@ -0,0 +1,109 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// FileStore keeps blobs as files under a directory of the local filesystem.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("blob directory: %w", err)
+	}
+
+	return &FileStore{dir: filepath.Clean(dir)}, nil
+}
+
+func (f *FileStore) path(key string) (string, error) {
+	if err := checkKey(key); err != nil {
+		return "", err
+	}
+
+	return filepath.Join(f.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes the blob to a temporary file renamed over the previous one, so
+// readers never see a partial blob.
+func (f *FileStore) Put(ctx context.Context, key, contentType string, data []byte) error {
+	name, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	// A concurrent Delete may remove the directory once it's empty again.
+	var tmp *os.File
+	for attempt := 0; tmp == nil; attempt++ {
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			return err
+		}
+
+		tmp, err = os.CreateTemp(filepath.Dir(name), ".blob-*")
+		if err != nil && (attempt > 0 || !errors.Is(err, fs.ErrNotExist)) {
+			return err
+		}
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+func (f *FileStore) Get(ctx context.Context, key string) (*Object, error) {
+	name, err := f.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &Object{Body: file, Size: info.Size()}, nil
+}
+
+// Delete removes the file of the blob, and then its parent directories as
+// long as they're empty.
+func (f *FileStore) Delete(ctx context.Context, key string) error {
+	name, err := f.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Removing a directory which isn't empty fails, which ends the walk.
+	for dir := filepath.Dir(name); dir != f.dir; dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+
+	return nil
+}
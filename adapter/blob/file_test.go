@@ -0,0 +1,62 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Put(ctx, "covers/1/original", "image/png", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, "covers/1/original", "image/png", []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := store.Get(ctx, "covers/1/original")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+	assert.Equal(t, int64(6), obj.Size)
+
+	assert.NoError(t, store.Delete(ctx, "covers/1/original"))
+	assert.NoError(t, store.Delete(ctx, "covers/1/original"))
+
+	_, err = store.Get(ctx, "covers/1/original")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Deleting the last blob of a directory removes the directories left empty.
+	for _, key := range []string{"covers/2/abc/original", "covers/2/abc/small.jpg", "covers/3/original"} {
+		if err := store.Put(ctx, key, "image/png", []byte("cover")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.NoError(t, store.Delete(ctx, "covers/2/abc/original"))
+	assert.DirExists(t, filepath.Join(dir, "covers", "2", "abc"))
+	assert.NoError(t, store.Delete(ctx, "covers/2/abc/small.jpg"))
+	assert.NoDirExists(t, filepath.Join(dir, "covers", "2"))
+	assert.DirExists(t, filepath.Join(dir, "covers", "3"))
+	assert.NoError(t, store.Delete(ctx, "covers/3/original"))
+	assert.NoDirExists(t, filepath.Join(dir, "covers"))
+	assert.DirExists(t, dir)
+
+	for _, key := range []string{"", "/etc/passwd", "../outside", "covers/../../outside", "covers//1"} {
+		assert.Error(t, store.Put(ctx, key, "text/plain", nil), key)
+	}
+}
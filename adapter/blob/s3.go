@@ -0,0 +1,197 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// s3Timeout bounds each request to the service, so that a hung endpoint
+// can't stall uploads.
+const s3Timeout = 30 * time.Second
+
+// S3Config locates the bucket of an S3-compatible store.
+type S3Config struct {
+	// Endpoint is the base URL of the service, e.g. https://s3.eu-west-1.amazonaws.com
+	// or the URL of a MinIO server.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// S3Store keeps blobs as objects of a bucket of an S3-compatible service. It
+// addresses the bucket in the path, which every such service supports, and
+// signs its requests with AWS Signature Version 4.
+type S3Store struct {
+	conf     S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Store(conf S3Config) (*S3Store, error) {
+	endpoint, err := url.Parse(conf.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", conf.Endpoint)
+	}
+	if conf.Bucket == "" {
+		return nil, errors.New("missing S3 bucket")
+	}
+	if conf.Region == "" {
+		conf.Region = "us-east-1"
+	}
+
+	return &S3Store{conf: conf, endpoint: endpoint, client: &http.Client{Timeout: s3Timeout}, now: time.Now}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return s.checkStatus(resp, http.StatusOK)
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (*Object, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if err := s.checkStatus(resp, http.StatusOK); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return &Object{Body: resp.Body, Size: resp.ContentLength}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 answers 204 whether or not the object existed.
+	return s.checkStatus(resp, http.StatusNoContent, http.StatusOK, http.StatusNotFound)
+}
+
+func (s *S3Store) do(ctx context.Context, method, key, contentType string, data []byte) (*http.Response, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.conf.Bucket + "/" + key
+	u.RawPath = ""
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(req, data)
+
+	return s.client.Do(req)
+}
+
+func (s *S3Store) checkStatus(resp *http.Response, statuses ...int) error {
+	for _, status := range statuses {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s: %s", resp.Request.Method, resp.Request.URL.Path, resp.Status, bytes.TrimSpace(body))
+}
+
+const (
+	signAlgorithm = "AWS4-HMAC-SHA256"
+	amzDateLayout = "20060102T150405Z"
+)
+
+// sign adds the headers of AWS Signature Version 4 to req, data being its body.
+func (s *S3Store) sign(req *http.Request, data []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format(amzDateLayout)
+	date := amzDate[:8]
+
+	payloadHash := sha256Hex(data)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncodePath(req.URL.Path),
+		"",
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.conf.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{signAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.conf.SecretKey), date)
+	key = hmacSHA256(key, s.conf.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signAlgorithm, s.conf.AccessKey, scope, signedHeaders, signature))
+}
+
+// uriEncodePath encodes every byte of path but the unreserved characters of
+// RFC 3986 and the slashes, as signatures expect.
+func uriEncodePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('0' <= c && c <= '9') || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
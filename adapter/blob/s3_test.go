@@ -0,0 +1,152 @@
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// s3StandIn is an S3-compatible server keeping the objects of a bucket in
+// memory. It checks the parts of the signature of the requests which don't
+// need the secret key.
+type s3StandIn struct {
+	t       *testing.T
+	bucket  string
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/20240102/eu-west-3/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+		s.t.Errorf("unexpected Authorization %q", auth)
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if got := r.Header.Get("X-Amz-Date"); got != "20240102T030405Z" {
+		s.t.Errorf("unexpected X-Amz-Date %q", got)
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	sum := sha256.Sum256(body)
+	if got := r.Header.Get("X-Amz-Content-Sha256"); got != hex.EncodeToString(sum[:]) {
+		s.t.Errorf("payload hash %q doesn't match the body", got)
+	}
+
+	prefix := "/" + s.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		s.objects[key] = body
+		s.types[key] = r.Header.Get("Content-Type")
+	case http.MethodGet:
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, "<Error><Code>NoSuchKey</Code></Error>")
+			return
+		}
+		w.Header().Set("Content-Type", s.types[key])
+		w.Write(data)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+
+	standIn := &s3StandIn{t: t, bucket: "covers", objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(standIn)
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:  server.URL,
+		Region:    "eu-west-3",
+		Bucket:    "covers",
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.now = func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	if err := store.Put(ctx, "covers/1/small.jpg", "image/jpeg", []byte("thumbnail")); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "image/jpeg", standIn.types["covers/1/small.jpg"])
+
+	obj, err := store.Get(ctx, "covers/1/small.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(obj.Body)
+	obj.Body.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "thumbnail", string(data))
+	assert.Equal(t, int64(9), obj.Size)
+
+	assert.NoError(t, store.Delete(ctx, "covers/1/small.jpg"))
+
+	_, err = store.Get(ctx, "covers/1/small.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestS3Store_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<Error><Code>SignatureDoesNotMatch</Code></Error>")
+	}))
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "covers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Put(context.Background(), "covers/1/original", "image/png", []byte("cover"))
+	assert.EqualError(t, err, "s3 PUT /covers/covers/1/original: 403 Forbidden: <Error><Code>SignatureDoesNotMatch</Code></Error>")
+}
+
+func TestS3Store_Timeout(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	store, err := NewS3Store(S3Config{Endpoint: server.URL, Bucket: "covers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, s3Timeout, store.client.Timeout)
+
+	store.client.Timeout = 50 * time.Millisecond
+	err = store.Put(context.Background(), "covers/1/original", "image/png", []byte("cover"))
+	assert.Error(t, err)
+}
+
+func TestURIEncodePath(t *testing.T) {
+	assert.Equal(t, "/bucket/covers/1/a%20b%2Bc~d.jpg", uriEncodePath("/bucket/covers/1/a b+c~d.jpg"))
+}
@@ -0,0 +1,102 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jinzhu/gorm"
+)
+
+const logModeKey = "myapp:log_mode"
+
+// ctxDB runs the statements of gorm through the context-aware methods of
+// database/sql, which gorm v1 doesn't use, so they are cancelled with ctx.
+type ctxDB struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+func (c *ctxDB) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.db.ExecContext(c.ctx, query, args...)
+}
+
+func (c *ctxDB) Prepare(query string) (*sql.Stmt, error) {
+	return c.db.PrepareContext(c.ctx, query)
+}
+
+func (c *ctxDB) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.db.QueryContext(c.ctx, query, args...)
+}
+
+func (c *ctxDB) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.db.QueryRowContext(c.ctx, query, args...)
+}
+
+// Begin is used by gorm for the implicit transactions of creates, updates and deletes.
+func (c *ctxDB) Begin() (*sql.Tx, error) {
+	return c.db.BeginTx(c.ctx, nil)
+}
+
+func (c *ctxDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return c.db.BeginTx(ctx, opts)
+}
+
+// ctxTx runs the statements of a transaction through the context-aware
+// methods of database/sql, so that each is cancelled with ctx rather than only
+// with the context the transaction was started with.
+type ctxTx struct {
+	tx  *sql.Tx
+	ctx context.Context
+}
+
+func (c *ctxTx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.tx.ExecContext(c.ctx, query, args...)
+}
+
+func (c *ctxTx) Prepare(query string) (*sql.Stmt, error) {
+	return c.tx.PrepareContext(c.ctx, query)
+}
+
+func (c *ctxTx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.tx.QueryContext(c.ctx, query, args...)
+}
+
+func (c *ctxTx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.tx.QueryRowContext(c.ctx, query, args...)
+}
+
+// LogMode enables or disables the logging of every statement on db and on
+// the handles returned by WithContext.
+func LogMode(db *gorm.DB, enable bool) {
+	db.LogMode(enable)
+	db.InstantSet(logModeKey, enable)
+}
+
+// WithContext returns a handle on db whose statements are cancelled with ctx
+// and traced as children of the span in ctx, db being a connection pool or a
+// transaction. A statement of a transaction cancelled by ctx fails the
+// transaction, which is then rolled back.
+func WithContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	var common gorm.SQLCommon
+	switch conn := db.CommonDB().(type) {
+	case *sql.DB:
+		common = &ctxDB{db: conn, ctx: ctx}
+	case *sql.Tx:
+		common = &ctxTx{tx: conn, ctx: ctx}
+	default:
+		return db.Set(contextKey, ctx)
+	}
+
+	// Opening a handle on an existing connection pool or transaction only
+	// allocates the gorm.DB; callbacks are shared through gorm.DefaultCallback.
+	c, err := gorm.Open(db.Dialect().GetName(), common)
+	if err != nil {
+		return db.Set(contextKey, ctx)
+	}
+
+	if enable, ok := db.Get(logModeKey); ok {
+		c.LogMode(enable.(bool))
+	}
+
+	return c.Set(contextKey, ctx)
+}
@@ -9,6 +9,12 @@ import (
 	"myapp/config"
 )
 
+// The handles returned by WithContext use the default callbacks rather than
+// those of the connection, so that's where the query spans are registered.
+func init() {
+	RegisterTracing(gorm.DefaultCallback)
+}
+
 func New(conf *config.Conf) (*gorm.DB, error) {
 	cfg := &mysql.Config{
 		Net:                  "tcp",
@@ -18,7 +24,19 @@ func New(conf *config.Conf) (*gorm.DB, error) {
 		Passwd:               conf.Db.Password,
 		AllowNativePasswords: true,
 		ParseTime:            true,
+
+		// UPDATEs report the rows they match rather than those they change,
+		// so that one changing nothing, e.g. a PUT repeated within the same
+		// second, still finds its row.
+		ClientFoundRows: true,
 	}
 
-	return gorm.Open("mysql", cfg.FormatDSN())
+	db, err := gorm.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+
+	LogMode(db, conf.Debug)
+
+	return db, nil
 }
@@ -0,0 +1,120 @@
+package gorm
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jinzhu/gorm"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	contextKey = "myapp:context"
+	spanKey    = "myapp:span"
+)
+
+var tracer = otel.Tracer("myapp/adapter/gorm")
+
+// RegisterTracing adds callbacks to cb which wrap every query in a client span.
+// Queries run on a handle without a context, see WithContext, are not traced.
+func RegisterTracing(cb *gorm.Callback) {
+
+	cb.Create().Before("gorm:begin_transaction").Register("tracing:start_create", startSpan("gorm.Create"))
+	cb.Create().After("gorm:commit_or_rollback_transaction").Register("tracing:end_create", endSpan)
+
+	cb.Query().Before("gorm:query").Register("tracing:start_query", startSpan("gorm.Query"))
+	cb.Query().After("gorm:after_query").Register("tracing:end_query", endSpan)
+
+	cb.Update().Before("gorm:begin_transaction").Register("tracing:start_update", startSpan("gorm.Update"))
+	cb.Update().After("gorm:commit_or_rollback_transaction").Register("tracing:end_update", endSpan)
+
+	cb.Delete().Before("gorm:begin_transaction").Register("tracing:start_delete", startSpan("gorm.Delete"))
+	cb.Delete().After("gorm:commit_or_rollback_transaction").Register("tracing:end_delete", endSpan)
+
+	cb.RowQuery().Before("gorm:row_query").Register("tracing:start_row_query", startSpan("gorm.RowQuery"))
+	cb.RowQuery().After("gorm:row_query").Register("tracing:end_row_query", endSpan)
+}
+
+// Exec runs a statement gorm can't build, e.g. a multi-row INSERT, on db in a
+// client span like those of the callbacks, since gorm runs such statements
+// without its callbacks. The statement is on table.
+func Exec(db *gorm.DB, table, query string, args ...interface{}) (sql.Result, error) {
+	v, _ := db.Get(contextKey)
+	ctx, ok := v.(context.Context)
+	if !ok {
+		return db.CommonDB().Exec(query, args...)
+	}
+
+	_, span := tracer.Start(ctx, "gorm.Exec",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemMySQL,
+			semconv.DBSQLTableKey.String(table),
+			semconv.DBStatementKey.String(query),
+		),
+	)
+	defer span.End()
+
+	res, err := db.CommonDB().Exec(query, args...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	if n, err := res.RowsAffected(); err == nil {
+		span.SetAttributes(attribute.Int64("db.rows_affected", n))
+	}
+
+	return res, nil
+}
+
+func startSpan(name string) func(scope *gorm.Scope) {
+	return func(scope *gorm.Scope) {
+		v, ok := scope.Get(contextKey)
+		if !ok {
+			return
+		}
+		ctx, ok := v.(context.Context)
+		if !ok {
+			return
+		}
+
+		_, span := tracer.Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemMySQL,
+				semconv.DBSQLTableKey.String(scope.TableName()),
+			),
+		)
+		scope.Set(spanKey, span)
+	}
+}
+
+func endSpan(scope *gorm.Scope) {
+	v, ok := scope.Get(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+
+	// Only the statement is recorded; bound values may contain user data.
+	span.SetAttributes(
+		semconv.DBStatementKey.String(scope.SQL),
+		attribute.Int64("db.rows_affected", scope.DB().RowsAffected),
+	)
+
+	if err := scope.DB().Error; err != nil && !gorm.IsRecordNotFoundError(err) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"myapp/model"
+	"myapp/util/apperror"
+	"net/http"
+)
+
+func (a *App) HandleListApiKeys(w http.ResponseWriter, r *http.Request) {
+	query, err := model.NewPageForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	keys, err := a.svcApiKey.GetListApiKey(r.Context(), query)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, keys)
+}
+
+// HandleCreateApiKey mints a key; the response is the only time the key is
+// shown.
+func (a *App) HandleCreateApiKey(w http.ResponseWriter, r *http.Request) {
+	keyForm := model.ApiKeyForm{}
+	if err := ParseRequestBody(w, r, a, &keyForm); err != nil {
+		return
+	}
+
+	if err := ValidateForm(w, r, a, &keyForm); err != nil {
+		return
+	}
+
+	key, err := a.svcApiKey.CreateApiKey(r.Context(), &keyForm)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data creation failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("New API key created: %d", key.ID)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusCreated)
+
+	RespondJSON(w, r, a, key)
+}
+
+func (a *App) HandleUpdateApiKeyScopes(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	scopesForm := &model.ApiKeyScopesForm{}
+	if err := ParseRequestBody(w, r, a, scopesForm); err != nil {
+		return
+	}
+
+	if err := ValidateForm(w, r, a, scopesForm); err != nil {
+		return
+	}
+
+	if err := a.svcApiKey.UpdateApiKeyScopes(r.Context(), id, scopesForm); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("API key scopes updated: %d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *App) HandleRevokeApiKey(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	if err := a.svcApiKey.RevokeApiKey(r.Context(), id); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("API key revoked: %d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
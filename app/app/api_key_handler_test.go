@@ -0,0 +1,147 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HandleApiKeys(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		method      string
+		id          string
+		query       string
+		body        string
+		handler     func(a *app.App) http.HandlerFunc
+		statusCode  int
+		respBody    string
+		prepareMock func(mockSvc *mock_service.MockApiKeyServiceInterface)
+	}{
+		{
+			name:       "list",
+			method:     "GET",
+			query:      "?limit=1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleListApiKeys },
+			statusCode: http.StatusOK,
+			respBody:   `{"data":[{"id":1,"name":"nightly import","prefix":"mk_4f2a9c1e","scopes":["books:read"],"created_by":"alice","created_at":"2024-03-01T12:00:00Z","last_used_at":null}],"total":2,"limit":1,"offset":0}`,
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {
+				mockSvc.EXPECT().GetListApiKey(gomock.Any(), &model.PageQuery{Limit: 1}).
+					Return(&model.ApiKeyListDto{Data: []model.ApiKeyDto{{ID: 1, Name: "nightly import", Prefix: "mk_4f2a9c1e", Scopes: []string{"books:read"}, CreatedBy: "alice", CreatedAt: createdAt}}, Total: 2, Limit: 1}, nil)
+			},
+		},
+		{
+			name:       "create",
+			method:     "POST",
+			body:       `{"name":"nightly import","scopes":["books:read","books:write"]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateApiKey },
+			statusCode: http.StatusCreated,
+			respBody:   `{"id":1,"name":"nightly import","prefix":"mk_4f2a9c1e","scopes":["books:read","books:write"],"created_by":"alice","created_at":"2024-03-01T12:00:00Z","last_used_at":null,"key":"mk_4f2a9c1e0b"}`,
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {
+				mockSvc.EXPECT().CreateApiKey(gomock.Any(), &model.ApiKeyForm{Name: "nightly import", Scopes: []string{"books:read", "books:write"}}).
+					Return(&model.ApiKeyCreatedDto{
+						ApiKeyDto: model.ApiKeyDto{ID: 1, Name: "nightly import", Prefix: "mk_4f2a9c1e", Scopes: []string{"books:read", "books:write"}, CreatedBy: "alice", CreatedAt: createdAt},
+						Key:       "mk_4f2a9c1e0b",
+					}, nil)
+			},
+		},
+		{
+			name:       "create with unknown scope",
+			method:     "POST",
+			body:       `{"name":"nightly import","scopes":["api_keys:manage"]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateApiKey },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "create without scopes",
+			method:     "POST",
+			body:       `{"name":"nightly import","scopes":[]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateApiKey },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "update scopes",
+			method:     "PUT",
+			id:         "1",
+			body:       `{"scopes":["books:read"]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateApiKeyScopes },
+			statusCode: http.StatusNoContent,
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {
+				mockSvc.EXPECT().UpdateApiKeyScopes(gomock.Any(), uint(1), &model.ApiKeyScopesForm{Scopes: []string{"books:read"}}).Return(nil)
+			},
+		},
+		{
+			name:       "update scopes of revoked key",
+			method:     "PUT",
+			id:         "2",
+			body:       `{"scopes":["books:read"]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateApiKeyScopes },
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {
+				mockSvc.EXPECT().UpdateApiKeyScopes(gomock.Any(), uint(2), gomock.Any()).Return(apperror.NotFound("api key not found", nil))
+			},
+		},
+		{
+			name:       "revoke",
+			method:     "DELETE",
+			id:         "1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleRevokeApiKey },
+			statusCode: http.StatusNoContent,
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {
+				mockSvc.EXPECT().RevokeApiKey(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockApiKeyService := mock_service.NewMockApiKeyServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockApiKeyService)
+			}
+
+			req, err := http.NewRequest(tt.method, "api/v1/api-keys/"+tt.id+tt.query, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mockApiKeyService, mock_service.NewMockHealthServiceInterface(ctrl))
+
+			tt.handler(a).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.respBody != "" {
+				assert.JSONEq(t, tt.respBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -3,20 +3,43 @@ package app
 import (
 	"myapp/service"
 	"myapp/util/logger"
+
+	"gopkg.in/go-playground/validator.v9"
 )
 
 type App struct {
-	logger  logger.LoggerInterface
-	svcBook service.BookServiceInterface
+	logger        logger.LoggerInterface
+	validator     *validator.Validate
+	svcBook       service.BookServiceInterface
+	svcAuthor     service.AuthorServiceInterface
+	svcLabel      service.LabelServiceInterface
+	svcCollection service.CollectionServiceInterface
+	svcCover      service.CoverServiceInterface
+	svcApiKey     service.ApiKeyServiceInterface
+	svcHealth     service.HealthServiceInterface
 }
 
 func NewApp(
 	logger logger.LoggerInterface,
+	validator *validator.Validate,
 	svcBook service.BookServiceInterface,
+	svcAuthor service.AuthorServiceInterface,
+	svcLabel service.LabelServiceInterface,
+	svcCollection service.CollectionServiceInterface,
+	svcCover service.CoverServiceInterface,
+	svcApiKey service.ApiKeyServiceInterface,
+	svcHealth service.HealthServiceInterface,
 ) *App {
 	return &App{
-		logger:  logger,
-		svcBook: svcBook,
+		logger:        logger,
+		validator:     validator,
+		svcBook:       svcBook,
+		svcAuthor:     svcAuthor,
+		svcLabel:      svcLabel,
+		svcCollection: svcCollection,
+		svcCover:      svcCover,
+		svcApiKey:     svcApiKey,
+		svcHealth:     svcHealth,
 	}
 }
 
@@ -0,0 +1,128 @@
+package app
+
+import (
+	"fmt"
+	"myapp/model"
+	"myapp/util/apperror"
+	"net/http"
+)
+
+func (a *App) HandleListAuthors(w http.ResponseWriter, r *http.Request) {
+	query, err := model.NewPageForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	authors, err := a.svcAuthor.GetListAuthor(r.Context(), query)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, authors)
+}
+
+func (a *App) HandleCreateAuthor(w http.ResponseWriter, r *http.Request) {
+	authorForm := model.AuthorForm{}
+	if err := ParseRequestBody(w, r, a, &authorForm); err != nil {
+		return
+	}
+
+	if err := ValidateForm(w, r, a, &authorForm); err != nil {
+		return
+	}
+
+	author, err := a.svcAuthor.CreateAuthor(r.Context(), &authorForm)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data creation failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("New author created: %d", author.ID)
+	w.WriteHeader(http.StatusCreated)
+
+	RespondJSON(w, r, a, author)
+}
+
+func (a *App) HandleReadAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	author, err := a.svcAuthor.GetAuthorByID(r.Context(), id)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	RespondJSON(w, r, a, author)
+}
+
+func (a *App) HandleUpdateAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	authorForm := &model.AuthorForm{}
+	if err := ParseRequestBody(w, r, a, authorForm); err != nil {
+		return
+	}
+
+	if err := ValidateForm(w, r, a, authorForm); err != nil {
+		return
+	}
+
+	if _, err := a.svcAuthor.UpdateAuthor(r.Context(), id, authorForm); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Author updated: %d", id)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (a *App) HandleDeleteAuthor(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	if err := a.svcAuthor.DeleteAuthor(r.Context(), id); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Author deleted: %d", id)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// HandleListAuthorBooks lists the books of the author in id order.
+func (a *App) HandleListAuthorBooks(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	query, err := model.NewPageForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	books, err := a.svcAuthor.GetAuthorBooks(r.Context(), id, query)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, books)
+}
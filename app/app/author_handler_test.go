@@ -0,0 +1,188 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HandleAuthors(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		id          string
+		query       string
+		body        string
+		handler     func(a *app.App) http.HandlerFunc
+		statusCode  int
+		respBody    string
+		prepareMock func(mockSvc *mock_service.MockAuthorServiceInterface)
+	}{
+		{
+			name:       "list",
+			method:     "GET",
+			query:      "?limit=1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleListAuthors },
+			statusCode: http.StatusOK,
+			respBody:   `{"data":[{"id":1,"name":"J. R. R. Tolkien"}],"total":2,"limit":1,"offset":0}`,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().GetListAuthor(gomock.Any(), &model.PageQuery{Limit: 1}).
+					Return(&model.AuthorListDto{Data: []model.AuthorDto{{ID: 1, Name: "J. R. R. Tolkien"}}, Total: 2, Limit: 1}, nil)
+			},
+		},
+		{
+			name:       "create",
+			method:     "POST",
+			body:       `{"name":"J. R. R. Tolkien"}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateAuthor },
+			statusCode: http.StatusCreated,
+			respBody:   `{"id":1,"name":"J. R. R. Tolkien"}`,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().CreateAuthor(gomock.Any(), &model.AuthorForm{Name: "J. R. R. Tolkien"}).
+					Return(&model.AuthorDto{ID: 1, Name: "J. R. R. Tolkien"}, nil)
+			},
+		},
+		{
+			name:       "create without name",
+			method:     "POST",
+			body:       `{}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateAuthor },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "create existing",
+			method:     "POST",
+			body:       `{"name":"J. R. R. Tolkien"}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateAuthor },
+			statusCode: http.StatusConflict,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().CreateAuthor(gomock.Any(), gomock.Any()).Return(nil, apperror.Conflict("author already exists", nil))
+			},
+		},
+		{
+			name:       "read",
+			method:     "GET",
+			id:         "1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadAuthor },
+			statusCode: http.StatusOK,
+			respBody:   `{"id":1,"name":"J. R. R. Tolkien"}`,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().GetAuthorByID(gomock.Any(), uint(1)).Return(&model.AuthorDto{ID: 1, Name: "J. R. R. Tolkien"}, nil)
+			},
+		},
+		{
+			name:       "read unknown",
+			method:     "GET",
+			id:         "2",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadAuthor },
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().GetAuthorByID(gomock.Any(), uint(2)).Return(nil, apperror.NotFound("author not found", nil))
+			},
+		},
+		{
+			name:       "update",
+			method:     "PUT",
+			id:         "1",
+			body:       `{"name":"Tolkien"}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateAuthor },
+			statusCode: http.StatusAccepted,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().UpdateAuthor(gomock.Any(), uint(1), &model.AuthorForm{Name: "Tolkien"}).Return(&model.AuthorDto{ID: 1, Name: "Tolkien"}, nil)
+			},
+		},
+		{
+			name:       "delete",
+			method:     "DELETE",
+			id:         "1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteAuthor },
+			statusCode: http.StatusAccepted,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().DeleteAuthor(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+		{
+			name:       "delete author of books",
+			method:     "DELETE",
+			id:         "1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteAuthor },
+			statusCode: http.StatusConflict,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().DeleteAuthor(gomock.Any(), uint(1)).Return(apperror.Conflict("author still has books", nil))
+			},
+		},
+		{
+			name:       "books",
+			method:     "GET",
+			id:         "1",
+			query:      "?limit=10",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleListAuthorBooks },
+			statusCode: http.StatusOK,
+			respBody:   `{"data":[{"id":3,"title":"The Hobbit","author":"J. R. R. Tolkien","published_date":"1937-09-21","image_url":"","description":"","author_ids":[1]}],"total":1,"limit":10,"offset":0}`,
+			prepareMock: func(mockSvc *mock_service.MockAuthorServiceInterface) {
+				mockSvc.EXPECT().GetAuthorBooks(gomock.Any(), uint(1), &model.PageQuery{Limit: 10}).
+					Return(&model.AuthorBookListDto{
+						Data:  []model.BookDto{{ID: 3, Title: "The Hobbit", Author: "J. R. R. Tolkien", PublishedDate: "1937-09-21", AuthorIDs: []uint{1}}},
+						Total: 1,
+						Limit: 10,
+					}, nil)
+			},
+		},
+		{
+			name:       "books invalid id",
+			method:     "GET",
+			id:         "invalid",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleListAuthorBooks },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockAuthorService := mock_service.NewMockAuthorServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockAuthorService)
+			}
+
+			req, err := http.NewRequest(tt.method, "api/v1/authors/"+tt.id+tt.query, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mockAuthorService, mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			tt.handler(a).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.respBody != "" {
+				assert.JSONEq(t, tt.respBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"myapp/model"
+	"net/http"
+)
+
+// BookBatchDto is the response of a batch, with one result per operation in
+// the order of the request.
+type BookBatchDto struct {
+	Mode      model.BookBatchMode `json:"mode"`
+	Committed bool                `json:"committed"`
+	Results   []BookOperationDto  `json:"results"`
+}
+
+type BookOperationDto struct {
+	Index  int                     `json:"index"`
+	Op     model.BookOperationType `json:"op"`
+	Status int                     `json:"status"`
+	ID     uint                    `json:"id,omitempty"`
+	Book   *model.BookDto          `json:"book,omitempty"`
+	Error  *Problem                `json:"error,omitempty"`
+}
+
+// operationStatus is the status of each successful operation, as if it had
+// been a request of its own.
+var operationStatus = map[model.BookOperationType]int{
+	model.BookOperationCreate: http.StatusCreated,
+	model.BookOperationUpdate: http.StatusOK,
+	model.BookOperationDelete: http.StatusNoContent,
+}
+
+func (a *App) HandleBatchBooks(w http.ResponseWriter, r *http.Request) {
+	batch := &model.BookBatchForm{}
+	if err := ParseRequestBody(w, r, a, batch); err != nil {
+		return
+	}
+
+	result, err := a.svcBook.BatchBooks(r.Context(), batch)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("batch failure: %w", err))
+		return
+	}
+
+	resp := &BookBatchDto{
+		Mode:      result.Mode,
+		Committed: result.Committed,
+		Results:   make([]BookOperationDto, 0, len(result.Results)),
+	}
+
+	failed := 0
+	for i, res := range result.Results {
+		dto := BookOperationDto{
+			Index:  i,
+			Op:     res.Op,
+			Status: operationStatus[res.Op],
+			ID:     res.ID,
+			Book:   res.Book,
+		}
+
+		if res.Err != nil {
+			failed++
+			dto.Error = NewProblem(r, res.Err)
+			dto.Error.Instance = ""
+			dto.Status = dto.Error.Status
+		}
+
+		resp.Results = append(resp.Results, dto)
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Book batch applied: %d operations, %d failed, committed %t", len(resp.Results), failed, resp.Committed)
+	RespondJSON(w, r, a, resp)
+}
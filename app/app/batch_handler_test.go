@@ -0,0 +1,108 @@
+package app_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HandleBatchBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		jsonStr     []byte
+		statusCode  int
+		body        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:       "committed",
+			jsonStr:    []byte(`{"operations":[{"op":"create","book":{"title":"title","author":"author","published_date":"2006-01-02","image_url":"https://example.com/cover.jpg"}},{"op":"delete","id":3}]}`),
+			statusCode: http.StatusOK,
+			body:       `{"mode":"atomic","committed":true,"results":[{"index":0,"op":"create","status":201,"id":10,"book":{"id":10,"title":"title","author":"author","published_date":"2006-01-02","image_url":"https://example.com/cover.jpg","description":""}},{"index":1,"op":"delete","status":204,"id":3}]}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().BatchBooks(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ interface{}, batch *model.BookBatchForm) (*model.BookBatchResult, error) {
+						assert.Len(t, batch.Operations, 2)
+						assert.Equal(t, uint(3), batch.Operations[1].ID)
+
+						return &model.BookBatchResult{
+							Mode:      model.BookBatchAtomic,
+							Committed: true,
+							Results: []model.BookOperationResult{
+								{Op: model.BookOperationCreate, ID: 10, Book: &model.BookDto{ID: 10, Title: "title", Author: "author", PublishedDate: "2006-01-02", ImageUrl: "https://example.com/cover.jpg"}},
+								{Op: model.BookOperationDelete, ID: 3},
+							},
+						}, nil
+					})
+			},
+		},
+		{
+			name:       "partial failure",
+			jsonStr:    []byte(`{"mode":"best_effort","operations":[{"op":"delete","id":3},{"op":"update","id":4,"version":2,"book":{}}]}`),
+			statusCode: http.StatusOK,
+			body:       `{"mode":"best_effort","committed":true,"results":[{"index":0,"op":"delete","status":204,"id":3},{"index":1,"op":"update","status":412,"id":4,"error":{"type":"urn:myapp:problem:precondition_failed","title":"Precondition failed","status":412,"detail":"book has been modified","code":"precondition_failed"}}]}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().BatchBooks(gomock.Any(), gomock.Any()).
+					Return(&model.BookBatchResult{
+						Mode:      model.BookBatchBestEffort,
+						Committed: true,
+						Results: []model.BookOperationResult{
+							{Op: model.BookOperationDelete, ID: 3},
+							{Op: model.BookOperationUpdate, ID: 4, Err: apperror.PreconditionFailed("book has been modified", nil)},
+						},
+					}, nil)
+			},
+		},
+		{
+			name:       "invalid batch",
+			jsonStr:    []byte(`{"operations":[]}`),
+			statusCode: http.StatusUnprocessableEntity,
+			body:       `{"type":"urn:myapp:problem:validation_failed","title":"Your request parameters didn't validate","status":422,"detail":"batch has no operations","instance":"api/v1/books:batch","code":"validation_failed"}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().BatchBooks(gomock.Any(), gomock.Any()).
+					Return(nil, apperror.Validation("batch has no operations", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest("POST", "api/v1/books:batch", bytes.NewBuffer(tt.jsonStr))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandleBatchBooks)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.JSONEq(t, tt.body, rr.Body.String())
+		})
+	}
+}
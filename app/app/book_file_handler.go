@@ -0,0 +1,169 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"myapp/model"
+	"myapp/util/apperror"
+	"net/http"
+	"strconv"
+)
+
+// maxImportSize bounds import files, which are read whole before the import.
+const maxImportSize = 32 << 20
+
+var errImportTooLarge = fmt.Errorf("import file is larger than %d bytes", maxImportSize)
+
+// importReader fails with errImportTooLarge once more than maxImportSize bytes are read.
+type importReader struct {
+	r io.Reader
+	n int64
+}
+
+func (ir *importReader) Read(p []byte) (int, error) {
+	n, err := ir.r.Read(p)
+	ir.n += int64(n)
+	if ir.n > maxImportSize {
+		return n, errImportTooLarge
+	}
+
+	return n, err
+}
+
+// fileFormat returns the format named by the format query parameter, CSV by default.
+func fileFormat(r *http.Request) (model.BookFileFormat, error) {
+	format := model.BookFileFormat(r.URL.Query().Get("format"))
+	if format == "" {
+		return model.BookFileCSV, nil
+	}
+	if _, ok := model.BookFileMediaTypes[format]; !ok {
+		return "", apperror.Validation("invalid format", nil, apperror.FieldError{Name: "format", Reason: "must be one of csv, jsonl"})
+	}
+
+	return format, nil
+}
+
+// uploadFormat returns the format of the request body from its Content-Type.
+func uploadFormat(r *http.Request) (model.BookFileFormat, bool) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", false
+	}
+
+	for f, t := range model.BookFileMediaTypes {
+		if mediaType == t {
+			return f, true
+		}
+	}
+
+	return "", false
+}
+
+// HandleExportBooks streams the whole catalog as CSV or JSON Lines. Errors
+// after the first book is written abort the response, as the status has
+// already been sent.
+func (a *App) HandleExportBooks(w http.ResponseWriter, r *http.Request) {
+	format, err := fileFormat(r)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	bw := model.NewBookWriter(format, w)
+	started := false
+	count := 0
+	err = a.svcBook.ExportBooks(r.Context(), func(book *model.BookDto) error {
+		if !started {
+			setExportHeaders(w, format)
+			started = true
+		}
+
+		count++
+		return bw.Write(book)
+	})
+	if err == nil {
+		if !started {
+			setExportHeaders(w, format)
+		}
+		err = bw.Flush()
+	}
+
+	if err != nil {
+		if !started {
+			RespondError(w, r, a, fmt.Errorf("data export failure: %w", err))
+			return
+		}
+
+		a.logger.WithContext(r.Context()).Warn().Err(err).Msgf("Book export aborted after %d books", count)
+		panic(http.ErrAbortHandler)
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Books exported: %d", count)
+}
+
+func setExportHeaders(w http.ResponseWriter, format model.BookFileFormat) {
+	w.Header().Set("Content-Type", model.BookFileMediaTypes[format]+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="books.%s"`, format))
+	w.WriteHeader(http.StatusOK)
+}
+
+// HandleImportBooks creates the books of a CSV or JSON Lines file, sent as the
+// request body. With dry_run=true the file is only validated.
+func (a *App) HandleImportBooks(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			RespondError(w, r, a, apperror.Validation("invalid dry_run", err, apperror.FieldError{Name: "dry_run", Reason: "must be a boolean"}))
+			return
+		}
+	}
+
+	format, ok := uploadFormat(r)
+	if !ok {
+		WriteProblem(w, r, a, NewStatusProblem(r, http.StatusUnsupportedMediaType))
+		return
+	}
+
+	rows, err := readImportRows(model.NewBookReader(format, &importReader{r: r.Body}))
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	result, err := a.svcBook.ImportBooks(r.Context(), rows, dryRun)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data import failure: %w", err))
+		return
+	}
+
+	status := http.StatusOK
+	if !result.DryRun {
+		a.logger.WithContext(r.Context()).Info().Msgf("Books imported: %d", result.Imported)
+		status = http.StatusCreated
+	}
+
+	w.WriteHeader(status)
+	RespondJSON(w, r, a, result)
+}
+
+func readImportRows(br model.BookReader) ([]*model.BookImportRow, error) {
+	var rows []*model.BookImportRow
+	for {
+		row, err := br.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		if errors.Is(err, errImportTooLarge) {
+			return nil, apperror.Validation(errImportTooLarge.Error(), nil)
+		}
+		if err != nil {
+			return nil, apperror.Validation("invalid import file", err)
+		}
+
+		rows = append(rows, row)
+	}
+}
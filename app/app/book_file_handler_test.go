@@ -0,0 +1,273 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HandleExportBooks(t *testing.T) {
+	books := []*model.BookDto{
+		{ID: 1, Title: "title", Author: "author", PublishedDate: "2006-01-02", Description: "with, comma"},
+		{ID: 2, Title: "other", Author: "author", PublishedDate: "2007-01-02", ImageUrl: "https://example.com/cover.jpg", ISBN13: "9780306406157", ISBN10: "0306406152"},
+	}
+	export := func(ctx context.Context, fn func(*model.BookDto) error) error {
+		for _, b := range books {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		statusCode  int
+		contentType string
+		body        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:        "csv",
+			statusCode:  http.StatusOK,
+			contentType: "text/csv; charset=utf-8",
+			body:        "id,title,author,published_date,image_url,description,isbn\n1,title,author,2006-01-02,,\"with, comma\",\n2,other,author,2007-01-02,https://example.com/cover.jpg,,9780306406157\n",
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ExportBooks(gomock.Any(), gomock.Any()).DoAndReturn(export)
+			},
+		},
+		{
+			name:        "jsonl",
+			query:       "?format=jsonl",
+			statusCode:  http.StatusOK,
+			contentType: "application/x-ndjson; charset=utf-8",
+			body:        `{"id":1,"title":"title","author":"author","published_date":"2006-01-02","image_url":"","description":"with, comma"}` + "\n" + `{"id":2,"title":"other","author":"author","published_date":"2007-01-02","image_url":"https://example.com/cover.jpg","description":"","isbn_13":"9780306406157","isbn_10":"0306406152"}` + "\n",
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ExportBooks(gomock.Any(), gomock.Any()).DoAndReturn(export)
+			},
+		},
+		{
+			name:        "empty csv",
+			statusCode:  http.StatusOK,
+			contentType: "text/csv; charset=utf-8",
+			body:        "id,title,author,published_date,image_url,description,isbn\n",
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ExportBooks(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:        "invalid format",
+			query:       "?format=xlsx",
+			statusCode:  http.StatusUnprocessableEntity,
+			contentType: app.ProblemContentType,
+			body:        `{"type":"urn:myapp:problem:validation_failed","title":"Your request parameters didn't validate","status":422,"detail":"invalid format","instance":"api/v1/books/export?format=xlsx","code":"validation_failed","invalid_params":[{"name":"format","reason":"must be one of csv, jsonl"}]}` + "\n",
+		},
+		{
+			name:        "failure before the first book",
+			statusCode:  http.StatusInternalServerError,
+			contentType: app.ProblemContentType,
+			body:        `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"api/v1/books/export","code":"internal"}` + "\n",
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ExportBooks(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest("GET", "api/v1/books/export"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandleExportBooks)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.Equal(t, tt.contentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tt.body, rr.Body.String())
+		})
+	}
+}
+
+func TestApp_HandleExportBooks_AbortsAfterFirstBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Warn().AnyTimes()
+
+	mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+	mockBookService.EXPECT().ExportBooks(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(*model.BookDto) error) error {
+		if err := fn(&model.BookDto{ID: 1}); err != nil {
+			return err
+		}
+		return apperror.Timeout("query timed out", nil)
+	})
+
+	req, err := http.NewRequest("GET", "api/v1/books/export", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+
+	a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		a.HandleExportBooks(rr, req)
+	})
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestApp_HandleImportBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		file        string
+		statusCode  int
+		body        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:        "csv",
+			contentType: "text/csv",
+			file:        "\ufeffTitle,author,published_date\r\n\"title, with comma\",author,2006-01-02\r\nother,author\r\n",
+			statusCode:  http.StatusCreated,
+			body:        `{"dry_run":false,"rows":2,"imported":1}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), false).
+					DoAndReturn(func(ctx context.Context, rows []*model.BookImportRow, dryRun bool) (*model.BookImportResult, error) {
+						assert.Len(t, rows, 2)
+						assert.Equal(t, &model.BookImportRow{Line: 2, Form: &model.BookForm{Title: "title, with comma", Author: "author", PublishedDate: "2006-01-02"}}, rows[0])
+						assert.Equal(t, 3, rows[1].Line)
+						assert.EqualError(t, rows[1].Err, "has 2 fields, expected 3")
+
+						return &model.BookImportResult{Rows: 2, Imported: 1}, nil
+					})
+			},
+		},
+		{
+			name:        "csv with unknown column",
+			contentType: "text/csv; charset=utf-8",
+			file:        "title,author,published_date,publisher\ntitle,author,2006-01-02,Penguin\n",
+			statusCode:  http.StatusCreated,
+			body:        `{"dry_run":false,"rows":1,"imported":0}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), false).
+					DoAndReturn(func(ctx context.Context, rows []*model.BookImportRow, dryRun bool) (*model.BookImportResult, error) {
+						assert.Len(t, rows, 1)
+						assert.Equal(t, 1, rows[0].Line)
+						assert.EqualError(t, rows[0].Err, `unknown column "publisher"`)
+
+						return &model.BookImportResult{Rows: 1}, nil
+					})
+			},
+		},
+		{
+			name:        "jsonl dry run",
+			query:       "?dry_run=true",
+			contentType: "application/x-ndjson",
+			file:        `{"title":"title","author":"author","published_date":"2006-01-02"}` + "\n\n" + `{"title":"title","publisher":"Penguin"}` + "\n",
+			statusCode:  http.StatusOK,
+			body:        `{"dry_run":true,"rows":2,"imported":0}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), true).
+					DoAndReturn(func(ctx context.Context, rows []*model.BookImportRow, dryRun bool) (*model.BookImportResult, error) {
+						assert.Len(t, rows, 2)
+						assert.Equal(t, 1, rows[0].Line)
+						assert.Equal(t, "title", rows[0].Form.Title)
+						assert.Equal(t, 3, rows[1].Line)
+						assert.EqualError(t, rows[1].Err, `json: unknown field "publisher"`)
+
+						return &model.BookImportResult{DryRun: true, Rows: 2}, nil
+					})
+			},
+		},
+		{
+			name:        "invalid rows",
+			contentType: "application/x-ndjson",
+			file:        `{"author":"author","published_date":"2006-01-02"}` + "\n",
+			statusCode:  http.StatusUnprocessableEntity,
+			body:        `{"type":"urn:myapp:problem:validation_failed","title":"Your request parameters didn't validate","status":422,"detail":"1 of 1 rows are invalid","instance":"api/v1/books/import","code":"validation_failed","invalid_params":[{"name":"title","reason":"is a required field","line":1}]}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ImportBooks(gomock.Any(), gomock.Any(), false).
+					Return(nil, apperror.Validation("1 of 1 rows are invalid", nil, apperror.FieldError{Name: "title", Reason: "is a required field", Line: 1}))
+			},
+		},
+		{
+			name:        "unsupported media type",
+			contentType: "application/json",
+			file:        `[]`,
+			statusCode:  http.StatusUnsupportedMediaType,
+			body:        `{"type":"about:blank","title":"Unsupported Media Type","status":415,"instance":"api/v1/books/import"}`,
+		},
+		{
+			name:        "invalid dry run",
+			query:       "?dry_run=maybe",
+			contentType: "text/csv",
+			statusCode:  http.StatusUnprocessableEntity,
+			body:        `{"type":"urn:myapp:problem:validation_failed","title":"Your request parameters didn't validate","status":422,"detail":"invalid dry_run","instance":"api/v1/books/import?dry_run=maybe","code":"validation_failed","invalid_params":[{"name":"dry_run","reason":"must be a boolean"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest("POST", "api/v1/books/import"+tt.query, bytes.NewBufferString(tt.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", tt.contentType)
+			rr := httptest.NewRecorder()
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandleImportBooks)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.JSONEq(t, tt.body, rr.Body.String())
+		})
+	}
+}
@@ -2,98 +2,257 @@ package app
 
 import (
 	"fmt"
+	"io"
+	"mime"
 	"myapp/model"
+	"myapp/util/apperror"
 	"net/http"
+	"strings"
 
-	"github.com/jinzhu/gorm"
+	"github.com/go-chi/chi"
 )
 
+// maxPatchSize bounds PATCH documents; a book is far smaller.
+const maxPatchSize = 1 << 20
+
+// acceptPatch is the Accept-Patch header value advertising the patch formats.
+var acceptPatch = func() string {
+	formats := make([]string, 0, len(model.PatchFormats))
+	for _, f := range model.PatchFormats {
+		formats = append(formats, string(f))
+	}
+	return strings.Join(formats, ", ")
+}()
+
+func patchFormat(r *http.Request) (model.PatchFormat, bool) {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return "", false
+	}
+
+	for _, f := range model.PatchFormats {
+		if mediaType == string(f) {
+			return f, true
+		}
+	}
+
+	return "", false
+}
+
 func (a *App) HandleListBooks(w http.ResponseWriter, r *http.Request) {
-	books, err := a.svcBook.GetListBook(r.Context())
+	query, err := model.NewBookListForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	books, err := a.svcBook.GetListBook(r.Context(), query)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	books.Links = pageLinks(r, query, books)
+
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, books)
+}
+
+func (a *App) HandleSearchBooks(w http.ResponseWriter, r *http.Request) {
+	query, err := model.NewBookSearchForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	books, err := a.svcBook.SearchBooks(r.Context(), query)
 	if err != nil {
-		RespondError(w, a, fmt.Errorf("data access failure: %w", err), http.StatusInternalServerError)
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	RespondJSON(w, r, a, &books, http.StatusInternalServerError)
+	RespondJSON(w, r, a, books)
 }
 
 func (a *App) HandleCreateBook(w http.ResponseWriter, r *http.Request) {
 	bookForm := model.BookForm{}
-	ParseRequestBody(w, r, a, &bookForm, http.StatusUnprocessableEntity)
+	if err := ParseRequestBody(w, r, a, &bookForm); err != nil {
+		return
+	}
+
+	if err := ValidateForm(w, r, a, &bookForm); err != nil {
+		return
+	}
 
 	book, err := a.svcBook.CreateBook(r.Context(), &bookForm)
 	if err != nil {
-		RespondError(w, a, fmt.Errorf("data creation failure: %w", err), http.StatusInternalServerError)
+		RespondError(w, r, a, fmt.Errorf("data creation failure: %w", err))
 		return
 	}
 
-	a.logger.Info().Msgf("New book created: %d", book.ID)
+	a.logger.WithContext(r.Context()).Info().Msgf("New book created: %d", book.ID)
+	w.Header().Set("ETag", ETag(book.Version))
 	w.WriteHeader(http.StatusCreated)
 
-	RespondJSON(w, r, a, book, http.StatusInternalServerError)
+	RespondJSON(w, r, a, book)
 }
 
 func (a *App) HandleReadBook(w http.ResponseWriter, r *http.Request) {
 	id, err := ParseUint(w, r, a)
 	if err != nil {
-		RespondError(w, a, fmt.Errorf("id request failure: %w", err), http.StatusUnprocessableEntity)
+		RespondError(w, r, a, err)
 		return
 	}
 
 	book, err := a.svcBook.GetBookByID(r.Context(), id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		RespondError(w, a, fmt.Errorf("data access failure: %w", err), http.StatusInternalServerError)
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	etag := ETag(book.Version)
+	w.Header().Set("ETag", etag)
+	if IfNoneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
 		return
 	}
 
-	RespondJSON(w, r, a, &book, http.StatusInternalServerError)
+	RespondJSON(w, r, a, &book)
+}
+
+// HandleReadBookByISBN returns the book with the ISBN-10 or ISBN-13 of the path.
+func (a *App) HandleReadBookByISBN(w http.ResponseWriter, r *http.Request) {
+	book, err := a.svcBook.GetBookByISBN(r.Context(), chi.URLParam(r, "isbn"))
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	etag := ETag(book.Version)
+	w.Header().Set("ETag", etag)
+	if IfNoneMatch(r, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	RespondJSON(w, r, a, &book)
 }
 
 func (a *App) HandleUpdateBook(w http.ResponseWriter, r *http.Request) {
 	id, err := ParseUint(w, r, a)
 	if err != nil {
-		RespondError(w, a, fmt.Errorf("id request failure: %w", err), http.StatusUnprocessableEntity)
+		RespondError(w, r, a, err)
 		return
 	}
 
 	bookForm := &model.BookForm{}
-	ParseRequestBody(w, r, a, &bookForm, http.StatusUnprocessableEntity)
+	if err := ParseRequestBody(w, r, a, bookForm); err != nil {
+		return
+	}
 
-	if err := a.svcBook.UpdateBook(r.Context(), id, bookForm); err != nil {
-		if err == gorm.ErrRecordNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		RespondError(w, a, fmt.Errorf("data update failure: %w", err), http.StatusInternalServerError)
+	if err := ValidateForm(w, r, a, bookForm); err != nil {
 		return
 	}
 
-	a.logger.Info().Msgf("Book updated: %d", id)
+	versions, err := IfMatchVersions(r)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	book, err := a.svcBook.UpdateBook(r.Context(), id, bookForm, versions)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Book updated: %d", id)
+	w.Header().Set("ETag", ETag(book.Version))
 	w.WriteHeader(http.StatusAccepted)
 }
 
+// HandlePatchBook applies a JSON Merge Patch or a JSON Patch, depending on the
+// Content-Type, to the book and responds with the patched book.
+func (a *App) HandlePatchBook(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	versions, err := IfMatchVersions(r)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	format, ok := patchFormat(r)
+	if !ok {
+		w.Header().Set("Accept-Patch", acceptPatch)
+		WriteProblem(w, r, a, NewStatusProblem(r, http.StatusUnsupportedMediaType))
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPatchSize))
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation("invalid request body", err))
+		return
+	}
+
+	book, err := a.svcBook.PatchBook(r.Context(), id, versions, format, patch)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data patch failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Book patched: %d", id)
+	w.Header().Set("ETag", ETag(book.Version))
+	RespondJSON(w, r, a, book)
+}
+
 func (a *App) HandleDeleteBook(w http.ResponseWriter, r *http.Request) {
 	id, err := ParseUint(w, r, a)
 	if err != nil {
-		RespondError(w, a, fmt.Errorf("id request failure: %w", err), http.StatusUnprocessableEntity)
+		RespondError(w, r, a, err)
 		return
 	}
 
-	if err := a.svcBook.DeleteBook(r.Context(), id); err != nil {
-		if err == gorm.ErrRecordNotFound {
-			w.WriteHeader(http.StatusNotFound)
-			return
-		}
-		RespondError(w, a, fmt.Errorf("data access failure: %w", err), http.StatusInternalServerError)
+	versions, err := IfMatchVersions(r)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	if err := a.svcBook.DeleteBook(r.Context(), id, versions); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
 		return
 	}
 
-	a.logger.Info().Msgf("Book deleted: %d", id)
+	a.logger.WithContext(r.Context()).Info().Msgf("Book deleted: %d", id)
 	w.WriteHeader(http.StatusAccepted)
 }
+
+// HandleBookHistory lists the changes of the book, most recent first.
+func (a *App) HandleBookHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	query, err := model.NewPageForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	history, err := a.svcBook.GetBookHistory(r.Context(), id, query)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, history)
+}
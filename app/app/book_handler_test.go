@@ -9,15 +9,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"myapp/app/app"
 	mock_service "myapp/mocks/service"
 	mock_logger "myapp/mocks/util/logger"
 	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
-	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,6 +27,7 @@ func TestApp_HandleCreateBook(t *testing.T) {
 	type args struct {
 		jsonStr []byte
 		book    *model.BookDto
+		errors  []string
 	}
 	tests := []struct {
 		name        string
@@ -36,7 +39,7 @@ func TestApp_HandleCreateBook(t *testing.T) {
 		{
 			name: "success call",
 			args: args{
-				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"image_url", "description":"description"}`),
+				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`),
 				book:    mockBookDto(),
 			},
 			wantErr:    false,
@@ -48,7 +51,7 @@ func TestApp_HandleCreateBook(t *testing.T) {
 		{
 			name: "server error",
 			args: args{
-				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"image_url", "description":"description"}`),
+				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`),
 			},
 			wantErr:    true,
 			statusCode: http.StatusInternalServerError,
@@ -56,6 +59,31 @@ func TestApp_HandleCreateBook(t *testing.T) {
 				mockSvc.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(nil, errors.New("data creation failure")).AnyTimes()
 			},
 		},
+		{
+			name: "conflict",
+			args: args{
+				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`),
+			},
+			wantErr:    true,
+			statusCode: http.StatusConflict,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(nil, apperror.Conflict("book already exists", nil)).AnyTimes()
+			},
+		},
+		{
+			name: "validation failure",
+			args: args{
+				jsonStr: []byte(`{"title":"", "author":"author", "published_date":"2006-02-30", "image_url":"not a url", "isbn":"0-306-40615-3"}`),
+				errors: []string{
+					`{"name":"title","reason":"is a required field"}`,
+					`{"name":"published_date","reason":"must be a valid date in YYYY-MM-DD format, not before 1000-01-01"}`,
+					`{"name":"image_url","reason":"must be a valid URL"}`,
+					`{"name":"isbn","reason":"must be a valid ISBN-10 or ISBN-13"}`,
+				},
+			},
+			wantErr:    true,
+			statusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name: "bad request",
 			args: args{
@@ -73,6 +101,7 @@ func TestApp_HandleCreateBook(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
 			mockLogger.EXPECT().Info().AnyTimes()
 			mockLogger.EXPECT().Warn().AnyTimes()
 
@@ -88,7 +117,7 @@ func TestApp_HandleCreateBook(t *testing.T) {
 			}
 			rr := httptest.NewRecorder()
 
-			a := app.NewApp(mockLogger, mockBookService)
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
 
 			handler := http.HandlerFunc(a.HandleCreateBook)
 			handler.ServeHTTP(rr, req)
@@ -104,8 +133,15 @@ func TestApp_HandleCreateBook(t *testing.T) {
 				str2 := bytes.NewBuffer(rr.Body.Bytes()).String()
 
 				assert.Contains(t, str2, str1)
+			case http.StatusConflict:
+				assert.Equal(t, rr.Code, tt.statusCode)
 			case http.StatusUnprocessableEntity:
 				assert.Equal(t, rr.Code, tt.statusCode)
+				assert.Equal(t, app.ProblemContentType, rr.Header().Get("Content-Type"))
+
+				for _, msg := range tt.args.errors {
+					assert.Contains(t, rr.Body.String(), msg)
+				}
 			case http.StatusInternalServerError:
 				assert.Equal(t, rr.Code, tt.statusCode)
 			default:
@@ -162,7 +198,7 @@ func TestApp_HandleReadBook(t *testing.T) {
 			wantErr:    true,
 			statusCode: http.StatusNotFound,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+				mockSvc.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).Return(nil, apperror.NotFound("book not found", nil)).AnyTimes()
 			},
 		},
 		{
@@ -181,7 +217,29 @@ func TestApp_HandleReadBook(t *testing.T) {
 			wantErr:    true,
 			statusCode: http.StatusInternalServerError,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).Return(nil, errors.New("data access failure")).AnyTimes()
+				mockSvc.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).Return(nil, errors.New(`data "access" failure`)).AnyTimes()
+			},
+		},
+		{
+			name: "client disconnected",
+			args: args{
+				id: "1",
+			},
+			wantErr:    true,
+			statusCode: app.StatusClientClosedRequest,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).Return(nil, apperror.Canceled("request canceled while querying book", context.Canceled)).AnyTimes()
+			},
+		},
+		{
+			name: "query timeout",
+			args: args{
+				id: "1",
+			},
+			wantErr:    true,
+			statusCode: http.StatusServiceUnavailable,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByID(gomock.Any(), gomock.Any()).Return(nil, apperror.Timeout("query on book timed out", context.DeadlineExceeded)).AnyTimes()
 			},
 		},
 	}
@@ -190,6 +248,7 @@ func TestApp_HandleReadBook(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
 			mockLogger.EXPECT().Info().AnyTimes()
 			mockLogger.EXPECT().Warn().AnyTimes()
 
@@ -211,7 +270,7 @@ func TestApp_HandleReadBook(t *testing.T) {
 
 			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
-			a := app.NewApp(mockLogger, mockBookService)
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
 
 			handler := http.HandlerFunc(a.HandleReadBook)
 			handler.ServeHTTP(rr, req)
@@ -229,10 +288,91 @@ func TestApp_HandleReadBook(t *testing.T) {
 				assert.Contains(t, str2, str1)
 			case http.StatusNotFound:
 				assert.Equal(t, rr.Code, tt.statusCode)
+				assert.Equal(t, app.ProblemContentType, rr.Header().Get("Content-Type"))
+				assert.JSONEq(t, `{"type":"urn:myapp:problem:not_found","title":"Resource not found","status":404,"detail":"book not found","instance":"api/v1/books/2","code":"not_found"}`, rr.Body.String())
 			case http.StatusUnprocessableEntity:
 				assert.Equal(t, rr.Code, tt.statusCode)
 			case http.StatusInternalServerError:
 				assert.Equal(t, rr.Code, tt.statusCode)
+				assert.JSONEq(t, `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"api/v1/books/1","code":"internal"}`, rr.Body.String())
+			case app.StatusClientClosedRequest:
+				assert.Equal(t, rr.Code, tt.statusCode)
+				assert.JSONEq(t, `{"type":"urn:myapp:problem:canceled","title":"Client closed request","status":499,"detail":"request canceled while querying book","instance":"api/v1/books/1","code":"canceled"}`, rr.Body.String())
+			case http.StatusServiceUnavailable:
+				assert.Equal(t, rr.Code, tt.statusCode)
+				assert.JSONEq(t, `{"type":"urn:myapp:problem:timeout","title":"Request timed out","status":503,"detail":"query on book timed out","instance":"api/v1/books/1","code":"timeout"}`, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestApp_HandleReadBookByISBN(t *testing.T) {
+	tests := []struct {
+		name        string
+		isbn        string
+		statusCode  int
+		respBody    string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:       "success call",
+			isbn:       "0-306-40615-2",
+			statusCode: http.StatusOK,
+			respBody:   `{"id":1,"title":"title","author":"author","published_date":"2006-01-02","image_url":"","description":"","isbn_13":"9780306406157","isbn_10":"0306406152"}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByISBN(gomock.Any(), "0-306-40615-2").
+					Return(&model.BookDto{ID: 1, Title: "title", Author: "author", PublishedDate: "2006-01-02", ISBN13: "9780306406157", ISBN10: "0306406152", Version: 2}, nil)
+			},
+		},
+		{
+			name:       "unknown isbn",
+			isbn:       "9780306406157",
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByISBN(gomock.Any(), "9780306406157").Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+		{
+			name:       "invalid isbn",
+			isbn:       "123",
+			statusCode: http.StatusUnprocessableEntity,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByISBN(gomock.Any(), "123").
+					Return(nil, apperror.Validation("invalid ISBN", nil, apperror.FieldError{Name: "isbn", Reason: "must be a valid ISBN-10 or ISBN-13"}))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+			tt.prepareMock(mockBookService)
+
+			req, err := http.NewRequest("GET", "api/v1/books/isbn/"+tt.isbn, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("isbn", tt.isbn)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			a.HandleReadBookByISBN(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.respBody != "" {
+				assert.JSONEq(t, tt.respBody, rr.Body.String())
+				assert.Equal(t, app.ETag(2), rr.Header().Get("ETag"))
 			}
 		})
 	}
@@ -255,16 +395,20 @@ func getBody(b *model.BookDto) ([]byte, error) {
 	return book, nil
 }
 
-func mockListBookDto() []model.BookDto {
-	books := []model.BookDto{
-		{
-			ID:            1,
-			Title:         "title",
-			Author:        "author",
-			PublishedDate: "2006-01-02",
-			ImageUrl:      "image_url",
-			Description:   "description",
+func mockListBookDto() *model.BookListDto {
+	books := &model.BookListDto{
+		Data: []model.BookDto{
+			{
+				ID:            1,
+				Title:         "title",
+				Author:        "author",
+				PublishedDate: "2006-01-02",
+				ImageUrl:      "image_url",
+				Description:   "description",
+			},
 		},
+		Total: 1,
+		Limit: model.DefaultBookListLimit,
 	}
 
 	return books
@@ -281,7 +425,8 @@ func getListBody(b []model.BookDto) ([]byte, error) {
 
 func TestApp_ListBooks(t *testing.T) {
 	type args struct {
-		books []model.BookDto
+		query string
+		books *model.BookListDto
 	}
 	tests := []struct {
 		name        string
@@ -293,20 +438,29 @@ func TestApp_ListBooks(t *testing.T) {
 		{
 			name: "success call",
 			args: args{
+				query: "?sort=title,-published_date&author=author",
 				books: mockListBookDto(),
 			},
 			wantErr:    false,
 			statusCode: http.StatusOK,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().GetListBook(gomock.Any()).Return(mockListBookDto(), nil).AnyTimes()
+				mockSvc.EXPECT().GetListBook(gomock.Any(), gomock.Any()).Return(mockListBookDto(), nil).AnyTimes()
 			},
 		},
+		{
+			name: "invalid query",
+			args: args{
+				query: "?sort=unknown",
+			},
+			wantErr:    true,
+			statusCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name:       "server error",
 			wantErr:    true,
 			statusCode: http.StatusInternalServerError,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().GetListBook(gomock.Any()).Return(nil, errors.New("data access failure")).AnyTimes()
+				mockSvc.EXPECT().GetListBook(gomock.Any(), gomock.Any()).Return(nil, errors.New("data access failure")).AnyTimes()
 			},
 		},
 	}
@@ -315,6 +469,7 @@ func TestApp_ListBooks(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
 			mockLogger.EXPECT().Info().AnyTimes()
 			mockLogger.EXPECT().Warn().AnyTimes()
 
@@ -324,13 +479,13 @@ func TestApp_ListBooks(t *testing.T) {
 				tt.prepareMock(mockBookService)
 			}
 
-			req, err := http.NewRequest("GET", "api/v1/books", nil)
+			req, err := http.NewRequest("GET", "api/v1/books"+tt.args.query, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
 			rr := httptest.NewRecorder()
 
-			a := app.NewApp(mockLogger, mockBookService)
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
 
 			handler := http.HandlerFunc(a.HandleListBooks)
 			handler.ServeHTTP(rr, req)
@@ -339,15 +494,18 @@ func TestApp_ListBooks(t *testing.T) {
 			case http.StatusOK:
 				assert.Equal(t, rr.Code, tt.statusCode)
 
-				b, err := getListBody(tt.args.books)
+				b, err := getListBody(tt.args.books.Data)
 				assert.NoError(t, err)
 
 				str1 := bytes.NewBuffer(b).String()
 				str2 := bytes.NewBuffer(rr.Body.Bytes()).String()
 
 				assert.Contains(t, str2, str1)
+				assert.Contains(t, str2, `"total":1`)
 			case http.StatusNotFound:
 				assert.Equal(t, rr.Code, tt.statusCode)
+			case http.StatusUnprocessableEntity:
+				assert.Equal(t, rr.Code, tt.statusCode)
 			case http.StatusInternalServerError:
 				assert.Equal(t, rr.Code, tt.statusCode)
 			}
@@ -355,6 +513,78 @@ func TestApp_ListBooks(t *testing.T) {
 	}
 }
 
+func TestApp_HandleSearchBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		statusCode  int
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:       "success call",
+			query:      "?q=title",
+			statusCode: http.StatusOK,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				result := &model.BookSearchListDto{
+					Data: []model.BookSearchHitDto{{
+						BookDto:    *mockBookDto(),
+						Score:      1.5,
+						Highlights: map[string]string{"title": "<em>title</em>"},
+					}},
+					Total: 1,
+				}
+				mockSvc.EXPECT().SearchBooks(gomock.Any(), gomock.Any()).Return(result, nil).AnyTimes()
+			},
+		},
+		{
+			name:       "missing query",
+			query:      "",
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "server error",
+			query:      "?q=title",
+			statusCode: http.StatusInternalServerError,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().SearchBooks(gomock.Any(), gomock.Any()).Return(nil, errors.New("data access failure")).AnyTimes()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest("GET", "api/v1/books/search"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandleSearchBooks)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.statusCode == http.StatusOK {
+				assert.Contains(t, rr.Body.String(), `"score":1.5`)
+				assert.Contains(t, rr.Body.String(), `"highlights":{"title":"\u003cem\u003etitle\u003c/em\u003e"}`)
+			}
+		})
+	}
+}
+
 func TestApp_HandleUpdateBook(t *testing.T) {
 	type args struct {
 		jsonStr []byte
@@ -370,32 +600,32 @@ func TestApp_HandleUpdateBook(t *testing.T) {
 		{
 			name: "success call",
 			args: args{
-				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"image_url", "description":"description"}`),
+				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`),
 				id:      "1",
 			},
 			wantErr:    false,
 			statusCode: http.StatusAccepted,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().UpdateBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				mockSvc.EXPECT().UpdateBook(gomock.Any(), gomock.Any(), gomock.Any(), nil).Return(&model.BookDto{ID: 1, Version: 2}, nil).AnyTimes()
 
 			},
 		},
 		{
 			name: "bad request",
 			args: args{
-				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"image_url", "description":"description"}`),
+				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`),
 				id:      "2",
 			},
 			wantErr:    true,
 			statusCode: http.StatusNotFound,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().UpdateBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(gorm.ErrRecordNotFound).AnyTimes()
+				mockSvc.EXPECT().UpdateBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, apperror.NotFound("book not found", nil)).AnyTimes()
 			},
 		},
 		{
 			name: "id request failure",
 			args: args{
-				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"image_url", "description":"description"}`),
+				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`),
 				id:      "invalidParam",
 			},
 			wantErr:    true,
@@ -404,13 +634,13 @@ func TestApp_HandleUpdateBook(t *testing.T) {
 		{
 			name: "server error",
 			args: args{
-				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"image_url", "description":"description"}`),
+				jsonStr: []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`),
 				id:      "1",
 			},
 			wantErr:    true,
 			statusCode: http.StatusInternalServerError,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().UpdateBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("data update failure")).AnyTimes()
+				mockSvc.EXPECT().UpdateBook(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("data update failure")).AnyTimes()
 			},
 		},
 	}
@@ -419,6 +649,7 @@ func TestApp_HandleUpdateBook(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
 			mockLogger.EXPECT().Info().AnyTimes()
 			mockLogger.EXPECT().Warn().AnyTimes()
 
@@ -440,7 +671,7 @@ func TestApp_HandleUpdateBook(t *testing.T) {
 
 			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
-			a := app.NewApp(mockLogger, mockBookService)
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
 
 			handler := http.HandlerFunc(a.HandleUpdateBook)
 			handler.ServeHTTP(rr, req)
@@ -458,6 +689,265 @@ func TestApp_HandleUpdateBook(t *testing.T) {
 	}
 }
 
+func TestApp_HandlePatchBook(t *testing.T) {
+	type args struct {
+		jsonStr     []byte
+		id          string
+		contentType string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		statusCode  int
+		body        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name: "merge patch",
+			args: args{
+				jsonStr:     []byte(`{"description":"new description"}`),
+				id:          "1",
+				contentType: "application/merge-patch+json",
+			},
+			statusCode: http.StatusOK,
+			body:       `{"id":1,"title":"title","author":"author","published_date":"2006-01-02","image_url":"","description":"new description"}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().PatchBook(gomock.Any(), uint(1), nil, model.PatchMerge, []byte(`{"description":"new description"}`)).
+					Return(&model.BookDto{ID: 1, Title: "title", Author: "author", PublishedDate: "2006-01-02", Description: "new description"}, nil)
+			},
+		},
+		{
+			name: "json patch",
+			args: args{
+				jsonStr:     []byte(`[{"op":"replace","path":"/title","value":"new title"}]`),
+				id:          "1",
+				contentType: "application/json-patch+json; charset=utf-8",
+			},
+			statusCode: http.StatusOK,
+			body:       `{"id":1,"title":"new title","author":"author","published_date":"2006-01-02","image_url":"","description":""}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().PatchBook(gomock.Any(), uint(1), nil, model.PatchJSON, gomock.Any()).
+					Return(&model.BookDto{ID: 1, Title: "new title", Author: "author", PublishedDate: "2006-01-02"}, nil)
+			},
+		},
+		{
+			name: "unsupported media type",
+			args: args{
+				jsonStr:     []byte(`{"description":"new description"}`),
+				id:          "1",
+				contentType: "application/json",
+			},
+			statusCode: http.StatusUnsupportedMediaType,
+			body:       `{"type":"about:blank","title":"Unsupported Media Type","status":415,"instance":"api/v1/books/1"}`,
+		},
+		{
+			name: "invalid patched book",
+			args: args{
+				jsonStr:     []byte(`{"title":null}`),
+				id:          "1",
+				contentType: "application/merge-patch+json",
+			},
+			statusCode: http.StatusUnprocessableEntity,
+			body:       `{"type":"urn:myapp:problem:validation_failed","title":"Your request parameters didn't validate","status":422,"detail":"invalid patched book","instance":"api/v1/books/1","code":"validation_failed","invalid_params":[{"name":"title","reason":"is required"}]}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().PatchBook(gomock.Any(), uint(1), nil, model.PatchMerge, gomock.Any()).
+					Return(nil, apperror.Validation("invalid patched book", nil, apperror.FieldError{Name: "title", Reason: "is required"}))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			path := fmt.Sprintf("api/v1/books/%s", tt.args.id)
+			req, err := http.NewRequest("PATCH", path, bytes.NewBuffer(tt.args.jsonStr))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", tt.args.contentType)
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.args.id)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandlePatchBook)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.JSONEq(t, tt.body, rr.Body.String())
+			if tt.statusCode == http.StatusUnsupportedMediaType {
+				assert.Equal(t, "application/merge-patch+json, application/json-patch+json", rr.Header().Get("Accept-Patch"))
+			}
+		})
+	}
+}
+
+func TestApp_ConditionalRequests(t *testing.T) {
+	bookJSON := []byte(`{"title":"title", "author":"author", "published_date":"2006-01-02", "image_url":"https://example.com/cover.jpg", "description":"description"}`)
+
+	tests := []struct {
+		name        string
+		method      string
+		handler     func(a *app.App) http.HandlerFunc
+		body        []byte
+		header      map[string]string
+		statusCode  int
+		etag        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:       "read returns etag",
+			method:     "GET",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadBook },
+			statusCode: http.StatusOK,
+			etag:       `"3"`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByID(gomock.Any(), uint(1)).Return(&model.BookDto{ID: 1, Version: 3}, nil)
+			},
+		},
+		{
+			name:       "read not modified",
+			method:     "GET",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadBook },
+			header:     map[string]string{"If-None-Match": `"2", W/"3"`},
+			statusCode: http.StatusNotModified,
+			etag:       `"3"`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByID(gomock.Any(), uint(1)).Return(&model.BookDto{ID: 1, Version: 3}, nil)
+			},
+		},
+		{
+			name:       "read modified",
+			method:     "GET",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadBook },
+			header:     map[string]string{"If-None-Match": `"2"`},
+			statusCode: http.StatusOK,
+			etag:       `"3"`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookByID(gomock.Any(), uint(1)).Return(&model.BookDto{ID: 1, Version: 3}, nil)
+			},
+		},
+		{
+			name:       "update if match",
+			method:     "PUT",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateBook },
+			body:       bookJSON,
+			header:     map[string]string{"If-Match": `"3"`},
+			statusCode: http.StatusAccepted,
+			etag:       `"4"`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().UpdateBook(gomock.Any(), uint(1), gomock.Any(), []uint{3}).Return(&model.BookDto{ID: 1, Version: 4}, nil)
+			},
+		},
+		{
+			name:       "update stale version",
+			method:     "PUT",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateBook },
+			body:       bookJSON,
+			header:     map[string]string{"If-Match": `"2"`},
+			statusCode: http.StatusPreconditionFailed,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().UpdateBook(gomock.Any(), uint(1), gomock.Any(), []uint{2}).Return(nil, apperror.PreconditionFailed("book has been modified", nil))
+			},
+		},
+		{
+			name:       "update if any matches",
+			method:     "PUT",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateBook },
+			body:       bookJSON,
+			header:     map[string]string{"If-Match": `"3", W/"4", "4"`},
+			statusCode: http.StatusAccepted,
+			etag:       `"5"`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().UpdateBook(gomock.Any(), uint(1), gomock.Any(), []uint{3, 4}).Return(&model.BookDto{ID: 1, Version: 5}, nil)
+			},
+		},
+		{
+			name:       "update weak tag never matches",
+			method:     "PUT",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateBook },
+			body:       bookJSON,
+			header:     map[string]string{"If-Match": `W/"3"`},
+			statusCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:       "delete if match",
+			method:     "DELETE",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteBook },
+			header:     map[string]string{"If-Match": `"3"`},
+			statusCode: http.StatusAccepted,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().DeleteBook(gomock.Any(), uint(1), []uint{3}).Return(nil)
+			},
+		},
+		{
+			name:       "delete any version",
+			method:     "DELETE",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteBook },
+			header:     map[string]string{"If-Match": `*`},
+			statusCode: http.StatusAccepted,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().DeleteBook(gomock.Any(), uint(1), nil).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest(tt.method, "api/v1/books/1", bytes.NewBuffer(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			tt.handler(a).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.Equal(t, tt.etag, rr.Header().Get("ETag"))
+			if tt.statusCode == http.StatusNotModified {
+				assert.Empty(t, rr.Body.String())
+			}
+		})
+	}
+}
+
 func TestApp_HandleDeleteBook(t *testing.T) {
 	type args struct {
 		id string
@@ -477,7 +967,7 @@ func TestApp_HandleDeleteBook(t *testing.T) {
 			wantErr:    false,
 			statusCode: http.StatusAccepted,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().DeleteBook(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				mockSvc.EXPECT().DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
 
 			},
 		},
@@ -489,7 +979,7 @@ func TestApp_HandleDeleteBook(t *testing.T) {
 			wantErr:    true,
 			statusCode: http.StatusNotFound,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().DeleteBook(gomock.Any(), gomock.Any()).Return(gorm.ErrRecordNotFound).AnyTimes()
+				mockSvc.EXPECT().DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(apperror.NotFound("book not found", nil)).AnyTimes()
 			},
 		},
 		{
@@ -508,7 +998,7 @@ func TestApp_HandleDeleteBook(t *testing.T) {
 			wantErr:    true,
 			statusCode: http.StatusInternalServerError,
 			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
-				mockSvc.EXPECT().DeleteBook(gomock.Any(), gomock.Any()).Return(errors.New("data access failure")).AnyTimes()
+				mockSvc.EXPECT().DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("data access failure")).AnyTimes()
 			},
 		},
 	}
@@ -517,6 +1007,7 @@ func TestApp_HandleDeleteBook(t *testing.T) {
 			ctrl := gomock.NewController(t)
 
 			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
 			mockLogger.EXPECT().Info().AnyTimes()
 			mockLogger.EXPECT().Warn().AnyTimes()
 
@@ -538,7 +1029,7 @@ func TestApp_HandleDeleteBook(t *testing.T) {
 
 			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
-			a := app.NewApp(mockLogger, mockBookService)
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
 
 			handler := http.HandlerFunc(a.HandleDeleteBook)
 			handler.ServeHTTP(rr, req)
@@ -555,3 +1046,136 @@ func TestApp_HandleDeleteBook(t *testing.T) {
 		})
 	}
 }
+
+func TestApp_HandleBookHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		query       string
+		statusCode  int
+		body        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:       "success call",
+			id:         "1",
+			query:      "?limit=1",
+			statusCode: http.StatusOK,
+			body:       `{"data":[{"id":2,"action":"delete","actor":"alice","request_id":"req-1","created_at":"2023-03-01T10:00:00Z","before":{"title":"title","author":"","published_date":"2006-01-02","image_url":"","description":"","version":1},"after":null}],"total":2,"limit":1,"offset":0}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookHistory(gomock.Any(), uint(1), &model.PageQuery{Limit: 1}).
+					Return(&model.BookHistoryDto{
+						Data: []model.BookAuditDto{{
+							ID:        2,
+							Action:    model.BookAuditDelete,
+							Actor:     "alice",
+							RequestID: "req-1",
+							CreatedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+							Before:    &model.BookSnapshot{Title: "title", PublishedDate: "2006-01-02", Version: 1},
+						}},
+						Total: 2,
+						Limit: 1,
+					}, nil)
+			},
+		},
+		{
+			name:       "unknown book",
+			id:         "2",
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().GetBookHistory(gomock.Any(), uint(2), gomock.Any()).Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+		{
+			name:       "invalid offset",
+			id:         "1",
+			query:      "?offset=-1",
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest("GET", "api/v1/books/"+tt.id+"/history"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandleBookHistory)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.body != "" {
+				assert.JSONEq(t, tt.body, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestApp_HandleReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		health     *model.HealthDto
+		statusCode int
+	}{
+		{
+			name:       "ready",
+			health:     &model.HealthDto{Status: model.HealthStatusUp, Checks: []model.HealthCheckDto{{Name: "database", Status: model.HealthStatusUp}}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "not ready",
+			health:     &model.HealthDto{Status: model.HealthStatusDown, Checks: []model.HealthCheckDto{{Name: "database", Status: model.HealthStatusDown, Error: "check failed"}}},
+			statusCode: http.StatusServiceUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockHealthService := mock_service.NewMockHealthServiceInterface(ctrl)
+			mockHealthService.EXPECT().Readiness(gomock.Any()).Return(tt.health)
+
+			req, err := http.NewRequest("GET", "/readyz", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mockHealthService)
+
+			handler := http.HandlerFunc(a.HandleReadiness)
+			handler.ServeHTTP(rr, req)
+
+			b, err := json.Marshal(tt.health)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.JSONEq(t, string(b), rr.Body.String())
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"fmt"
+	"myapp/model"
+	"myapp/util/apperror"
+	"net/http"
+)
+
+func (a *App) HandleListCollections(w http.ResponseWriter, r *http.Request) {
+	query, err := model.NewPageForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	collections, err := a.svcCollection.GetListCollection(r.Context(), query)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, collections)
+}
+
+func (a *App) HandleCreateCollection(w http.ResponseWriter, r *http.Request) {
+	collectionForm := model.CollectionForm{}
+	if err := ParseRequestBody(w, r, a, &collectionForm); err != nil {
+		return
+	}
+
+	if err := ValidateForm(w, r, a, &collectionForm); err != nil {
+		return
+	}
+
+	collection, err := a.svcCollection.CreateCollection(r.Context(), &collectionForm)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data creation failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("New collection created: %d", collection.ID)
+	w.WriteHeader(http.StatusCreated)
+
+	RespondJSON(w, r, a, collection)
+}
+
+func (a *App) HandleReadCollection(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	collection, err := a.svcCollection.GetCollectionByID(r.Context(), id)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	RespondJSON(w, r, a, collection)
+}
+
+func (a *App) HandleUpdateCollection(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	collectionForm := &model.CollectionForm{}
+	if err := ParseRequestBody(w, r, a, collectionForm); err != nil {
+		return
+	}
+
+	if err := ValidateForm(w, r, a, collectionForm); err != nil {
+		return
+	}
+
+	if err := a.svcCollection.UpdateCollection(r.Context(), id, collectionForm); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Collection updated: %d", id)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (a *App) HandleDeleteCollection(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	if err := a.svcCollection.DeleteCollection(r.Context(), id); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Collection deleted: %d", id)
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,150 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HandleCollections(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		id          string
+		query       string
+		body        string
+		handler     func(a *app.App) http.HandlerFunc
+		statusCode  int
+		respBody    string
+		prepareMock func(mockSvc *mock_service.MockCollectionServiceInterface)
+	}{
+		{
+			name:       "list",
+			method:     "GET",
+			query:      "?limit=1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleListCollections },
+			statusCode: http.StatusOK,
+			respBody:   `{"data":[{"id":1,"name":"Favorites","description":"","owner":"alice"}],"total":2,"limit":1,"offset":0}`,
+			prepareMock: func(mockSvc *mock_service.MockCollectionServiceInterface) {
+				mockSvc.EXPECT().GetListCollection(gomock.Any(), &model.PageQuery{Limit: 1}).
+					Return(&model.CollectionListDto{Data: []model.CollectionDto{{ID: 1, Name: "Favorites", Owner: "alice"}}, Total: 2, Limit: 1}, nil)
+			},
+		},
+		{
+			name:       "create",
+			method:     "POST",
+			body:       `{"name":"Favorites","book_ids":[3]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateCollection },
+			statusCode: http.StatusCreated,
+			respBody:   `{"id":1,"name":"Favorites","description":"","owner":"alice","books":[{"id":3,"title":"The Hobbit","author":"J. R. R. Tolkien","published_date":"1937-09-21","image_url":"","description":""}]}`,
+			prepareMock: func(mockSvc *mock_service.MockCollectionServiceInterface) {
+				mockSvc.EXPECT().CreateCollection(gomock.Any(), &model.CollectionForm{Name: "Favorites", BookIDs: []uint{3}}).
+					Return(&model.CollectionDto{
+						ID:    1,
+						Name:  "Favorites",
+						Owner: "alice",
+						Books: []model.BookDto{{ID: 3, Title: "The Hobbit", Author: "J. R. R. Tolkien", PublishedDate: "1937-09-21"}},
+					}, nil)
+			},
+		},
+		{
+			name:       "create with duplicate books",
+			method:     "POST",
+			body:       `{"name":"Favorites","book_ids":[3,3]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateCollection },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "read",
+			method:     "GET",
+			id:         "1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadCollection },
+			statusCode: http.StatusOK,
+			respBody:   `{"id":1,"name":"Favorites","description":"","owner":"alice"}`,
+			prepareMock: func(mockSvc *mock_service.MockCollectionServiceInterface) {
+				mockSvc.EXPECT().GetCollectionByID(gomock.Any(), uint(1)).Return(&model.CollectionDto{ID: 1, Name: "Favorites", Owner: "alice"}, nil)
+			},
+		},
+		{
+			name:       "update unknown book",
+			method:     "PUT",
+			id:         "1",
+			body:       `{"name":"Favorites","book_ids":[4]}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUpdateCollection },
+			statusCode: http.StatusUnprocessableEntity,
+			prepareMock: func(mockSvc *mock_service.MockCollectionServiceInterface) {
+				mockSvc.EXPECT().UpdateCollection(gomock.Any(), uint(1), &model.CollectionForm{Name: "Favorites", BookIDs: []uint{4}}).
+					Return(apperror.Validation("unknown book", nil, apperror.FieldError{Name: "book_ids", Reason: "must reference existing books"}))
+			},
+		},
+		{
+			name:       "delete",
+			method:     "DELETE",
+			id:         "1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteCollection },
+			statusCode: http.StatusAccepted,
+			prepareMock: func(mockSvc *mock_service.MockCollectionServiceInterface) {
+				mockSvc.EXPECT().DeleteCollection(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+		{
+			name:       "delete unknown",
+			method:     "DELETE",
+			id:         "2",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteCollection },
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockCollectionServiceInterface) {
+				mockSvc.EXPECT().DeleteCollection(gomock.Any(), uint(2)).Return(apperror.NotFound("collection not found", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockCollectionService := mock_service.NewMockCollectionServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockCollectionService)
+			}
+
+			req, err := http.NewRequest(tt.method, "api/v1/collections/"+tt.id+tt.query, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mockCollectionService, mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			tt.handler(a).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.respBody != "" {
+				assert.JSONEq(t, tt.respBody, rr.Body.String())
+			}
+		})
+	}
+}
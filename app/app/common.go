@@ -2,42 +2,136 @@ package app
 
 import (
 	"encoding/json"
-	"fmt"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi"
 )
 
-func ParseRequestBody(w http.ResponseWriter, r *http.Request, a *App, v interface{}, statusCodeErr int) {
+// StatusClientClosedRequest is the non-standard status, from nginx, logged when
+// the client disconnected before the response was written.
+const StatusClientClosedRequest = 499
+
+// statusCodes maps domain error codes to HTTP status codes.
+var statusCodes = map[apperror.Code]int{
+	apperror.CodeNotFound:           http.StatusNotFound,
+	apperror.CodeValidation:         http.StatusUnprocessableEntity,
+	apperror.CodeConflict:           http.StatusConflict,
+	apperror.CodePreconditionFailed: http.StatusPreconditionFailed,
+	apperror.CodeCanceled:           StatusClientClosedRequest,
+	apperror.CodeTimeout:            http.StatusServiceUnavailable,
+	apperror.CodeAborted:            http.StatusFailedDependency,
+	apperror.CodeUnauthorized:       http.StatusUnauthorized,
+	apperror.CodeForbidden:          http.StatusForbidden,
+	apperror.CodeRateLimited:        http.StatusTooManyRequests,
+}
+
+// StatusCode returns the HTTP status code for err, 500 for errors which are not domain errors.
+func StatusCode(err error) int {
+	if status, ok := statusCodes[apperror.CodeOf(err)]; ok {
+		return status
+	}
+
+	return http.StatusInternalServerError
+}
+
+func ParseRequestBody(w http.ResponseWriter, r *http.Request, a *App, v interface{}) error {
 	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
-		RespondError(w, a, fmt.Errorf("parse request body: %w", err), statusCodeErr)
-		return
+		err = apperror.Validation("invalid request body", err)
+		RespondError(w, r, a, err)
+		return err
 	}
+
+	return nil
+}
+
+// ValidateForm checks v against its form tags and responds with the list of
+// field errors when it is invalid.
+func ValidateForm(w http.ResponseWriter, r *http.Request, a *App, v interface{}) error {
+	if err := a.validator.Struct(v); err != nil {
+		if fields := validator.ToFieldErrors(err); fields != nil {
+			err = apperror.Validation("invalid form", err, fields...)
+		}
+
+		RespondError(w, r, a, err)
+		return err
+	}
+
+	return nil
 }
 
-func RespondJSON(w http.ResponseWriter, r *http.Request, a *App, v interface{}, statusCodeErr int) {
+func RespondJSON(w http.ResponseWriter, r *http.Request, a *App, v interface{}) {
 	if err := json.NewEncoder(w).Encode(v); err != nil {
-		RespondError(w, a, fmt.Errorf("data encode: %w", err), statusCodeErr)
-		return
+		a.logger.WithContext(r.Context()).Warn().Err(err).Msg("data encode")
 	}
 }
 
-func RespondError(w http.ResponseWriter, a *App, err error, statusCodeErr int) {
-	a.logger.Warn().Err(err).Msg("")
+// RespondError writes err as problem details with the status code of its domain error code.
+// Details of internal errors are logged but never sent to the client.
+func RespondError(w http.ResponseWriter, r *http.Request, a *App, err error) {
+	p := NewProblem(r, err)
 
-	w.WriteHeader(statusCodeErr)
-	fmt.Fprintf(w, `{"error": "%v"}`, err)
+	l := a.logger.WithContext(r.Context())
+	if p.Status >= http.StatusInternalServerError {
+		l.Warn().Err(err).Msg("")
+	} else {
+		l.Info().Err(err).Msg("")
+	}
+
+	WriteProblem(w, r, a, p)
 }
 
 func ParseUint(w http.ResponseWriter, r *http.Request, a *App) (uint, error) {
 	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 0, 64)
 	if err != nil || id == 0 {
-		a.logger.Info().Msgf("can not parse ID: %v", id)
-
-		w.WriteHeader(http.StatusUnprocessableEntity)
-		return uint(id), err
+		return 0, apperror.Validation("invalid id", err, apperror.FieldError{Name: "id", Reason: "must be a positive integer"})
 	}
 
 	return uint(id), nil
 }
+
+// pageLinks builds self/next/prev links for a list page, keeping the request filters.
+// Cursor links are used when the client paginates with cursors, offset links otherwise.
+func pageLinks(r *http.Request, query *model.BookQuery, list *model.BookListDto) model.PageLinks {
+	link := func(set map[string]string) string {
+		u := *r.URL
+		v := u.Query()
+		for key, val := range set {
+			if val == "" {
+				v.Del(key)
+				continue
+			}
+			v.Set(key, val)
+		}
+		u.RawQuery = v.Encode()
+		return u.RequestURI()
+	}
+
+	links := model.PageLinks{Self: r.URL.RequestURI()}
+
+	if query.Cursor != nil {
+		if list.NextCursor != "" {
+			links.Next = link(map[string]string{"cursor": list.NextCursor})
+		}
+		if list.PrevCursor != "" {
+			links.Prev = link(map[string]string{"cursor": list.PrevCursor})
+		}
+		return links
+	}
+
+	if int64(query.Offset+query.Limit) < list.Total {
+		links.Next = link(map[string]string{"offset": strconv.Itoa(query.Offset + query.Limit)})
+	}
+	if query.Offset > 0 {
+		prev := query.Offset - query.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links.Prev = link(map[string]string{"offset": strconv.Itoa(prev)})
+	}
+
+	return links
+}
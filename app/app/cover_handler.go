@@ -0,0 +1,110 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"myapp/util/apperror"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+)
+
+// coverPart is the name of the multipart form part holding the cover.
+const coverPart = "cover"
+
+// maxCoverUploadSize bounds the cover upload requests, form parts skipped
+// included; the covers themselves are bounded by the cover service.
+const maxCoverUploadSize = 32 << 20
+
+// HandleUploadCover stores the image of the cover part of a multipart form as
+// the cover of the book.
+func (a *App) HandleUploadCover(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxCoverUploadSize)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		WriteProblem(w, r, a, NewStatusProblem(r, http.StatusUnsupportedMediaType))
+		return
+	}
+
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			RespondError(w, r, a, apperror.Validation("missing cover", nil, apperror.FieldError{Name: coverPart, Reason: "is a required field"}))
+			return
+		}
+		if err != nil {
+			RespondError(w, r, a, apperror.Validation("invalid multipart form", err))
+			return
+		}
+
+		if part.FormName() != coverPart {
+			part.Close()
+			continue
+		}
+
+		book, err := a.svcCover.SetCover(r.Context(), id, part)
+		part.Close()
+		if err != nil {
+			RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+			return
+		}
+
+		a.logger.WithContext(r.Context()).Info().Msgf("Book cover uploaded: %d", id)
+		w.Header().Set("ETag", ETag(book.Version))
+
+		RespondJSON(w, r, a, book)
+		return
+	}
+}
+
+// HandleReadCover serves the cover of the book, or its thumbnail of the size
+// of the path.
+func (a *App) HandleReadCover(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	cover, err := a.svcCover.GetCover(r.Context(), id, chi.URLParam(r, "size"))
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+	defer cover.Body.Close()
+
+	w.Header().Set("Content-Type", cover.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if cover.Size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(cover.Size, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, cover.Body); err != nil {
+		a.logger.WithContext(r.Context()).Warn().Err(err).Msgf("Cover of book %d not fully sent", id)
+	}
+}
+
+func (a *App) HandleDeleteCover(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	if err := a.svcCover.DeleteCover(r.Context(), id); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Book cover deleted: %d", id)
+	w.WriteHeader(http.StatusNoContent)
+}
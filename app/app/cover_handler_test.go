@@ -0,0 +1,224 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// multipartBody returns a multipart form with the part and its content type.
+func multipartBody(t *testing.T, part, content string) (io.Reader, string) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, err := mw.CreateFormFile(part, "cover.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &buf, mw.FormDataContentType()
+}
+
+func TestApp_HandleCovers(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		size        string
+		part        string
+		partSize    int
+		contentType string
+		handler     func(a *app.App) http.HandlerFunc
+		statusCode  int
+		respBody    string
+		respHeaders map[string]string
+		prepareMock func(mockSvc *mock_service.MockCoverServiceInterface)
+	}{
+		{
+			name:       "upload cover",
+			method:     "PUT",
+			part:       "cover",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUploadCover },
+			statusCode: http.StatusOK,
+			respBody:   `{"id":1,"title":"title","author":"author","published_date":"","image_url":"/api/v1/books/1/cover","description":""}`,
+			respHeaders: map[string]string{
+				"ETag": `"2"`,
+			},
+			prepareMock: func(mockSvc *mock_service.MockCoverServiceInterface) {
+				mockSvc.EXPECT().SetCover(gomock.Any(), uint(1), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, id uint, r io.Reader) (*model.BookDto, error) {
+						data, err := io.ReadAll(r)
+						if err != nil || string(data) != "image" {
+							t.Errorf("SetCover() read %q, %v", data, err)
+						}
+						return &model.BookDto{ID: 1, Title: "title", Author: "author", ImageUrl: "/api/v1/books/1/cover", Version: 2}, nil
+					})
+			},
+		},
+		{
+			name:        "upload without multipart form",
+			method:      "PUT",
+			contentType: "image/png",
+			handler:     func(a *app.App) http.HandlerFunc { return a.HandleUploadCover },
+			statusCode:  http.StatusUnsupportedMediaType,
+		},
+		{
+			name:       "upload without cover part",
+			method:     "PUT",
+			part:       "image",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUploadCover },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "upload too large",
+			method:     "PUT",
+			part:       "image",
+			partSize:   32<<20 + 1,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUploadCover },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "upload invalid cover",
+			method:     "PUT",
+			part:       "cover",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleUploadCover },
+			statusCode: http.StatusUnprocessableEntity,
+			prepareMock: func(mockSvc *mock_service.MockCoverServiceInterface) {
+				mockSvc.EXPECT().SetCover(gomock.Any(), uint(1), gomock.Any()).
+					Return(nil, apperror.Validation("unsupported cover type", nil, apperror.FieldError{Name: "cover", Reason: "must be a JPEG, PNG or GIF image"}))
+			},
+		},
+		{
+			name:       "read cover",
+			method:     "GET",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadCover },
+			statusCode: http.StatusOK,
+			respHeaders: map[string]string{
+				"Content-Type":           "image/png",
+				"Content-Length":         "5",
+				"X-Content-Type-Options": "nosniff",
+			},
+			prepareMock: func(mockSvc *mock_service.MockCoverServiceInterface) {
+				mockSvc.EXPECT().GetCover(gomock.Any(), uint(1), "").
+					Return(&model.Cover{Body: io.NopCloser(bytes.NewBufferString("image")), Size: 5, ContentType: "image/png"}, nil)
+			},
+		},
+		{
+			name:       "read thumbnail",
+			method:     "GET",
+			size:       "small",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadCover },
+			statusCode: http.StatusOK,
+			respHeaders: map[string]string{
+				"Content-Type": "image/jpeg",
+			},
+			prepareMock: func(mockSvc *mock_service.MockCoverServiceInterface) {
+				mockSvc.EXPECT().GetCover(gomock.Any(), uint(1), "small").
+					Return(&model.Cover{Body: io.NopCloser(bytes.NewBufferString("image")), Size: 5, ContentType: "image/jpeg"}, nil)
+			},
+		},
+		{
+			name:       "read unknown size",
+			method:     "GET",
+			size:       "huge",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleReadCover },
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockCoverServiceInterface) {
+				mockSvc.EXPECT().GetCover(gomock.Any(), uint(1), "huge").Return(nil, apperror.NotFound("unknown cover size", nil))
+			},
+		},
+		{
+			name:       "delete cover",
+			method:     "DELETE",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteCover },
+			statusCode: http.StatusNoContent,
+			prepareMock: func(mockSvc *mock_service.MockCoverServiceInterface) {
+				mockSvc.EXPECT().DeleteCover(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+		{
+			name:       "delete missing cover",
+			method:     "DELETE",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteCover },
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockCoverServiceInterface) {
+				mockSvc.EXPECT().DeleteCover(gomock.Any(), uint(1)).Return(apperror.NotFound("book has no cover", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockCoverService := mock_service.NewMockCoverServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockCoverService)
+			}
+
+			var body io.Reader = &bytes.Buffer{}
+			contentType := tt.contentType
+			if tt.part != "" {
+				content := "image"
+				if tt.partSize > 0 {
+					content = strings.Repeat("x", tt.partSize)
+				}
+				body, contentType = multipartBody(t, tt.part, content)
+			}
+
+			req, err := http.NewRequest(tt.method, "api/v1/books/1/cover", body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "1")
+			if tt.size != "" {
+				rctx.URLParams.Add("size", tt.size)
+			}
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mockCoverService, mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			tt.handler(a).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			for key, value := range tt.respHeaders {
+				assert.Equal(t, value, rr.Header().Get(key), key)
+			}
+			if tt.respBody != "" {
+				assert.JSONEq(t, tt.respBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"fmt"
+	"myapp/util/apperror"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ETag returns the strong entity tag of a resource at version.
+func ETag(version uint) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// IfMatchVersions returns the versions listed by the If-Match header, one of
+// which is required, or none when any version will do. Weak tags and tags we
+// didn't issue can never match.
+func IfMatchVersions(r *http.Request) ([]uint, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	var versions []uint
+	for _, tag := range strings.Split(header, ",") {
+		if version, ok := parseETag(strings.TrimSpace(tag)); ok {
+			versions = append(versions, version)
+		}
+	}
+	if len(versions) == 0 {
+		return nil, apperror.PreconditionFailed("If-Match doesn't match the current version", nil)
+	}
+
+	return versions, nil
+}
+
+// IfNoneMatch reports whether the If-None-Match header matches etag, using
+// the weak comparison as required for GET.
+func IfNoneMatch(r *http.Request, etag string) bool {
+	header := strings.TrimSpace(r.Header.Get("If-None-Match"))
+	if header == "*" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseETag(tag string) (uint, bool) {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.ParseUint(tag[1:len(tag)-1], 10, 64)
+	if err != nil || version == 0 {
+		return 0, false
+	}
+
+	return uint(version), true
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"net/http"
+)
+
+func (a *App) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, a.svcHealth.Liveness(r.Context()))
+}
+
+func (a *App) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	health := a.svcHealth.Readiness(r.Context())
+
+	if !health.IsUp() {
+		a.logger.WithContext(r.Context()).Warn().Interface("checks", health.Checks).Msg("Readiness check failed")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	RespondJSON(w, r, a, health)
+}
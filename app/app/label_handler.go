@@ -0,0 +1,157 @@
+package app
+
+import (
+	"fmt"
+	"myapp/model"
+	"myapp/util/apperror"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
+)
+
+// labelParam returns the name of the label addressed by the path.
+func labelParam(r *http.Request) (string, error) {
+	name, err := url.PathUnescape(chi.URLParam(r, "name"))
+	if err != nil {
+		return "", apperror.Validation("invalid label name", err)
+	}
+
+	return name, nil
+}
+
+// HandleListLabels returns the handler listing the labels of the kind.
+func (a *App) HandleListLabels(kind model.LabelKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query, err := model.NewPageForm(r.URL.Query()).ToQuery()
+		if err != nil {
+			RespondError(w, r, a, apperror.Validation(err.Error(), err))
+			return
+		}
+
+		labels, err := a.svcLabel.GetListLabel(r.Context(), kind, query)
+		if err != nil {
+			RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		RespondJSON(w, r, a, labels)
+	}
+}
+
+// HandleCreateLabel returns the handler creating labels of the kind.
+func (a *App) HandleCreateLabel(kind model.LabelKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		labelForm := model.LabelForm{}
+		if err := ParseRequestBody(w, r, a, &labelForm); err != nil {
+			return
+		}
+
+		if err := ValidateForm(w, r, a, &labelForm); err != nil {
+			return
+		}
+
+		label, err := a.svcLabel.CreateLabel(r.Context(), kind, &labelForm)
+		if err != nil {
+			RespondError(w, r, a, fmt.Errorf("data creation failure: %w", err))
+			return
+		}
+
+		a.logger.WithContext(r.Context()).Info().Msgf("New %s created: %s", kind, label.Name)
+		w.WriteHeader(http.StatusCreated)
+
+		RespondJSON(w, r, a, label)
+	}
+}
+
+// HandleDeleteLabel returns the handler deleting labels of the kind.
+func (a *App) HandleDeleteLabel(kind model.LabelKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, err := labelParam(r)
+		if err != nil {
+			RespondError(w, r, a, err)
+			return
+		}
+
+		if err := a.svcLabel.DeleteLabel(r.Context(), kind, name); err != nil {
+			RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+			return
+		}
+
+		a.logger.WithContext(r.Context()).Info().Msgf("Deleted %s: %s", kind, name)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// HandleListBookLabels returns the handler listing the labels of the kind
+// attached to a book.
+func (a *App) HandleListBookLabels(kind model.LabelKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseUint(w, r, a)
+		if err != nil {
+			RespondError(w, r, a, err)
+			return
+		}
+
+		labels, err := a.svcLabel.GetBookLabels(r.Context(), kind, id)
+		if err != nil {
+			RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		RespondJSON(w, r, a, labels)
+	}
+}
+
+// HandleAttachLabel returns the handler attaching labels of the kind to a
+// book. Attaching a label twice is a no-op.
+func (a *App) HandleAttachLabel(kind model.LabelKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseUint(w, r, a)
+		if err != nil {
+			RespondError(w, r, a, err)
+			return
+		}
+
+		name, err := labelParam(r)
+		if err != nil {
+			RespondError(w, r, a, err)
+			return
+		}
+
+		if err := a.svcLabel.AttachLabel(r.Context(), kind, id, name); err != nil {
+			RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+			return
+		}
+
+		a.logger.WithContext(r.Context()).Info().Msgf("Book %d attached to %s %s", id, kind, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// HandleDetachLabel returns the handler detaching labels of the kind from a book.
+func (a *App) HandleDetachLabel(kind model.LabelKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseUint(w, r, a)
+		if err != nil {
+			RespondError(w, r, a, err)
+			return
+		}
+
+		name, err := labelParam(r)
+		if err != nil {
+			RespondError(w, r, a, err)
+			return
+		}
+
+		if err := a.svcLabel.DetachLabel(r.Context(), kind, id, name); err != nil {
+			RespondError(w, r, a, fmt.Errorf("data update failure: %w", err))
+			return
+		}
+
+		a.logger.WithContext(r.Context()).Info().Msgf("Book %d detached from %s %s", id, kind, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
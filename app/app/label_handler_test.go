@@ -0,0 +1,167 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HandleLabels(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		id          string
+		label       string
+		query       string
+		body        string
+		handler     func(a *app.App) http.HandlerFunc
+		statusCode  int
+		respBody    string
+		prepareMock func(mockSvc *mock_service.MockLabelServiceInterface)
+	}{
+		{
+			name:       "list genres",
+			method:     "GET",
+			query:      "?limit=1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleListLabels(model.LabelGenre) },
+			statusCode: http.StatusOK,
+			respBody:   `{"data":[{"id":1,"name":"fantasy"}],"total":2,"limit":1,"offset":0}`,
+			prepareMock: func(mockSvc *mock_service.MockLabelServiceInterface) {
+				mockSvc.EXPECT().GetListLabel(gomock.Any(), model.LabelGenre, &model.PageQuery{Limit: 1}).
+					Return(&model.LabelListDto{Data: []model.LabelDto{{ID: 1, Name: "fantasy"}}, Total: 2, Limit: 1}, nil)
+			},
+		},
+		{
+			name:       "create genre",
+			method:     "POST",
+			body:       `{"name":"fantasy"}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateLabel(model.LabelGenre) },
+			statusCode: http.StatusCreated,
+			respBody:   `{"id":1,"name":"fantasy"}`,
+			prepareMock: func(mockSvc *mock_service.MockLabelServiceInterface) {
+				mockSvc.EXPECT().CreateLabel(gomock.Any(), model.LabelGenre, &model.LabelForm{Name: "fantasy"}).
+					Return(&model.LabelDto{ID: 1, Name: "fantasy"}, nil)
+			},
+		},
+		{
+			name:       "create genre without name",
+			method:     "POST",
+			body:       `{}`,
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleCreateLabel(model.LabelGenre) },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "delete tag",
+			method:     "DELETE",
+			label:      "classic",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDeleteLabel(model.LabelTag) },
+			statusCode: http.StatusAccepted,
+			prepareMock: func(mockSvc *mock_service.MockLabelServiceInterface) {
+				mockSvc.EXPECT().DeleteLabel(gomock.Any(), model.LabelTag, "classic").Return(nil)
+			},
+		},
+		{
+			name:       "book tags",
+			method:     "GET",
+			id:         "1",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleListBookLabels(model.LabelTag) },
+			statusCode: http.StatusOK,
+			respBody:   `{"data":[{"id":3,"name":"classic"}]}`,
+			prepareMock: func(mockSvc *mock_service.MockLabelServiceInterface) {
+				mockSvc.EXPECT().GetBookLabels(gomock.Any(), model.LabelTag, uint(1)).
+					Return(&model.BookLabelListDto{Data: []model.LabelDto{{ID: 3, Name: "classic"}}}, nil)
+			},
+		},
+		{
+			name:       "attach escaped tag",
+			method:     "PUT",
+			id:         "1",
+			label:      "science%20fiction",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleAttachLabel(model.LabelTag) },
+			statusCode: http.StatusNoContent,
+			prepareMock: func(mockSvc *mock_service.MockLabelServiceInterface) {
+				mockSvc.EXPECT().AttachLabel(gomock.Any(), model.LabelTag, uint(1), "science fiction").Return(nil)
+			},
+		},
+		{
+			name:       "attach unknown genre",
+			method:     "PUT",
+			id:         "1",
+			label:      "fantasy",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleAttachLabel(model.LabelGenre) },
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockLabelServiceInterface) {
+				mockSvc.EXPECT().AttachLabel(gomock.Any(), model.LabelGenre, uint(1), "fantasy").Return(apperror.NotFound("genre not found", nil))
+			},
+		},
+		{
+			name:       "attach invalid escape",
+			method:     "PUT",
+			id:         "1",
+			label:      "bad%zz",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleAttachLabel(model.LabelTag) },
+			statusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "detach genre",
+			method:     "DELETE",
+			id:         "1",
+			label:      "fantasy",
+			handler:    func(a *app.App) http.HandlerFunc { return a.HandleDetachLabel(model.LabelGenre) },
+			statusCode: http.StatusNoContent,
+			prepareMock: func(mockSvc *mock_service.MockLabelServiceInterface) {
+				mockSvc.EXPECT().DetachLabel(gomock.Any(), model.LabelGenre, uint(1), "fantasy").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			mockLabelService := mock_service.NewMockLabelServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockLabelService)
+			}
+
+			req, err := http.NewRequest(tt.method, "api/v1/tags"+tt.query, bytes.NewBufferString(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			rctx.URLParams.Add("name", tt.label)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mockLabelService, mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			tt.handler(a).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.respBody != "" {
+				assert.JSONEq(t, tt.respBody, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"encoding/json"
+	"myapp/util/apperror"
+	"net/http"
+)
+
+const (
+	ProblemContentType = "application/problem+json"
+
+	// problemTypePrefix namespaces the problem types of domain errors.
+	problemTypePrefix = "urn:myapp:problem:"
+)
+
+// Problem is an RFC 7807 problem details object.
+type Problem struct {
+	Type     string        `json:"type"`
+	Title    string        `json:"title"`
+	Status   int           `json:"status"`
+	Detail   string        `json:"detail,omitempty"`
+	Instance string        `json:"instance,omitempty"`
+	Code     apperror.Code `json:"code,omitempty"`
+
+	// InvalidParams lists the invalid fields of validation problems.
+	InvalidParams []apperror.FieldError `json:"invalid_params,omitempty"`
+}
+
+var problemTitles = map[apperror.Code]string{
+	apperror.CodeNotFound:           "Resource not found",
+	apperror.CodeValidation:         "Your request parameters didn't validate",
+	apperror.CodeConflict:           "Resource conflict",
+	apperror.CodePreconditionFailed: "Precondition failed",
+	apperror.CodeCanceled:           "Client closed request",
+	apperror.CodeTimeout:            "Request timed out",
+	apperror.CodeAborted:            "Operation aborted",
+	apperror.CodeUnauthorized:       "Authentication required",
+	apperror.CodeForbidden:          "Permission denied",
+	apperror.CodeRateLimited:        "Too many requests",
+}
+
+// NewProblem builds the problem details of err for the request r.
+// Details of internal errors are never exposed.
+func NewProblem(r *http.Request, err error) *Problem {
+	e, ok := apperror.As(err)
+	if !ok {
+		return NewStatusProblem(r, http.StatusInternalServerError)
+	}
+
+	p := &Problem{
+		Type:          problemTypePrefix + string(e.Code),
+		Title:         problemTitles[e.Code],
+		Status:        StatusCode(err),
+		Detail:        e.Message,
+		Instance:      r.URL.RequestURI(),
+		Code:          e.Code,
+		InvalidParams: e.Fields,
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	return p
+}
+
+// NewStatusProblem builds a problem without additional semantics beyond the HTTP status.
+func NewStatusProblem(r *http.Request, status int) *Problem {
+	var code apperror.Code
+	if status == http.StatusInternalServerError {
+		code = apperror.CodeInternal
+	}
+	for c, s := range statusCodes {
+		if s == status {
+			code = c
+		}
+	}
+
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Instance: r.URL.RequestURI(),
+		Code:     code,
+	}
+}
+
+func WriteProblem(w http.ResponseWriter, r *http.Request, a *App, p *Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+
+	if err := json.NewEncoder(w).Encode(p); err != nil {
+		a.logger.WithContext(r.Context()).Warn().Err(err).Msg("problem encode")
+	}
+}
+
+func (a *App) HandleNotFound(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, r, a, NewStatusProblem(r, http.StatusNotFound))
+}
+
+func (a *App) HandleMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	WriteProblem(w, r, a, NewStatusProblem(r, http.StatusMethodNotAllowed))
+}
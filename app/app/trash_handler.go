@@ -0,0 +1,59 @@
+package app
+
+import (
+	"fmt"
+	"myapp/model"
+	"myapp/util/apperror"
+	"net/http"
+)
+
+func (a *App) HandleListDeletedBooks(w http.ResponseWriter, r *http.Request) {
+	query, err := model.NewPageForm(r.URL.Query()).ToQuery()
+	if err != nil {
+		RespondError(w, r, a, apperror.Validation(err.Error(), err))
+		return
+	}
+
+	books, err := a.svcBook.ListDeletedBooks(r.Context(), query)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data access failure: %w", err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	RespondJSON(w, r, a, books)
+}
+
+func (a *App) HandleRestoreBook(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	book, err := a.svcBook.RestoreBook(r.Context(), id)
+	if err != nil {
+		RespondError(w, r, a, fmt.Errorf("data restore failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Book restored: %d", id)
+	w.Header().Set("ETag", ETag(book.Version))
+	RespondJSON(w, r, a, book)
+}
+
+func (a *App) HandlePurgeBook(w http.ResponseWriter, r *http.Request) {
+	id, err := ParseUint(w, r, a)
+	if err != nil {
+		RespondError(w, r, a, err)
+		return
+	}
+
+	if err := a.svcBook.PurgeBook(r.Context(), id); err != nil {
+		RespondError(w, r, a, fmt.Errorf("data purge failure: %w", err))
+		return
+	}
+
+	a.logger.WithContext(r.Context()).Info().Msgf("Book purged: %d", id)
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,176 @@
+package app_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"myapp/app/app"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/validator"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApp_HandleListDeletedBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		statusCode  int
+		body        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:       "success call",
+			query:      "?limit=10&offset=10",
+			statusCode: http.StatusOK,
+			body:       `{"data":[{"id":1,"title":"title","author":"author","published_date":"2006-01-02","image_url":"","description":"","deleted_at":"2023-03-01T10:00:00Z"}],"total":11,"limit":10,"offset":10}`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().ListDeletedBooks(gomock.Any(), &model.PageQuery{Limit: 10, Offset: 10}).
+					Return(&model.BookTrashListDto{
+						Data: []model.DeletedBookDto{{
+							BookDto:   model.BookDto{ID: 1, Title: "title", Author: "author", PublishedDate: "2006-01-02"},
+							DeletedAt: time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC),
+						}},
+						Total:  11,
+						Limit:  10,
+						Offset: 10,
+					}, nil)
+			},
+		},
+		{
+			name:       "invalid limit",
+			query:      "?limit=1000",
+			statusCode: http.StatusUnprocessableEntity,
+			body:       `{"type":"urn:myapp:problem:validation_failed","title":"Your request parameters didn't validate","status":422,"detail":"limit must be an integer between 1 and 100","instance":"api/v1/books/trash?limit=1000","code":"validation_failed"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest("GET", "api/v1/books/trash"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandleListDeletedBooks)
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.JSONEq(t, tt.body, rr.Body.String())
+		})
+	}
+}
+
+func TestApp_HandleRestoreAndPurgeBook(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		id          string
+		statusCode  int
+		etag        string
+		prepareMock func(mockSvc *mock_service.MockBookServiceInterface)
+	}{
+		{
+			name:       "restore",
+			method:     "POST",
+			id:         "1",
+			statusCode: http.StatusOK,
+			etag:       `"4"`,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().RestoreBook(gomock.Any(), uint(1)).Return(&model.BookDto{ID: 1, Version: 4}, nil)
+			},
+		},
+		{
+			name:       "restore book not in trash",
+			method:     "POST",
+			id:         "2",
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().RestoreBook(gomock.Any(), uint(2)).Return(nil, apperror.NotFound("deleted book not found", nil))
+			},
+		},
+		{
+			name:       "purge",
+			method:     "DELETE",
+			id:         "1",
+			statusCode: http.StatusAccepted,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().PurgeBook(gomock.Any(), uint(1)).Return(nil)
+			},
+		},
+		{
+			name:       "purge book not in trash",
+			method:     "DELETE",
+			id:         "2",
+			statusCode: http.StatusNotFound,
+			prepareMock: func(mockSvc *mock_service.MockBookServiceInterface) {
+				mockSvc.EXPECT().PurgeBook(gomock.Any(), uint(2)).Return(apperror.NotFound("deleted book not found", nil))
+			},
+		},
+		{
+			name:       "invalid id",
+			method:     "DELETE",
+			id:         "invalid",
+			statusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockBookService)
+			}
+
+			req, err := http.NewRequest(tt.method, "api/v1/books/trash/"+tt.id, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			handler := http.HandlerFunc(a.HandlePurgeBook)
+			if tt.method == "POST" {
+				handler = a.HandleRestoreBook
+			}
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.Equal(t, tt.etag, rr.Header().Get("ETag"))
+		})
+	}
+}
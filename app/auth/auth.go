@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myapp/config"
+	"myapp/service"
+	"myapp/util/apperror"
+	"myapp/util/jwt"
+	"myapp/util/policy"
+	"myapp/util/principal"
+	"net/http"
+	"strings"
+)
+
+// ErrNoCredentials is returned by an Authenticator for requests without the
+// credentials it checks, so that another one may check theirs.
+var ErrNoCredentials = errors.New("no credentials")
+
+// Authenticator identifies the principal of a request. Invalid credentials are
+// reported by apperror.Unauthorized errors.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*principal.Principal, error)
+}
+
+// Chain authenticates requests with the first of its authenticators whose
+// credentials they carry.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (*principal.Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+
+		return p, err
+	}
+
+	return nil, ErrNoCredentials
+}
+
+// Grant returns authn granting the principals it authenticates the
+// permissions of their roles in pol.
+func Grant(authn Authenticator, pol policy.Policy) Authenticator {
+	return &granting{authn: authn, policy: pol}
+}
+
+type granting struct {
+	authn  Authenticator
+	policy policy.Policy
+}
+
+func (g *granting) Authenticate(r *http.Request) (*principal.Principal, error) {
+	p, err := g.authn.Authenticate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Permissions = g.policy.Permissions(p.Roles)
+
+	return p, nil
+}
+
+// Bearer authenticates requests by the JWT of their Authorization header.
+type Bearer struct {
+	verifier *jwt.Verifier
+}
+
+func NewBearer(verifier *jwt.Verifier) *Bearer {
+	return &Bearer{verifier: verifier}
+}
+
+func (b *Bearer) Authenticate(r *http.Request) (*principal.Principal, error) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrNoCredentials
+	}
+
+	claims, err := b.verifier.Verify(r.Context(), strings.TrimSpace(token))
+	if errors.Is(err, jwt.ErrInvalidToken) {
+		return nil, apperror.Unauthorized(err.Error(), nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &principal.Principal{Subject: claims.Subject, Roles: claims.Roles, Scopes: claims.Scopes()}, nil
+}
+
+const (
+	// ApiKeyHeader is the header of the API keys of service clients.
+	ApiKeyHeader = "X-API-Key"
+
+	// ApiKeySubjectPrefix starts the subject of the principals of API keys,
+	// followed by the id of the key.
+	ApiKeySubjectPrefix = "api_key:"
+)
+
+// ApiKey authenticates requests by the API key of their X-API-Key header. The
+// principal of a key has its scopes as permissions.
+type ApiKey struct {
+	svcApiKey service.ApiKeyServiceInterface
+}
+
+func NewApiKey(svcApiKey service.ApiKeyServiceInterface) *ApiKey {
+	return &ApiKey{svcApiKey: svcApiKey}
+}
+
+func (k *ApiKey) Authenticate(r *http.Request) (*principal.Principal, error) {
+	key := r.Header.Get(ApiKeyHeader)
+	if key == "" {
+		return nil, ErrNoCredentials
+	}
+
+	apiKey, err := k.svcApiKey.Authenticate(r.Context(), key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &principal.Principal{
+		Subject:     fmt.Sprintf("%s%d", ApiKeySubjectPrefix, apiKey.ID),
+		Scopes:      apiKey.ToDto().Scopes,
+		Permissions: apiKey.Permissions(),
+	}, nil
+}
+
+// New returns the authenticator of the API configured by conf, nil when
+// authentication is disabled. Users present JWTs, service clients the API keys
+// of svcApiKey.
+func New(ctx context.Context, conf *config.Conf, svcApiKey service.ApiKeyServiceInterface) (Authenticator, error) {
+	c := conf.Auth
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	jwtConf := jwt.Config{
+		Secret:   []byte(c.JWTSecret),
+		Issuer:   c.JWTIssuer,
+		Audience: c.JWTAudience,
+		Leeway:   c.JWTLeeway,
+	}
+	if c.JWTJWKS != "" {
+		jwks, err := jwt.NewJWKS(ctx, c.JWTJWKS)
+		if err != nil {
+			return nil, err
+		}
+		jwtConf.JWKS = jwks
+	}
+	if len(jwtConf.Secret) == 0 && jwtConf.JWKS == nil {
+		return nil, errors.New("authentication enabled without AUTH_JWT_SECRET nor AUTH_JWT_JWKS")
+	}
+
+	pol := policy.Default
+	if c.PolicyFile != "" {
+		var err error
+		if pol, err = policy.Load(c.PolicyFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return Chain{Grant(NewBearer(jwt.NewVerifier(jwtConf)), pol), NewApiKey(svcApiKey)}, nil
+}
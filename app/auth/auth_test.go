@@ -0,0 +1,182 @@
+package auth_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"myapp/app/auth"
+	"myapp/config"
+	mock_service "myapp/mocks/service"
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/jwt"
+	"myapp/util/policy"
+	"myapp/util/principal"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+// hs256 returns a token of the claims signed with secret.
+func hs256(claims, secret string) string {
+	signed := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(claims))
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signed))
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestBearer_Authenticate(t *testing.T) {
+	exp := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	bearer := auth.NewBearer(jwt.NewVerifier(jwt.Config{Secret: []byte("secret")}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		principal     *principal.Principal
+		wantErr       error
+	}{
+		{
+			name:          "valid token",
+			authorization: "Bearer " + hs256(`{"sub":"alice","exp":`+exp+`,"roles":["admin"],"scope":"books:read"}`, "secret"),
+			principal:     &principal.Principal{Subject: "alice", Roles: []string{"admin"}, Scopes: []string{"books:read"}},
+		},
+		{
+			name:          "case insensitive scheme",
+			authorization: "bearer " + hs256(`{"sub":"alice","exp":`+exp+`}`, "secret"),
+			principal:     &principal.Principal{Subject: "alice", Scopes: []string{}},
+		},
+		{
+			name:    "without header",
+			wantErr: auth.ErrNoCredentials,
+		},
+		{
+			name:          "other scheme",
+			authorization: "Basic YWxpY2U6cGFzcw==",
+			wantErr:       auth.ErrNoCredentials,
+		},
+		{
+			name:          "invalid token",
+			authorization: "Bearer " + hs256(`{"sub":"alice","exp":`+exp+`}`, "other"),
+			wantErr:       apperror.ErrUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+
+			p, err := auth.Chain{bearer}.Authenticate(r)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.principal, p)
+			}
+		})
+	}
+}
+
+func TestApiKey_Authenticate(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		principal   *principal.Principal
+		wantErr     error
+		prepareMock func(mockSvc *mock_service.MockApiKeyServiceInterface)
+	}{
+		{
+			name: "valid key",
+			key:  "mk_secret",
+			principal: &principal.Principal{
+				Subject:     "api_key:7",
+				Scopes:      []string{"books:read", "books:write"},
+				Permissions: []policy.Permission{policy.BooksRead, policy.BooksWrite},
+			},
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {
+				mockSvc.EXPECT().Authenticate(gomock.Any(), "mk_secret").Return(&model.ApiKey{Model: gorm.Model{ID: 7}, Scopes: "books:read books:write"}, nil)
+			},
+		},
+		{
+			name:        "without header",
+			wantErr:     auth.ErrNoCredentials,
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {},
+		},
+		{
+			name:    "revoked key",
+			key:     "mk_secret",
+			wantErr: apperror.ErrUnauthorized,
+			prepareMock: func(mockSvc *mock_service.MockApiKeyServiceInterface) {
+				mockSvc.EXPECT().Authenticate(gomock.Any(), "mk_secret").Return(nil, apperror.Unauthorized("api key revoked", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockSvc := mock_service.NewMockApiKeyServiceInterface(ctrl)
+			tt.prepareMock(mockSvc)
+
+			r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+			if tt.key != "" {
+				r.Header.Set(auth.ApiKeyHeader, tt.key)
+			}
+
+			p, err := auth.NewApiKey(mockSvc).Authenticate(r)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.principal, p)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockSvc := mock_service.NewMockApiKeyServiceInterface(ctrl)
+	mockSvc.EXPECT().Authenticate(gomock.Any(), "mk_secret").Return(&model.ApiKey{Model: gorm.Model{ID: 7}, Scopes: "books:read"}, nil)
+
+	conf := &config.Conf{}
+	conf.Auth.Enabled = true
+	conf.Auth.JWTSecret = "secret"
+
+	authn, err := auth.New(context.Background(), conf, mockSvc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Users are granted the permissions of their roles...
+	r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+	r.Header.Set("Authorization", "Bearer "+hs256(`{"sub":"alice","exp":`+strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)+`,"roles":["editor"]}`, "secret"))
+	p, err := authn.Authenticate(r)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []policy.Permission{policy.BooksRead, policy.BooksWrite}, p.Permissions)
+	}
+
+	// ...and service clients those of their key.
+	r, _ = http.NewRequest("GET", "/api/v1/books", nil)
+	r.Header.Set(auth.ApiKeyHeader, "mk_secret")
+	p, err = authn.Authenticate(r)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "api_key:7", p.Subject)
+		assert.Equal(t, []policy.Permission{policy.BooksRead}, p.Permissions)
+	}
+}
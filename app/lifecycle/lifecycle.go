@@ -0,0 +1,176 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"myapp/util/logger"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// Manager runs the HTTP servers of the process until SIGINT/SIGTERM is received
+// or a server fails, then shuts everything down: the servers and jobs within
+// the configured deadline, and then the shutdown hooks within a deadline of
+// their own.
+type Manager struct {
+	logger  logger.LoggerInterface
+	timeout time.Duration
+	servers []*http.Server
+	hooks   []hook
+	jobs    []job
+
+	stopJobs context.CancelFunc
+	jobsDone sync.WaitGroup
+}
+
+func New(l logger.LoggerInterface, shutdownTimeout time.Duration) *Manager {
+	return &Manager{
+		logger:  l,
+		timeout: shutdownTimeout,
+	}
+}
+
+// AddServer registers a server to be started by Run and gracefully shut down on exit.
+func (m *Manager) AddServer(s *http.Server) {
+	m.servers = append(m.servers, s)
+}
+
+// OnShutdown registers fn to be called once all servers are shut down.
+// Hooks are called in the order they were registered.
+func (m *Manager) OnShutdown(name string, fn func(ctx context.Context) error) {
+	m.hooks = append(m.hooks, hook{name: name, fn: fn})
+}
+
+// AddJob registers fn to be run every interval while the servers run. A run in
+// progress at shutdown is cancelled and waited for before the shutdown hooks.
+// The interval must be positive.
+func (m *Manager) AddJob(name string, interval time.Duration, fn func(ctx context.Context) error) error {
+	if interval <= 0 {
+		return fmt.Errorf("job %s: interval must be positive, got %s", name, interval)
+	}
+
+	m.jobs = append(m.jobs, job{name: name, interval: interval, fn: fn})
+
+	return nil
+}
+
+// Run starts the servers and blocks until the process is asked to stop.
+// It returns the error of the server which failed, if any.
+func (m *Manager) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return m.run(ctx)
+}
+
+func (m *Manager) run(ctx context.Context) error {
+	errCh := make(chan error, len(m.servers))
+
+	for _, s := range m.servers {
+		go func(s *http.Server) {
+			m.logger.Info().Msgf("Starting server %v", s.Addr)
+
+			if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(s)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(logger.NewContext(context.Background(), m.logger))
+	m.stopJobs = stopJobs
+	for _, j := range m.jobs {
+		m.jobsDone.Add(1)
+		go m.runJob(jobsCtx, j)
+	}
+
+	var runErr error
+	select {
+	case <-ctx.Done():
+		m.logger.Info().Msg("Shutdown signal received")
+	case runErr = <-errCh:
+		m.logger.Warn().Err(runErr).Msg("Server failed, shutting down")
+	}
+
+	m.shutdown()
+
+	return runErr
+}
+
+func (m *Manager) shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	// Stop accepting connections and wait for in-flight requests to complete,
+	// all servers at once so that each has the whole deadline.
+	var servers sync.WaitGroup
+	for _, s := range m.servers {
+		servers.Add(1)
+		go func(s *http.Server) {
+			defer servers.Done()
+
+			if err := s.Shutdown(ctx); err != nil {
+				m.logger.Warn().Err(err).Msgf("Server %v did not drain in time", s.Addr)
+				s.Close()
+			}
+		}(s)
+	}
+	servers.Wait()
+	m.logger.Info().Msg("Servers stopped")
+
+	m.stopJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		m.jobsDone.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-ctx.Done():
+		m.logger.Warn().Msg("Jobs did not stop in time")
+	}
+
+	// The hooks, e.g. the flush of the traces, get a deadline of their own
+	// rather than what the servers left of theirs.
+	hooksCtx, cancelHooks := context.WithTimeout(context.Background(), m.timeout)
+	defer cancelHooks()
+
+	for _, h := range m.hooks {
+		if err := h.fn(hooksCtx); err != nil {
+			m.logger.Warn().Err(err).Msgf("Shutdown of %s failed", h.name)
+		}
+	}
+}
+
+func (m *Manager) runJob(ctx context.Context, j job) {
+	defer m.jobsDone.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := j.fn(ctx); err != nil && ctx.Err() == nil {
+				m.logger.Warn().Err(err).Msgf("Job %s failed", j.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package lifecycle
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	mock_logger "myapp/mocks/util/logger"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	return l.Addr().String()
+}
+
+func newManager(t *testing.T, timeout time.Duration) *Manager {
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().Info().AnyTimes()
+	mockLogger.EXPECT().Warn().AnyTimes()
+
+	return New(mockLogger, timeout)
+}
+
+func TestManager_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	s := &http.Server{
+		Addr: freeAddr(t),
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+			io.WriteString(w, "done")
+		}),
+	}
+
+	var calls []string
+
+	m := newManager(t, 5*time.Second)
+	m.AddServer(s)
+	m.OnShutdown("first", func(ctx context.Context) error {
+		calls = append(calls, "first")
+		return nil
+	})
+	m.OnShutdown("second", func(ctx context.Context) error {
+		calls = append(calls, "second")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- m.run(ctx) }()
+
+	respBody := make(chan string)
+	go func() {
+		var resp *http.Response
+		var err error
+		for i := 0; i < 50; i++ {
+			if resp, err = http.Get("http://" + s.Addr); err == nil {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if err != nil {
+			respBody <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		respBody <- string(b)
+	}()
+
+	<-started
+	cancel()
+
+	// The in-flight request must complete even though shutdown has started.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	assert.Equal(t, "done", <-respBody)
+	assert.NoError(t, <-runErr)
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
+
+func TestManager_ReturnsServerError(t *testing.T) {
+	addr := freeAddr(t)
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	closed := false
+
+	m := newManager(t, time.Second)
+	m.AddServer(&http.Server{Addr: addr})
+	m.OnShutdown("database", func(ctx context.Context) error {
+		closed = true
+		return nil
+	})
+
+	err = m.run(context.Background())
+	assert.Error(t, err)
+	assert.True(t, closed)
+}
+
+func TestManager_RunsJobsUntilShutdown(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	var events []string
+
+	m := newManager(t, time.Second)
+	m.AddServer(&http.Server{Addr: freeAddr(t)})
+	err := m.AddJob("purge", 10*time.Millisecond, func(ctx context.Context) error {
+		runs <- struct{}{}
+		<-ctx.Done()
+		events = append(events, "job stopped")
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.OnShutdown("database", func(ctx context.Context) error {
+		events = append(events, "database closed")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- m.run(ctx) }()
+
+	<-runs
+	cancel()
+
+	assert.NoError(t, <-runErr)
+	assert.Equal(t, []string{"job stopped", "database closed"}, events)
+}
+
+func TestManager_AddJobRejectsInterval(t *testing.T) {
+	m := newManager(t, time.Second)
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		err := m.AddJob("purge", interval, func(ctx context.Context) error { return nil })
+		assert.Error(t, err, interval)
+	}
+	assert.Empty(t, m.jobs)
+}
+
+func TestManager_ShutsServersDownConcurrently(t *testing.T) {
+	const timeout = 300 * time.Millisecond
+
+	release := make(chan struct{})
+	defer close(release)
+
+	// Each server has a request which won't complete within the deadline.
+	var servers []*http.Server
+	started := make(chan struct{}, 2)
+	for i := 0; i < 2; i++ {
+		servers = append(servers, &http.Server{
+			Addr: freeAddr(t),
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				started <- struct{}{}
+				<-release
+			}),
+		})
+	}
+
+	var stopping time.Time
+	var hookErr error
+	hookDelay := make(chan time.Duration, 1)
+
+	m := newManager(t, timeout)
+	for _, s := range servers {
+		m.AddServer(s)
+	}
+	m.OnShutdown("tracing", func(ctx context.Context) error {
+		hookDelay <- time.Since(stopping)
+		hookErr = ctx.Err()
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- m.run(ctx) }()
+
+	for _, s := range servers {
+		go func(addr string) {
+			for i := 0; i < 50; i++ {
+				resp, err := http.Get("http://" + addr)
+				if err == nil {
+					resp.Body.Close()
+					return
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		}(s.Addr)
+	}
+	<-started
+	<-started
+
+	stopping = time.Now()
+	cancel()
+
+	assert.NoError(t, <-runErr)
+	// Shut down one after another, the servers would take twice the deadline.
+	assert.Less(t, int64(<-hookDelay), int64(2*timeout))
+	assert.NoError(t, hookErr)
+}
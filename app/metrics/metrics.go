@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "myapp"
+
+var sizeBuckets = prometheus.ExponentialBuckets(64, 4, 8)
+
+// Metrics exports the request measurements of requestlog and the DB pool stats to Prometheus.
+type Metrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	latency      *prometheus.HistogramVec
+	requestSize  *prometheus.HistogramVec
+	responseSize *prometheus.HistogramVec
+}
+
+func New(db *sql.DB, dbName string) *Metrics {
+	labels := []string{"route", "method", "status"}
+
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route pattern, method and status.",
+		}, labels),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		requestSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_size_bytes",
+			Help:      "Size of HTTP requests, headers included.",
+			Buckets:   sizeBuckets,
+		}, labels),
+		responseSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_response_size_bytes",
+			Help:      "Size of HTTP responses, headers included.",
+			Buckets:   sizeBuckets,
+		}, labels),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.latency,
+		m.requestSize,
+		m.responseSize,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{Namespace: namespace}),
+	)
+
+	if db != nil {
+		m.registry.MustRegister(collectors.NewDBStatsCollector(db, dbName))
+	}
+
+	return m
+}
+
+// ObserveRequest implements requestlog.Observer.
+func (m *Metrics) ObserveRequest(route, method string, status int, latency time.Duration, requestSize, responseSize int64) {
+	labels := prometheus.Labels{
+		"route":  route,
+		"method": method,
+		"status": strconv.Itoa(status),
+	}
+
+	m.requests.With(labels).Inc()
+	m.latency.With(labels).Observe(latency.Seconds())
+	m.requestSize.With(labels).Observe(float64(requestSize))
+	m.responseSize.With(labels).Observe(float64(responseSize))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often the memory store forgets the buckets which are
+// full again, so idle clients don't hold memory.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	limit   Limit
+	tokens  float64
+	updated time.Time
+}
+
+// refill adds the tokens earned since the last update, up to the limit.
+func (b *bucket) refill(now time.Time) {
+	rate := float64(b.limit.Requests) / b.limit.Period.Seconds()
+	b.tokens = math.Min(float64(b.limit.Requests), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+}
+
+// until returns the time until the bucket has tokens tokens.
+func (b *bucket) until(tokens float64) time.Duration {
+	if b.tokens >= tokens {
+		return 0
+	}
+
+	rate := float64(b.limit.Requests) / b.limit.Period.Seconds()
+	return time.Duration((tokens - b.tokens) / rate * float64(time.Second))
+}
+
+// MemoryStore keeps the buckets in memory, for a single instance of the API.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), lastSweep: time.Now(), now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok || b.limit != limit {
+		b = &bucket{limit: limit, tokens: float64(limit.Requests), updated: now}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	res := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = b.until(1)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = b.until(float64(limit.Requests))
+
+	return res, nil
+}
+
+// sweep forgets the buckets which are full at now.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.limit.Requests) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
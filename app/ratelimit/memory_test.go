@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 2, Period: time.Minute}
+
+	take := func(key string) Result {
+		res, err := store.Take(context.Background(), key, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	// The bucket starts full...
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: 30 * time.Second}, take("alice"))
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, take("alice"))
+
+	// ...then requests are refused until a token is earned back.
+	assert.Equal(t, Result{Limit: 2, Remaining: 0, Reset: time.Minute, RetryAfter: 30 * time.Second}, take("alice"))
+
+	// Clients have their own bucket.
+	assert.True(t, take("bob").Allowed)
+
+	now = now.Add(20 * time.Second)
+	assert.Equal(t, Result{Limit: 2, Remaining: 0, Reset: 40 * time.Second, RetryAfter: 10 * time.Second}, take("alice"))
+
+	now = now.Add(10 * time.Second)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: time.Minute}, take("alice"))
+}
+
+func TestMemoryStore_sweep(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	store.lastSweep = now
+
+	if _, err := store.Take(context.Background(), "alice", Limit{Requests: 1, Period: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Take(context.Background(), "bob", Limit{Requests: 1, Period: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(sweepInterval)
+	if _, err := store.Take(context.Background(), "carol", Limit{Requests: 1, Period: time.Second}); err != nil {
+		t.Fatal(err)
+	}
+
+	// bob's bucket is full again and forgotten, alice's isn't yet.
+	assert.Len(t, store.buckets, 2)
+	assert.Contains(t, store.buckets, "alice")
+	assert.Contains(t, store.buckets, "carol")
+}
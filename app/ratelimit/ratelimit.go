@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"myapp/app/auth"
+	"myapp/config"
+	"myapp/util/clientip"
+	"myapp/util/principal"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit allows Requests requests per Period to a client. It's a token bucket:
+// a client can burst its Requests, then its requests are spread over Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit parses a limit written as requests/period, e.g. "60/1m".
+func ParseLimit(s string) (Limit, error) {
+	requests, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q: missing period", s)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n < 1 {
+		return Limit{}, fmt.Errorf("limit %q: requests must be a positive integer", s)
+	}
+
+	d, err := time.ParseDuration(strings.TrimSpace(period))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: period must be a positive duration", s)
+	}
+
+	return Limit{Requests: n, Period: d}, nil
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Period)
+}
+
+// Result is the state of the bucket of a client after taking a request from it.
+type Result struct {
+	Allowed bool
+	Limit   int
+
+	// Remaining is the number of requests the client can make at once.
+	Remaining int
+
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+
+	// RetryAfter is the time until the next request is allowed, when this one
+	// isn't.
+	RetryAfter time.Duration
+}
+
+// Store holds the buckets of the clients. It's shared by the instances of the
+// API when it's backed by a shared service.
+type Store interface {
+	// Take takes a request from the bucket of key, whose limit is limit.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Limiter throttles the API requests of the clients, identified by their API
+// key or else their IP. The routes with a limit of their own each have a bucket
+// per client; the others share one with the default limit. Before
+// authentication, every request is also taken from the bucket of its IP, so
+// failed authentications are throttled too.
+type Limiter struct {
+	store    Store
+	clientIP *clientip.Resolver
+	ip       Limit
+	def      Limit
+	routes   map[string]Limit
+}
+
+// New returns the limiter configured by conf, whose buckets are kept by store;
+// nil when rate limiting is disabled.
+func New(conf *config.Conf, store Store) (*Limiter, error) {
+	if !conf.RateLimit.Enabled {
+		return nil, nil
+	}
+
+	ip, err := ParseLimit(conf.RateLimit.IP)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit: ip: %w", err)
+	}
+
+	def, err := ParseLimit(conf.RateLimit.Default)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	routes := make(map[string]Limit, len(conf.RateLimit.Routes))
+	for _, s := range conf.RateLimit.Routes {
+		route, limit, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit: route %q: missing limit", s)
+		}
+
+		method, pattern, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !strings.HasPrefix(pattern, "/") {
+			return nil, fmt.Errorf("rate limit: route %q: must be a method and a pattern", s)
+		}
+
+		l, err := ParseLimit(limit)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit: route %q: %w", s, err)
+		}
+		routes[Route(method, pattern)] = l
+	}
+
+	clientIP, err := clientip.New(conf.RateLimit.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("rate limit: %w", err)
+	}
+
+	return &Limiter{store: store, clientIP: clientIP, ip: ip, def: def, routes: routes}, nil
+}
+
+// Route returns the name of the route of method and pattern in the
+// configuration, e.g. "GET /api/v1/books".
+func Route(method, pattern string) string {
+	return strings.ToUpper(method) + " " + pattern
+}
+
+// CheckRoutes returns an error when a route of the configuration isn't one of
+// routes, most likely a typo which would leave the route unlimited.
+func (l *Limiter) CheckRoutes(routes []string) error {
+	known := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		known[route] = true
+	}
+
+	for route := range l.routes {
+		if !known[route] {
+			return fmt.Errorf("rate limit: route %q: no such route", route)
+		}
+	}
+
+	return nil
+}
+
+// TakeIP takes the request r from the bucket of its IP, whatever its route.
+func (l *Limiter) TakeIP(r *http.Request) (Result, error) {
+	return l.store.Take(r.Context(), "ip|"+l.clientIP.IP(r), l.ip)
+}
+
+// Take takes the request r to route from the bucket of its client.
+func (l *Limiter) Take(r *http.Request, route string) (Result, error) {
+	client := "ip:" + l.clientIP.IP(r)
+	if p := principal.FromContext(r.Context()); p != nil && strings.HasPrefix(p.Subject, auth.ApiKeySubjectPrefix) {
+		client = p.Subject
+	}
+
+	limit, ok := l.routes[route]
+	if !ok {
+		limit, route = l.def, "*"
+	}
+
+	return l.store.Take(r.Context(), route+"|"+client, limit)
+}
@@ -0,0 +1,215 @@
+package ratelimit_test
+
+import (
+	"context"
+	"myapp/app/ratelimit"
+	"myapp/config"
+	"myapp/util/principal"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		limit   string
+		want    ratelimit.Limit
+		wantErr string
+	}{
+		{limit: "60/1m", want: ratelimit.Limit{Requests: 60, Period: time.Minute}},
+		{limit: " 5 / 1s ", want: ratelimit.Limit{Requests: 5, Period: time.Second}},
+		{limit: "60", wantErr: `limit "60": missing period`},
+		{limit: "0/1m", wantErr: `limit "0/1m": requests must be a positive integer`},
+		{limit: "60/minute", wantErr: `limit "60/minute": period must be a positive duration`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.limit, func(t *testing.T) {
+			got, err := ratelimit.ParseLimit(tt.limit)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		conf    func(conf *config.Conf)
+		wantErr string
+	}{
+		{
+			name: "disabled",
+			conf: func(conf *config.Conf) { conf.RateLimit.Enabled = false },
+		},
+		{
+			name:    "invalid ip limit",
+			conf:    func(conf *config.Conf) { conf.RateLimit.IP = "600" },
+			wantErr: `rate limit: ip: limit "600": missing period`,
+		},
+		{
+			name:    "invalid default",
+			conf:    func(conf *config.Conf) { conf.RateLimit.Default = "fast" },
+			wantErr: `rate limit: limit "fast": missing period`,
+		},
+		{
+			name:    "route without method",
+			conf:    func(conf *config.Conf) { conf.RateLimit.Routes = []string{"/api/v1/books=60/1m"} },
+			wantErr: `rate limit: route "/api/v1/books=60/1m": must be a method and a pattern`,
+		},
+		{
+			name:    "route without limit",
+			conf:    func(conf *config.Conf) { conf.RateLimit.Routes = []string{"GET /api/v1/books"} },
+			wantErr: `rate limit: route "GET /api/v1/books": missing limit`,
+		},
+		{
+			name:    "invalid trusted proxy",
+			conf:    func(conf *config.Conf) { conf.RateLimit.TrustedProxies = []string{"proxy"} },
+			wantErr: `rate limit: trusted proxy "proxy": invalid IP address`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Conf{}
+			conf.RateLimit.Enabled = true
+			conf.RateLimit.IP = "600/1m"
+			conf.RateLimit.Default = "300/1m"
+			tt.conf(conf)
+
+			l, err := ratelimit.New(conf, ratelimit.NewMemoryStore())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Nil(t, l)
+		})
+	}
+}
+
+// storeFunc is a Store recording the keys and limits taken from.
+type storeFunc func(key string, limit ratelimit.Limit)
+
+func (f storeFunc) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	f(key, limit)
+	return ratelimit.Result{Allowed: true}, nil
+}
+
+func TestLimiter_Take(t *testing.T) {
+	tests := []struct {
+		name      string
+		route     string
+		principal *principal.Principal
+		wantKey   string
+		wantLimit ratelimit.Limit
+	}{
+		{
+			name:      "route limit",
+			route:     "GET /api/v1/books",
+			wantKey:   "GET /api/v1/books|ip:198.51.100.1",
+			wantLimit: ratelimit.Limit{Requests: 60, Period: time.Minute},
+		},
+		{
+			name:      "default limit",
+			route:     "GET /api/v1/books/{id}",
+			wantKey:   "*|ip:198.51.100.1",
+			wantLimit: ratelimit.Limit{Requests: 300, Period: time.Minute},
+		},
+		{
+			name:      "user",
+			route:     "GET /api/v1/books",
+			principal: &principal.Principal{Subject: "alice"},
+			wantKey:   "GET /api/v1/books|ip:198.51.100.1",
+			wantLimit: ratelimit.Limit{Requests: 60, Period: time.Minute},
+		},
+		{
+			name:      "api key",
+			route:     "GET /api/v1/books",
+			principal: &principal.Principal{Subject: "api_key:7"},
+			wantKey:   "GET /api/v1/books|api_key:7",
+			wantLimit: ratelimit.Limit{Requests: 60, Period: time.Minute},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &config.Conf{}
+			conf.RateLimit.Enabled = true
+			conf.RateLimit.IP = "600/1m"
+			conf.RateLimit.Default = "300/1m"
+			conf.RateLimit.Routes = []string{"GET /api/v1/books=60/1m"}
+			conf.RateLimit.TrustedProxies = []string{"10.0.0.0/8"}
+
+			var gotKey string
+			var gotLimit ratelimit.Limit
+			l, err := ratelimit.New(conf, storeFunc(func(key string, limit ratelimit.Limit) {
+				gotKey, gotLimit = key, limit
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+			r.RemoteAddr = "10.0.0.2:51234"
+			r.Header.Set("X-Forwarded-For", "198.51.100.1")
+			if tt.principal != nil {
+				r = r.WithContext(principal.NewContext(r.Context(), tt.principal))
+			}
+
+			if _, err := l.Take(r, tt.route); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.wantKey, gotKey)
+			assert.Equal(t, tt.wantLimit, gotLimit)
+		})
+	}
+}
+
+func TestLimiter_TakeIP(t *testing.T) {
+	conf := &config.Conf{}
+	conf.RateLimit.Enabled = true
+	conf.RateLimit.IP = "600/1m"
+	conf.RateLimit.Default = "300/1m"
+
+	var gotKey string
+	var gotLimit ratelimit.Limit
+	l, err := ratelimit.New(conf, storeFunc(func(key string, limit ratelimit.Limit) {
+		gotKey, gotLimit = key, limit
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+	r.RemoteAddr = "198.51.100.1:51234"
+	r = r.WithContext(principal.NewContext(r.Context(), &principal.Principal{Subject: "api_key:7"}))
+
+	if _, err := l.TakeIP(r); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "ip|198.51.100.1", gotKey)
+	assert.Equal(t, ratelimit.Limit{Requests: 600, Period: time.Minute}, gotLimit)
+}
+
+func TestLimiter_CheckRoutes(t *testing.T) {
+	conf := &config.Conf{}
+	conf.RateLimit.Enabled = true
+	conf.RateLimit.IP = "600/1m"
+	conf.RateLimit.Default = "300/1m"
+	conf.RateLimit.Routes = []string{"GET /api/v1/book=60/1m"}
+
+	l, err := ratelimit.New(conf, ratelimit.NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.EqualError(t, l.CheckRoutes([]string{"GET /api/v1/books"}), `rate limit: route "GET /api/v1/book": no such route`)
+	assert.NoError(t, l.CheckRoutes([]string{"GET /api/v1/book", "GET /api/v1/books"}))
+}
@@ -7,17 +7,27 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/go-chi/chi"
 )
 
+// Observer receives the measurements of every logged request, e.g. to export them as metrics.
+type Observer interface {
+	ObserveRequest(route, method string, status int, latency time.Duration, requestSize, responseSize int64)
+}
+
 type Handler struct {
-	handler http.Handler
-	logger  logger.LoggerInterface
+	handler  http.Handler
+	logger   logger.LoggerInterface
+	observer Observer
 }
 
-func NewHandler(h http.HandlerFunc, l logger.LoggerInterface) *Handler {
+// NewHandler wraps h to log every request; o may be nil.
+func NewHandler(h http.HandlerFunc, l logger.LoggerInterface, o Observer) *Handler {
 	return &Handler{
-		handler: h,
-		logger:  l,
+		handler:  h,
+		logger:   l,
+		observer: o,
 	}
 }
 
@@ -44,10 +54,28 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	r2.Body = rcc
 	w2 := &responseStats{w: w}
 
+	// A handler aborting the response panics, with http.ErrAbortHandler for a
+	// deliberate abort; the request is still logged and observed as the panic
+	// goes on to the server.
+	completed := false
+	defer func() {
+		if !completed {
+			le.Aborted = true
+			h.record(r, le, start, rcc, w2)
+		}
+	}()
+
 	h.handler.ServeHTTP(w2, r2)
+	completed = true
+
+	h.record(r, le, start, rcc, w2)
+}
 
+// record completes the log entry of the request r once it's served, logs and
+// observes it.
+func (h *Handler) record(r *http.Request, le *logEntry, start time.Time, rcc *readCounterCloser, w2 *responseStats) {
 	le.Latency = time.Since(start)
-	if rcc.err == nil && rcc.r != nil {
+	if rcc.err == nil && rcc.r != nil && !le.Aborted {
 		// If the handler hasn't encountered an error in the Body (like EOF),
 		// then consume the rest of the Body to provide an accurate rcc.n.
 		io.Copy(ioutil.Discard, rcc)
@@ -58,10 +86,28 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		le.Status = http.StatusOK
 	}
 	le.ResponseHeaderSize, le.ResponseBodySize = w2.size()
-	h.logger.Info().
+
+	// The route pattern is only complete once the router has matched the request.
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		le.RoutePattern = rctx.RoutePattern()
+	}
+
+	if h.observer != nil {
+		h.observer.ObserveRequest(
+			le.RoutePattern,
+			le.RequestMethod,
+			le.Status,
+			le.Latency,
+			le.RequestHeaderSize+le.RequestBodySize,
+			le.ResponseHeaderSize+le.ResponseBodySize,
+		)
+	}
+
+	h.logger.WithContext(r.Context()).Info().
 		Time("received_time", le.ReceivedTime).
 		Str("method", le.RequestMethod).
 		Str("url", le.RequestURL).
+		Str("route", le.RoutePattern).
 		Int64("header_size", le.RequestHeaderSize).
 		Int64("body_size", le.RequestBodySize).
 		Str("agent", le.UserAgent).
@@ -73,5 +119,6 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		Int64("resp_header_size", le.ResponseHeaderSize).
 		Int64("resp_body_size", le.ResponseBodySize).
 		Dur("latency", le.Latency).
+		Bool("aborted", le.Aborted).
 		Msg("")
 }
@@ -0,0 +1,47 @@
+package requestlog_test
+
+import (
+	"myapp/app/requestlog"
+	mock_logger "myapp/mocks/util/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type observerFunc func(route, method string, status int, latency time.Duration, requestSize, responseSize int64)
+
+func (f observerFunc) ObserveRequest(route, method string, status int, latency time.Duration, requestSize, responseSize int64) {
+	f(route, method, status, latency, requestSize, responseSize)
+}
+
+func TestHandler_Aborted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger)
+	mockLogger.EXPECT().Info()
+
+	var observed []int
+	o := observerFunc(func(route, method string, status int, latency time.Duration, requestSize, responseSize int64) {
+		observed = append(observed, status)
+	})
+
+	h := requestlog.NewHandler(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("id,title\n"))
+		panic(http.ErrAbortHandler)
+	}, mockLogger, o)
+
+	r, _ := http.NewRequest("GET", "/api/v1/books/export", nil)
+
+	defer func() {
+		// The abort goes on to the server once the request is recorded.
+		assert.Equal(t, http.ErrAbortHandler, recover())
+		assert.Equal(t, []int{http.StatusOK}, observed)
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), r)
+}
@@ -12,6 +12,7 @@ type logEntry struct {
 	ReceivedTime      time.Time
 	RequestMethod     string
 	RequestURL        string
+	RoutePattern      string
 	RequestHeaderSize int64
 	RequestBodySize   int64
 	UserAgent         string
@@ -25,6 +26,9 @@ type logEntry struct {
 	ResponseHeaderSize int64
 	ResponseBodySize   int64
 	Latency            time.Duration
+
+	// Aborted is set when the handler aborted the response midway.
+	Aborted bool
 }
 
 func ipFromHostPort(hp string) string {
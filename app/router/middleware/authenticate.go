@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"errors"
+	"myapp/app/app"
+	"myapp/app/auth"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"myapp/util/principal"
+	"net/http"
+	"strconv"
+)
+
+// Authenticate refuses the requests authn can't authenticate with a 401
+// problem, and stores the principal of the others in their context; it is also
+// their actor.
+func Authenticate(a *app.App, authn auth.Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p, err := authn.Authenticate(r)
+			if errors.Is(err, auth.ErrNoCredentials) {
+				err = apperror.Unauthorized("missing credentials", nil)
+			}
+			if e, ok := apperror.As(err); ok && e.Code == apperror.CodeUnauthorized {
+				challenge := `Bearer realm="myapp"`
+				if r.Header.Get("Authorization") != "" {
+					challenge += `, error="invalid_token", error_description=` + strconv.Quote(e.Message)
+				}
+				w.Header().Set("WWW-Authenticate", challenge)
+			}
+			if err != nil {
+				app.RespondError(w, r, a, err)
+				return
+			}
+
+			ctx := principal.NewContext(r.Context(), p)
+			ctx = actor.NewContext(ctx, p.Subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"errors"
+	"myapp/app/app"
+	"myapp/app/auth"
+	"myapp/app/router/middleware"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"myapp/util/principal"
+	"myapp/util/validator"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type authenticatorFunc func(r *http.Request) (*principal.Principal, error)
+
+func (f authenticatorFunc) Authenticate(r *http.Request) (*principal.Principal, error) {
+	return f(r)
+}
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name            string
+		authorization   string
+		authn           authenticatorFunc
+		statusCode      int
+		wwwAuthenticate string
+		body            string
+		actor           string
+	}{
+		{
+			name: "authenticated",
+			authn: func(r *http.Request) (*principal.Principal, error) {
+				return &principal.Principal{Subject: "alice", Roles: []string{"editor"}}, nil
+			},
+			statusCode: http.StatusOK,
+			actor:      "alice",
+		},
+		{
+			name: "without credentials",
+			authn: func(r *http.Request) (*principal.Principal, error) {
+				return nil, auth.ErrNoCredentials
+			},
+			statusCode:      http.StatusUnauthorized,
+			wwwAuthenticate: `Bearer realm="myapp"`,
+			body:            `{"type":"urn:myapp:problem:unauthorized","title":"Authentication required","status":401,"detail":"missing credentials","instance":"/api/v1/books","code":"unauthorized"}`,
+		},
+		{
+			name:          "invalid credentials",
+			authorization: "Bearer token",
+			authn: func(r *http.Request) (*principal.Principal, error) {
+				return nil, apperror.Unauthorized("invalid token: token expired", nil)
+			},
+			statusCode:      http.StatusUnauthorized,
+			wwwAuthenticate: `Bearer realm="myapp", error="invalid_token", error_description="invalid token: token expired"`,
+			body:            `{"type":"urn:myapp:problem:unauthorized","title":"Authentication required","status":401,"detail":"invalid token: token expired","instance":"/api/v1/books","code":"unauthorized"}`,
+		},
+		{
+			name: "invalid api key",
+			authn: func(r *http.Request) (*principal.Principal, error) {
+				return nil, apperror.Unauthorized("api key revoked", nil)
+			},
+			statusCode:      http.StatusUnauthorized,
+			wwwAuthenticate: `Bearer realm="myapp"`,
+			body:            `{"type":"urn:myapp:problem:unauthorized","title":"Authentication required","status":401,"detail":"api key revoked","instance":"/api/v1/books","code":"unauthorized"}`,
+		},
+		{
+			name: "authentication failure",
+			authn: func(r *http.Request) (*principal.Principal, error) {
+				return nil, errors.New("jwks unreachable")
+			},
+			statusCode: http.StatusInternalServerError,
+			body:       `{"type":"about:blank","title":"Internal Server Error","status":500,"instance":"/api/v1/books","code":"internal"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+
+			var ctxActor string
+			var ctxPrincipal *principal.Principal
+			middleware.Authenticate(a, tt.authn)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxActor = actor.FromContext(r.Context())
+				ctxPrincipal = principal.FromContext(r.Context())
+			})).ServeHTTP(rr, r)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.Equal(t, tt.wwwAuthenticate, rr.Header().Get("WWW-Authenticate"))
+			if tt.body != "" {
+				assert.Equal(t, app.ProblemContentType, rr.Header().Get("Content-Type"))
+				assert.JSONEq(t, tt.body, rr.Body.String())
+			}
+			if tt.actor != "" {
+				assert.Equal(t, tt.actor, ctxActor)
+				if assert.NotNil(t, ctxPrincipal) {
+					assert.Equal(t, tt.actor, ctxPrincipal.Subject)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"fmt"
+	"myapp/app/app"
+	"myapp/util/apperror"
+	"myapp/util/policy"
+	"myapp/util/principal"
+	"net/http"
+)
+
+// Authorize refuses the requests whose principal lacks perm with a 403
+// problem. Requests without principal are let through: authentication is
+// disabled.
+func Authorize(a *app.App, perm policy.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if p := principal.FromContext(r.Context()); p != nil && !p.Can(perm) {
+				app.RespondError(w, r, a, apperror.Forbidden(fmt.Sprintf("missing permission %s", perm), nil))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware_test
+
+import (
+	"context"
+	"myapp/app/app"
+	"myapp/app/router/middleware"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/util/policy"
+	"myapp/util/principal"
+	"myapp/util/validator"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorize(t *testing.T) {
+	tests := []struct {
+		name       string
+		principal  *principal.Principal
+		statusCode int
+		body       string
+	}{
+		{
+			name:       "granted",
+			principal:  &principal.Principal{Subject: "alice", Permissions: []policy.Permission{policy.BooksRead, policy.BooksDelete}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "denied",
+			principal:  &principal.Principal{Subject: "alice", Permissions: []policy.Permission{policy.BooksRead}},
+			statusCode: http.StatusForbidden,
+			body:       `{"type":"urn:myapp:problem:forbidden","title":"Permission denied","status":403,"detail":"missing permission books:delete","instance":"/api/v1/books/1","code":"forbidden"}`,
+		},
+		{
+			name:       "authentication disabled",
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			r, _ := http.NewRequest("DELETE", "/api/v1/books/1", nil)
+			if tt.principal != nil {
+				r = r.WithContext(principal.NewContext(context.Background(), tt.principal))
+			}
+			rr := httptest.NewRecorder()
+
+			middleware.Authorize(a, policy.BooksDelete)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})).ServeHTTP(rr, r)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			if tt.body != "" {
+				assert.JSONEq(t, tt.body, rr.Body.String())
+			}
+		})
+	}
+}
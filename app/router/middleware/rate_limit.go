@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"myapp/app/app"
+	"myapp/app/ratelimit"
+	"myapp/util/apperror"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit refuses the requests to route of the clients which exhausted
+// their limit with a 429 problem, and tells the others how many requests they
+// have left in the RateLimit-* headers.
+func RateLimit(a *app.App, l *ratelimit.Limiter, route string) func(http.Handler) http.Handler {
+	return rateLimit(a, func(r *http.Request) (ratelimit.Result, error) {
+		return l.Take(r, route)
+	})
+}
+
+// RateLimitIP refuses the requests of the IPs which exhausted their limit,
+// whatever the route; it goes before authentication, so that invalid
+// credentials can't be tried without limit.
+func RateLimitIP(a *app.App, l *ratelimit.Limiter) func(http.Handler) http.Handler {
+	return rateLimit(a, l.TakeIP)
+}
+
+// rateLimit refuses the requests take reports as not allowed. Requests are let
+// through when the store of the limiter fails: it's better than refusing them
+// all.
+func rateLimit(a *app.App, take func(r *http.Request) (ratelimit.Result, error)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := take(r)
+			if err != nil {
+				a.Logger().WithContext(r.Context()).Warn().Err(err).Msg("Rate limit not applied")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", seconds(res.Reset))
+			if !res.Allowed {
+				w.Header().Set("Retry-After", seconds(res.RetryAfter))
+				app.RespondError(w, r, a, apperror.RateLimited(fmt.Sprintf("rate limit exceeded, retry in %ss", seconds(res.RetryAfter)), nil))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// seconds returns d in whole seconds, rounded up so clients don't retry too early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
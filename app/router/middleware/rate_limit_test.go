@@ -0,0 +1,108 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"myapp/app/app"
+	"myapp/app/ratelimit"
+	"myapp/app/router/middleware"
+	"myapp/config"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/util/validator"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingStore is a Store whose backend is unreachable.
+type failingStore struct{}
+
+func (failingStore) Take(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimit(t *testing.T) {
+	type response struct {
+		statusCode int
+		limit      string
+		remaining  string
+		reset      string
+		retryAfter string
+		body       string
+	}
+	tests := []struct {
+		name      string
+		store     ratelimit.Store
+		responses []response
+	}{
+		{
+			name:  "limited",
+			store: ratelimit.NewMemoryStore(),
+			responses: []response{
+				{statusCode: http.StatusOK, limit: "2", remaining: "1", reset: "30"},
+				{statusCode: http.StatusOK, limit: "2", remaining: "0", reset: "60"},
+				{
+					statusCode: http.StatusTooManyRequests,
+					limit:      "2",
+					remaining:  "0",
+					reset:      "60",
+					retryAfter: "30",
+					body:       `{"type":"urn:myapp:problem:rate_limited","title":"Too many requests","status":429,"detail":"rate limit exceeded, retry in 30s","instance":"/api/v1/books","code":"rate_limited"}`,
+				},
+			},
+		},
+		{
+			name:  "store failure",
+			store: failingStore{},
+			responses: []response{
+				{statusCode: http.StatusOK},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+			mockLogger.EXPECT().Warn().AnyTimes()
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			conf := &config.Conf{}
+			conf.RateLimit.Enabled = true
+			conf.RateLimit.IP = "10/1m"
+			conf.RateLimit.Default = "2/1m"
+			l, err := ratelimit.New(conf, tt.store)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			h := middleware.RateLimit(a, l, "GET /api/v1/books")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			for _, want := range tt.responses {
+				r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+				r.RemoteAddr = "203.0.113.7:51234"
+				rr := httptest.NewRecorder()
+				h.ServeHTTP(rr, r)
+
+				assert.Equal(t, want.statusCode, rr.Code)
+				assert.Equal(t, want.limit, rr.Header().Get("RateLimit-Limit"))
+				assert.Equal(t, want.remaining, rr.Header().Get("RateLimit-Remaining"))
+				assert.Equal(t, want.reset, rr.Header().Get("RateLimit-Reset"))
+				assert.Equal(t, want.retryAfter, rr.Header().Get("Retry-After"))
+				if want.body != "" {
+					assert.JSONEq(t, want.body, rr.Body.String())
+				}
+			}
+		})
+	}
+}
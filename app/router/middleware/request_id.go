@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"myapp/util/logger"
+	"myapp/util/requestid"
+	"net/http"
+)
+
+// RequestID reuses the X-Request-ID of the request or generates one, stores it
+// in the request context and echoes it in the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestid.Header)
+		if !requestid.IsValid(id) {
+			id = requestid.New()
+		}
+
+		w.Header().Set(requestid.Header, id)
+		next.ServeHTTP(w, r.WithContext(requestid.NewContext(r.Context(), id)))
+	})
+}
+
+// ContextLogger stores a logger scoped to the request in its context, so the
+// service and repository layers log with the request ID through logger.Ctx.
+func ContextLogger(l logger.LoggerInterface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := logger.NewContext(r.Context(), l.WithContext(r.Context()))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package middleware_test
+
+import (
+	"myapp/app/router/middleware"
+	"myapp/util/requestid"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		reused bool
+	}{
+		{name: "reused", header: "3f2c1d-abc_1.2:3", reused: true},
+		{name: "generated", header: "", reused: false},
+		{name: "invalid replaced", header: "bad id\n", reused: false},
+		{name: "too long replaced", header: strings.Repeat("a", 129), reused: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/", nil)
+			if tt.header != "" {
+				r.Header.Set(requestid.Header, tt.header)
+			}
+			rr := httptest.NewRecorder()
+
+			var ctxID string
+			middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctxID = requestid.FromContext(r.Context())
+			})).ServeHTTP(rr, r)
+
+			respID := rr.Header().Get(requestid.Header)
+			if respID == "" || respID != ctxID {
+				t.Errorf("Wrong request ID: header %q, context %q", respID, ctxID)
+			}
+
+			if reused := respID == tt.header; reused != tt.reused {
+				t.Errorf("Wrong request ID reuse: got %v want %v", reused, tt.reused)
+			}
+		})
+	}
+}
@@ -2,27 +2,122 @@ package router
 
 import (
 	"myapp/app/app"
+	"myapp/app/auth"
+	"myapp/app/ratelimit"
 	"myapp/app/requestlog"
 	"myapp/app/router/middleware"
+	"myapp/model"
+	"myapp/util/policy"
+	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi"
 )
 
-func New(a *app.App) *chi.Mux {
+// apiPrefix is the prefix of the routes of the API.
+const apiPrefix = "/api/v1"
+
+// New builds the application router; measurements of the API requests are passed to o, which may be nil.
+// The API requests are authenticated by authn, unless it's nil, and each route
+// requires a permission of the principal. Clients are throttled by limiter,
+// unless it's nil.
+func New(a *app.App, o requestlog.Observer, authn auth.Authenticator, limiter *ratelimit.Limiter) *chi.Mux {
 	l := a.Logger()
 
 	r := chi.NewRouter()
+	r.Use(middleware.Tracing, middleware.RequestID, middleware.ContextLogger(l))
+	r.NotFound(a.HandleNotFound)
+	r.MethodNotAllowed(a.HandleMethodNotAllowed)
+
+	// Probes for the orchestrator, kept out of the access log
+	r.With(middleware.ContentTypeJson).Get("/healthz", a.HandleLiveness)
+	r.With(middleware.ContentTypeJson).Get("/readyz", a.HandleReadiness)
+
+	// handle routes the API requests of method and pattern to h, for the
+	// principals with perm.
+	handle := func(r chi.Router, method, pattern string, perm policy.Permission, h http.HandlerFunc) {
+		next := middleware.Authorize(a, perm)(h)
+		if limiter != nil {
+			next = middleware.RateLimit(a, limiter, ratelimit.Route(method, apiPrefix+pattern))(next)
+		}
+		r.Method(method, pattern, requestlog.NewHandler(next.ServeHTTP, l, o))
+	}
 
-	r.Route("/api/v1", func(r chi.Router) {
+	r.Route(apiPrefix, func(r chi.Router) {
 		r.Use(middleware.ContentTypeJson)
+		if limiter != nil {
+			r.Use(middleware.RateLimitIP(a, limiter))
+		}
+		if authn != nil {
+			r.Use(middleware.Authenticate(a, authn))
+		}
 
 		// Routes for books
-		r.Method("GET", "/books", requestlog.NewHandler(a.HandleListBooks, l))
-		r.Method("POST", "/books", requestlog.NewHandler(a.HandleCreateBook, l))
-		r.Method("GET", "/books/{id}", requestlog.NewHandler(a.HandleReadBook, l))
-		r.Method("PUT", "/books/{id}", requestlog.NewHandler(a.HandleUpdateBook, l))
-		r.Method("DELETE", "/books/{id}", requestlog.NewHandler(a.HandleDeleteBook, l))
+		handle(r, "GET", "/books", policy.BooksRead, a.HandleListBooks)
+		handle(r, "POST", "/books", policy.BooksWrite, a.HandleCreateBook)
+		handle(r, "POST", "/books:batch", policy.BooksWrite, a.HandleBatchBooks)
+		handle(r, "GET", "/books/search", policy.BooksRead, a.HandleSearchBooks)
+		handle(r, "GET", "/books/export", policy.BooksRead, a.HandleExportBooks)
+		handle(r, "POST", "/books/import", policy.BooksWrite, a.HandleImportBooks)
+		handle(r, "GET", "/books/isbn/{isbn}", policy.BooksRead, a.HandleReadBookByISBN)
+		handle(r, "GET", "/books/trash", policy.BooksDelete, a.HandleListDeletedBooks)
+		handle(r, "POST", "/books/trash/{id}/restore", policy.BooksDelete, a.HandleRestoreBook)
+		handle(r, "DELETE", "/books/trash/{id}", policy.BooksPurge, a.HandlePurgeBook)
+		handle(r, "GET", "/books/{id}", policy.BooksRead, a.HandleReadBook)
+		handle(r, "PUT", "/books/{id}", policy.BooksWrite, a.HandleUpdateBook)
+		handle(r, "PATCH", "/books/{id}", policy.BooksWrite, a.HandlePatchBook)
+		handle(r, "DELETE", "/books/{id}", policy.BooksDelete, a.HandleDeleteBook)
+		handle(r, "GET", "/books/{id}/history", policy.BooksRead, a.HandleBookHistory)
+		handle(r, "PUT", "/books/{id}/cover", policy.BooksWrite, a.HandleUploadCover)
+		handle(r, "GET", "/books/{id}/cover", policy.BooksRead, a.HandleReadCover)
+		handle(r, "GET", "/books/{id}/cover/{size}", policy.BooksRead, a.HandleReadCover)
+		handle(r, "DELETE", "/books/{id}/cover", policy.BooksWrite, a.HandleDeleteCover)
+
+		// Routes for tags and genres, and their books
+		for path, kind := range map[string]model.LabelKind{"tags": model.LabelTag, "genres": model.LabelGenre} {
+			handle(r, "GET", "/"+path, policy.BooksRead, a.HandleListLabels(kind))
+			handle(r, "POST", "/"+path, policy.BooksWrite, a.HandleCreateLabel(kind))
+			handle(r, "DELETE", "/"+path+"/{name}", policy.BooksDelete, a.HandleDeleteLabel(kind))
+			handle(r, "GET", "/books/{id}/"+path, policy.BooksRead, a.HandleListBookLabels(kind))
+			handle(r, "PUT", "/books/{id}/"+path+"/{name}", policy.BooksWrite, a.HandleAttachLabel(kind))
+			handle(r, "DELETE", "/books/{id}/"+path+"/{name}", policy.BooksWrite, a.HandleDetachLabel(kind))
+		}
+
+		// Routes for authors
+		handle(r, "GET", "/authors", policy.BooksRead, a.HandleListAuthors)
+		handle(r, "POST", "/authors", policy.BooksWrite, a.HandleCreateAuthor)
+		handle(r, "GET", "/authors/{id}", policy.BooksRead, a.HandleReadAuthor)
+		handle(r, "PUT", "/authors/{id}", policy.BooksWrite, a.HandleUpdateAuthor)
+		handle(r, "DELETE", "/authors/{id}", policy.BooksDelete, a.HandleDeleteAuthor)
+		handle(r, "GET", "/authors/{id}/books", policy.BooksRead, a.HandleListAuthorBooks)
+
+		// Routes for collections
+		handle(r, "GET", "/collections", policy.BooksRead, a.HandleListCollections)
+		handle(r, "POST", "/collections", policy.BooksWrite, a.HandleCreateCollection)
+		handle(r, "GET", "/collections/{id}", policy.BooksRead, a.HandleReadCollection)
+		handle(r, "PUT", "/collections/{id}", policy.BooksWrite, a.HandleUpdateCollection)
+		handle(r, "DELETE", "/collections/{id}", policy.BooksDelete, a.HandleDeleteCollection)
+
+		// Routes for the API keys of service clients
+		handle(r, "GET", "/api-keys", policy.ApiKeysManage, a.HandleListApiKeys)
+		handle(r, "POST", "/api-keys", policy.ApiKeysManage, a.HandleCreateApiKey)
+		handle(r, "PUT", "/api-keys/{id}/scopes", policy.ApiKeysManage, a.HandleUpdateApiKeyScopes)
+		handle(r, "DELETE", "/api-keys/{id}", policy.ApiKeysManage, a.HandleRevokeApiKey)
 	})
 
 	return r
 }
+
+// Routes returns the names of the API routes of r, e.g. "GET /api/v1/books",
+// as they're named in the configuration of the rate limits.
+func Routes(r chi.Routes) []string {
+	var routes []string
+	_ = chi.Walk(r, func(method, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		if strings.HasPrefix(route, apiPrefix+"/") {
+			routes = append(routes, ratelimit.Route(method, route))
+		}
+		return nil
+	})
+
+	return routes
+}
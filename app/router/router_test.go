@@ -0,0 +1,325 @@
+package router_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"myapp/app/app"
+	"myapp/app/auth"
+	"myapp/app/metrics"
+	"myapp/app/ratelimit"
+	"myapp/app/router"
+	"myapp/config"
+	mock_service "myapp/mocks/service"
+	mock_logger "myapp/mocks/util/logger"
+	"myapp/model"
+	"myapp/util/jwt"
+	"myapp/util/policy"
+	"myapp/util/principal"
+	"myapp/util/validator"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRouter_Problems(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+		body       string
+	}{
+		{
+			name:       "not found",
+			method:     "GET",
+			path:       "/api/v1/unknown",
+			statusCode: http.StatusNotFound,
+			body:       `{"type":"about:blank","title":"Not Found","status":404,"instance":"/api/v1/unknown","code":"not_found"}`,
+		},
+		{
+			name:       "method not allowed",
+			method:     "PATCH",
+			path:       "/api/v1/books",
+			statusCode: http.StatusMethodNotAllowed,
+			body:       `{"type":"about:blank","title":"Method Not Allowed","status":405,"instance":"/api/v1/books"}`,
+		},
+		{
+			name:       "batch method not allowed",
+			method:     "GET",
+			path:       "/api/v1/books:batch",
+			statusCode: http.StatusMethodNotAllowed,
+			body:       `{"type":"about:blank","title":"Method Not Allowed","status":405,"instance":"/api/v1/books:batch"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+
+			a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			router.New(a, nil, nil, nil).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+			assert.Equal(t, app.ProblemContentType, rr.Header().Get("Content-Type"))
+			assert.JSONEq(t, tt.body, rr.Body.String())
+		})
+	}
+}
+
+func TestRouter_Metrics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Info().AnyTimes()
+
+	mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+	mockBookService.EXPECT().GetBookByID(gomock.Any(), uint(5)).Return(&model.BookDto{ID: 5}, nil)
+
+	a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+	m := metrics.New(nil, "")
+
+	req, err := http.NewRequest("GET", "/api/v1/books/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router.New(a, m, nil, nil).ServeHTTP(httptest.NewRecorder(), req)
+
+	req, err = http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, req)
+
+	// Requests are labelled by route pattern, not by raw URL.
+	assert.Contains(t, rr.Body.String(), `myapp_http_requests_total{method="GET",route="/api/v1/books/{id}",status="200"} 1`)
+	assert.Contains(t, rr.Body.String(), `myapp_http_request_duration_seconds_count{method="GET",route="/api/v1/books/{id}",status="200"} 1`)
+}
+
+func TestRouter_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Info().AnyTimes()
+
+	mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+	mockBookService.EXPECT().GetBookByID(gomock.Any(), uint(5)).Return(&model.BookDto{ID: 5}, nil)
+
+	a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+	req, err := http.NewRequest("GET", "/api/v1/books/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.New(a, nil, nil, nil).ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		// The span continues the trace of the caller and is named after the route.
+		assert.Equal(t, "GET /api/v1/books/{id}", spans[0].Name())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	}
+}
+
+func TestRouter_Authentication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Info().AnyTimes()
+
+	a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+	authn := auth.NewBearer(jwt.NewVerifier(jwt.Config{Secret: []byte("secret")}))
+
+	req, err := http.NewRequest("DELETE", "/api/v1/books/5", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	router.New(a, nil, authn, nil).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.JSONEq(t, `{"type":"urn:myapp:problem:unauthorized","title":"Authentication required","status":401,"detail":"missing credentials","instance":"/api/v1/books/5","code":"unauthorized"}`, rr.Body.String())
+}
+
+func TestRouter_RateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Info().AnyTimes()
+
+	mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+	mockBookService.EXPECT().GetListBook(gomock.Any(), gomock.Any()).Return(&model.BookListDto{}, nil).AnyTimes()
+	mockBookService.EXPECT().GetBookByID(gomock.Any(), uint(5)).Return(&model.BookDto{ID: 5}, nil).AnyTimes()
+
+	a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+	conf := &config.Conf{}
+	conf.RateLimit.Enabled = true
+	conf.RateLimit.IP = "20/1m"
+	conf.RateLimit.Default = "10/1m"
+	conf.RateLimit.Routes = []string{"GET /api/v1/books=1/1m"}
+	limiter, err := ratelimit.New(conf, ratelimit.NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := router.New(a, nil, nil, limiter)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		req, err := http.NewRequest("GET", path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = "203.0.113.7:51234"
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/api/v1/books").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/books").Code)
+
+	// The other routes share the default limit.
+	rr := serve("/api/v1/books/5")
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "10", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "9", rr.Header().Get("RateLimit-Remaining"))
+}
+
+func TestRouter_RateLimitBeforeAuthentication(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+	mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+	mockLogger.EXPECT().Info().AnyTimes()
+
+	a := app.NewApp(mockLogger, validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+
+	authenticated := 0
+	authn := authenticatorFunc(func(r *http.Request) (*principal.Principal, error) {
+		authenticated++
+		return nil, auth.ErrNoCredentials
+	})
+
+	conf := &config.Conf{}
+	conf.RateLimit.Enabled = true
+	conf.RateLimit.IP = "2/1m"
+	conf.RateLimit.Default = "10/1m"
+	limiter, err := ratelimit.New(conf, ratelimit.NewMemoryStore())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := router.New(a, nil, authn, limiter)
+
+	var codes []int
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest("GET", "/api/v1/books", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.RemoteAddr = "203.0.113.7:51234"
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		codes = append(codes, rr.Code)
+	}
+
+	// Failed authentications count, and the last request is refused before
+	// reaching the authenticator.
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+	assert.Equal(t, 2, authenticated)
+}
+
+func TestRoutes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	a := app.NewApp(mock_logger.NewMockLoggerInterface(ctrl), validator.New(), mock_service.NewMockBookServiceInterface(ctrl), mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mock_service.NewMockApiKeyServiceInterface(ctrl), mock_service.NewMockHealthServiceInterface(ctrl))
+	routes := router.Routes(router.New(a, nil, nil, nil))
+
+	assert.Contains(t, routes, "GET /api/v1/books")
+	assert.Contains(t, routes, "DELETE /api/v1/books/trash/{id}")
+	assert.NotContains(t, routes, "GET /healthz")
+
+	// The routes limited by default exist.
+	for _, route := range []string{"GET /api/v1/books", "GET /api/v1/books/search", "GET /api/v1/books/export"} {
+		assert.Contains(t, routes, route)
+	}
+}
+
+type authenticatorFunc func(r *http.Request) (*principal.Principal, error)
+
+func (f authenticatorFunc) Authenticate(r *http.Request) (*principal.Principal, error) {
+	return f(r)
+}
+
+func TestRouter_Authorization(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		method     string
+		path       string
+		statusCode int
+	}{
+		{name: "reader reads", role: policy.RoleReader, method: "GET", path: "/api/v1/books/5", statusCode: http.StatusOK},
+		{name: "reader deletes", role: policy.RoleReader, method: "DELETE", path: "/api/v1/books/5", statusCode: http.StatusForbidden},
+		{name: "reader creates author", role: policy.RoleReader, method: "POST", path: "/api/v1/authors", statusCode: http.StatusForbidden},
+		{name: "editor purges", role: policy.RoleEditor, method: "DELETE", path: "/api/v1/books/trash/5", statusCode: http.StatusForbidden},
+		{name: "admin purges", role: policy.RoleAdmin, method: "DELETE", path: "/api/v1/books/trash/5", statusCode: http.StatusAccepted},
+		{name: "editor revokes api key", role: policy.RoleEditor, method: "DELETE", path: "/api/v1/api-keys/5", statusCode: http.StatusForbidden},
+		{name: "admin revokes api key", role: policy.RoleAdmin, method: "DELETE", path: "/api/v1/api-keys/5", statusCode: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockLogger := mock_logger.NewMockLoggerInterface(ctrl)
+			mockLogger.EXPECT().WithContext(gomock.Any()).Return(mockLogger).AnyTimes()
+			mockLogger.EXPECT().Info().AnyTimes()
+
+			mockBookService := mock_service.NewMockBookServiceInterface(ctrl)
+			mockBookService.EXPECT().GetBookByID(gomock.Any(), uint(5)).Return(&model.BookDto{ID: 5}, nil).AnyTimes()
+			mockBookService.EXPECT().PurgeBook(gomock.Any(), uint(5)).Return(nil).AnyTimes()
+
+			mockApiKeyService := mock_service.NewMockApiKeyServiceInterface(ctrl)
+			mockApiKeyService.EXPECT().RevokeApiKey(gomock.Any(), uint(5)).Return(nil).AnyTimes()
+
+			a := app.NewApp(mockLogger, validator.New(), mockBookService, mock_service.NewMockAuthorServiceInterface(ctrl), mock_service.NewMockLabelServiceInterface(ctrl), mock_service.NewMockCollectionServiceInterface(ctrl), mock_service.NewMockCoverServiceInterface(ctrl), mockApiKeyService, mock_service.NewMockHealthServiceInterface(ctrl))
+			authn := auth.Grant(authenticatorFunc(func(r *http.Request) (*principal.Principal, error) {
+				return &principal.Principal{Subject: "alice", Roles: []string{tt.role}}, nil
+			}), policy.Default)
+
+			req, err := http.NewRequest(tt.method, tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+			router.New(a, nil, authn, nil).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.statusCode, rr.Code)
+		})
+	}
+}
@@ -1,14 +1,25 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"myapp/app/auth"
+	"myapp/app/lifecycle"
+	"myapp/app/metrics"
+	"myapp/app/ratelimit"
+	"myapp/app/requestlog"
 	"myapp/app/router"
+	"myapp/app/tracing"
 	"myapp/config"
 	"myapp/repository"
 	"myapp/service"
+	"myapp/util/actor"
 	lr "myapp/util/logger"
+	vr "myapp/util/validator"
 	"net/http"
+	"time"
 
+	"myapp/adapter/blob"
 	dbConn "myapp/adapter/gorm"
 	"myapp/app/app"
 )
@@ -18,27 +29,89 @@ func main() {
 
 	logger := lr.New(appConf.Debug)
 
+	shutdownTracing, err := tracing.Setup(context.Background(), appConf)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Tracing setup failed")
+		return
+	}
+
 	conn, err := dbConn.New(appConf)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("")
 		return
 	}
-	if appConf.Debug {
-		conn.LogMode(true)
+
+	validator := vr.New()
+
+	authorRepo := repository.NewAuthorRepo(conn, appConf.Db.QueryTimeout)
+	svcAuthor := service.NewAuthorService(authorRepo)
+
+	labelRepo := repository.NewLabelRepo(conn, appConf.Db.QueryTimeout)
+	svcLabel := service.NewLabelService(labelRepo)
+
+	collectionRepo := repository.NewCollectionRepo(conn, appConf.Db.QueryTimeout)
+	svcCollection := service.NewCollectionService(collectionRepo)
+
+	coverStore, err := blob.New(appConf)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Cover storage setup failed")
+		return
 	}
 
-	db := repository.NewBookRepo(conn)
-	svcBook := service.NewBookService(db)
+	db := repository.NewBookRepo(conn, appConf.Db.QueryTimeout)
+	svcBook := service.NewBookService(db, coverStore, validator, appConf.Batch.MaxSize)
+	svcCover := service.NewCoverService(db, coverStore, appConf.Cover.MaxSize)
 
-	// validator := validator.New()
+	apiKeyRepo := repository.NewApiKeyRepo(conn, appConf.Db.QueryTimeout)
+	svcApiKey := service.NewApiKeyService(apiKeyRepo)
 
-	application := app.NewApp(logger, svcBook)
+	healthRepo := repository.NewHealthRepo(conn, appConf.Db.MigrationsDir)
+	svcHealth := service.NewHealthService(healthRepo)
 
-	appRouter := router.New(application)
+	application := app.NewApp(logger, validator, svcBook, svcAuthor, svcLabel, svcCollection, svcCover, svcApiKey, svcHealth)
 
-	address := fmt.Sprintf(":%d", appConf.Server.Port)
+	lc := lifecycle.New(logger, appConf.Server.TimeoutShutdown)
+
+	var observer requestlog.Observer
+	if appConf.Metrics.Port != 0 {
+		appMetrics := metrics.New(conn.DB(), appConf.Db.DbName)
+		observer = appMetrics
+
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/metrics", appMetrics.Handler())
+
+		lc.AddServer(&http.Server{
+			Addr:        fmt.Sprintf(":%d", appConf.Metrics.Port),
+			Handler:     metricsMux,
+			ReadTimeout: appConf.Server.TimeoutRead,
+			IdleTimeout: appConf.Server.TimeoutIdle,
+		})
+	}
 
-	logger.Info().Msgf("Starting server %v", address)
+	authn, err := auth.New(context.Background(), appConf, svcApiKey)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Authentication setup failed")
+		return
+	}
+	if authn == nil {
+		logger.Warn().Msg("Authentication disabled, the API is open to anyone")
+	}
+
+	limiter, err := ratelimit.New(appConf, ratelimit.NewMemoryStore())
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Rate limiting setup failed")
+		return
+	}
+
+	appRouter := router.New(application, observer, authn, limiter)
+	if limiter != nil {
+		if err := limiter.CheckRoutes(router.Routes(appRouter)); err != nil {
+			logger.Fatal().Err(err).Msg("Rate limiting setup failed")
+			return
+		}
+	}
+
+	address := fmt.Sprintf(":%d", appConf.Server.Port)
 
 	s := &http.Server{
 		Addr:         address,
@@ -48,7 +121,31 @@ func main() {
 		IdleTimeout:  appConf.Server.TimeoutIdle,
 	}
 
-	if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	lc.AddServer(s)
+
+	if retention := appConf.Trash.Retention; retention > 0 {
+		err := lc.AddJob("trash purge", appConf.Trash.PurgeInterval, func(ctx context.Context) error {
+			purged, err := svcBook.PurgeDeletedBooks(actor.NewContext(ctx, actor.System), time.Now().Add(-retention))
+			if purged > 0 {
+				logger.Info().Msgf("Purged %d books deleted more than %s ago", purged, retention)
+			}
+			return err
+		})
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Trash purge setup failed")
+			return
+		}
+	}
+
+	lc.OnShutdown("database", func(ctx context.Context) error {
+		return conn.Close()
+	})
+	lc.OnShutdown("tracing", shutdownTracing)
+	lc.OnShutdown("logger", func(ctx context.Context) error {
+		return logger.Sync()
+	})
+
+	if err := lc.Run(); err != nil {
 		logger.Fatal().Err(err).Msg("Server startup failed")
 	}
 }
@@ -8,9 +8,16 @@ import (
 )
 
 type Conf struct {
-	Server serverConf
-	Debug  bool `env:"DEBUG,required"`
-	Db     dbConf
+	Server    serverConf
+	Debug     bool `env:"DEBUG,required"`
+	Db        dbConf
+	Metrics   metricsConf
+	Tracing   tracingConf
+	Batch     batchConf
+	Trash     trashConf
+	Cover     coverConf
+	Auth      authConf
+	RateLimit rateLimitConf
 }
 
 type serverConf struct {
@@ -18,6 +25,103 @@ type serverConf struct {
 	TimeoutRead  time.Duration `env:"SERVER_TIMEOUT_READ,required"`
 	TimeoutWrite time.Duration `env:"SERVER_TIMEOUT_WRITE,required"`
 	TimeoutIdle  time.Duration `env:"SERVER_TIMEOUT_IDLE,required"`
+
+	// TimeoutShutdown bounds how long in-flight requests are drained on
+	// SIGINT/SIGTERM, and then how long the shutdown hooks run.
+	TimeoutShutdown time.Duration `env:"SERVER_TIMEOUT_SHUTDOWN,default=15s"`
+}
+
+type metricsConf struct {
+	// Port serves /metrics apart from the API so it isn't exposed publicly; 0 disables it.
+	Port int `env:"METRICS_PORT,default=9090"`
+}
+
+type batchConf struct {
+	// MaxSize is the maximum number of operations of a batch request.
+	MaxSize int `env:"BATCH_MAX_SIZE,default=1000"`
+}
+
+type trashConf struct {
+	// Retention is how long deleted books stay in the trash before the
+	// scheduled purge removes them for good; 0 disables the purge.
+	Retention time.Duration `env:"TRASH_RETENTION,default=720h"`
+
+	// PurgeInterval is how often the scheduled purge runs; it must be positive
+	// unless the purge is disabled.
+	PurgeInterval time.Duration `env:"TRASH_PURGE_INTERVAL,default=1h"`
+}
+
+type coverConf struct {
+	// Storage is where uploaded covers and their thumbnails are kept, "local"
+	// for LocalDir or "s3" for a bucket of an S3-compatible service.
+	Storage  string `env:"COVER_STORAGE,default=local"`
+	LocalDir string `env:"COVER_LOCAL_DIR,default=/myapp/covers"`
+
+	S3Endpoint  string `env:"COVER_S3_ENDPOINT"`
+	S3Region    string `env:"COVER_S3_REGION,default=us-east-1"`
+	S3Bucket    string `env:"COVER_S3_BUCKET"`
+	S3AccessKey string `env:"COVER_S3_ACCESS_KEY"`
+	S3SecretKey string `env:"COVER_S3_SECRET_KEY"`
+
+	// MaxSize is the maximum size in bytes of an uploaded cover, at most
+	// 32 MiB, the bound of the upload requests.
+	MaxSize int64 `env:"COVER_MAX_SIZE,default=10485760"`
+}
+
+type authConf struct {
+	// Enabled requires credentials on the API routes; disable it for local runs only.
+	Enabled bool `env:"AUTH_ENABLED,default=true"`
+
+	// JWTSecret is the key of HS256 tokens, JWTJWKS the file path or the
+	// http(s) URL of the JSON Web Key Set of RS256 tokens. One is required.
+	JWTSecret string `env:"AUTH_JWT_SECRET"`
+	JWTJWKS   string `env:"AUTH_JWT_JWKS"`
+
+	// JWTIssuer and JWTAudience, when set, must match the iss and aud claims.
+	JWTIssuer   string `env:"AUTH_JWT_ISSUER"`
+	JWTAudience string `env:"AUTH_JWT_AUDIENCE"`
+
+	// JWTLeeway is the clock skew tolerated on the exp and nbf claims.
+	JWTLeeway time.Duration `env:"AUTH_JWT_LEEWAY,default=1m"`
+
+	// PolicyFile is the JSON file of the permissions of the roles, see
+	// policy.Load; the reader, editor and admin roles of policy.Default
+	// apply when it's empty.
+	PolicyFile string `env:"AUTH_POLICY_FILE"`
+}
+
+type rateLimitConf struct {
+	// Enabled throttles the API requests of each client, identified by its
+	// API key or else its IP.
+	Enabled bool `env:"RATE_LIMIT_ENABLED,default=true"`
+
+	// IP is the limit of all the requests of an IP, taken before they're
+	// authenticated, as requests/period.
+	IP string `env:"RATE_LIMIT_IP,default=600/1m"`
+
+	// Default is the limit of the routes without their own, shared by them,
+	// as requests/period.
+	Default string `env:"RATE_LIMIT_DEFAULT,default=300/1m"`
+
+	// Routes are the limits of routes, as "METHOD pattern=requests/period"
+	// separated by semicolons; each route has its own bucket per client.
+	// Unknown routes fail the startup.
+	Routes []string `env:"RATE_LIMIT_ROUTES,default=GET /api/v1/books=60/1m;GET /api/v1/books/search=60/1m;GET /api/v1/books/export=10/1m"`
+
+	// TrustedProxies are the IPs or CIDRs of the proxies whose
+	// X-Forwarded-For header is believed, separated by semicolons.
+	TrustedProxies []string `env:"RATE_LIMIT_TRUSTED_PROXIES"`
+}
+
+type tracingConf struct {
+	// Exporter is one of "none", "stdout" for local runs, or "otlp".
+	Exporter     string `env:"TRACING_EXPORTER,default=none"`
+	OTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT,default=localhost:4318"`
+	OTLPInsecure bool   `env:"TRACING_OTLP_INSECURE,default=true"`
+	ServiceName  string `env:"TRACING_SERVICE_NAME,default=myapp"`
+
+	// SampleRatio is the fraction of new traces recorded; the decision of the caller is kept.
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO,default=1"`
 }
 
 type dbConf struct {
@@ -26,6 +130,13 @@ type dbConf struct {
 	Username string `env:"DB_USER,required"`
 	Password string `env:"DB_PASS,required"`
 	DbName   string `env:"DB_NAME,required"`
+
+	// QueryTimeout bounds each repository call, so a slow query is abandoned
+	// even if the client keeps waiting.
+	QueryTimeout time.Duration `env:"DB_QUERY_TIMEOUT,default=5s"`
+
+	// MigrationsDir holds the goose migrations the readiness check compares the schema against.
+	MigrationsDir string `env:"DB_MIGRATIONS_DIR,default=/myapp/migrations"`
 }
 
 func AppConfig() *Conf {
@@ -6,4 +6,5 @@ echo 'Deleting mysql-client...'
 apk del mysql-client
 
 echo 'Start application...'
-/myapp/app
\ No newline at end of file
+# exec so the application receives SIGTERM directly and can shut down gracefully
+exec /myapp/app
\ No newline at end of file
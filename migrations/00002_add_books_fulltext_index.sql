@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE books
+    ADD FULLTEXT INDEX ft_books_title_author_description (title, author, description);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE books
+    DROP INDEX ft_books_title_author_description;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+ALTER TABLE books
+    ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER description;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE books
+    DROP COLUMN version;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS book_audit
+(
+    id              BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    book_id         INT UNSIGNED    NOT NULL,
+    action          VARCHAR(16)     NOT NULL,
+    before_snapshot JSON            NULL,
+    after_snapshot  JSON            NULL,
+    actor           VARCHAR(255)    NOT NULL,
+    request_id      VARCHAR(128)    NOT NULL DEFAULT '',
+    created_at      TIMESTAMP       NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_book_audit_book_id (book_id, id)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS book_audit;
\ No newline at end of file
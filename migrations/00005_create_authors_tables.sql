@@ -0,0 +1,40 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS authors
+(
+    id         INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP    NOT NULL,
+    updated_at TIMESTAMP    NULL,
+    deleted_at TIMESTAMP    NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX uix_authors_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS book_authors
+(
+    book_id   INT UNSIGNED      NOT NULL,
+    author_id INT UNSIGNED      NOT NULL,
+    position  SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (book_id, author_id),
+    INDEX idx_book_authors_author_id (author_id, book_id),
+    CONSTRAINT fk_book_authors_book FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_authors_author FOREIGN KEY (author_id) REFERENCES authors (id)
+);
+
+-- Every distinct author name of the books becomes an author, books deleted
+-- to the trash included.
+INSERT INTO authors (name, created_at, updated_at)
+SELECT DISTINCT TRIM(author), NOW(), NOW()
+FROM books
+WHERE TRIM(author) <> '';
+
+INSERT INTO book_authors (book_id, author_id, position)
+SELECT books.id, authors.id, 0
+FROM books
+         JOIN authors ON authors.name = TRIM(books.author);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS book_authors;
+DROP TABLE IF EXISTS authors;
\ No newline at end of file
@@ -0,0 +1,46 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS tags
+(
+    id         INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(64)  NOT NULL,
+    created_at TIMESTAMP    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX uix_tags_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS book_tags
+(
+    book_id INT UNSIGNED NOT NULL,
+    tag_id  INT UNSIGNED NOT NULL,
+    PRIMARY KEY (book_id, tag_id),
+    INDEX idx_book_tags_tag_id (tag_id, book_id),
+    CONSTRAINT fk_book_tags_book FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS genres
+(
+    id         INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name       VARCHAR(64)  NOT NULL,
+    created_at TIMESTAMP    NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX uix_genres_name (name)
+);
+
+CREATE TABLE IF NOT EXISTS book_genres
+(
+    book_id  INT UNSIGNED NOT NULL,
+    genre_id INT UNSIGNED NOT NULL,
+    PRIMARY KEY (book_id, genre_id),
+    INDEX idx_book_genres_genre_id (genre_id, book_id),
+    CONSTRAINT fk_book_genres_book FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE CASCADE,
+    CONSTRAINT fk_book_genres_genre FOREIGN KEY (genre_id) REFERENCES genres (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS book_genres;
+DROP TABLE IF EXISTS genres;
+DROP TABLE IF EXISTS book_tags;
+DROP TABLE IF EXISTS tags;
\ No newline at end of file
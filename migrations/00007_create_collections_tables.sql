@@ -0,0 +1,29 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+CREATE TABLE IF NOT EXISTS collections
+(
+    id          INT UNSIGNED NOT NULL AUTO_INCREMENT,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT         NULL,
+    owner       VARCHAR(255) NOT NULL,
+    created_at  TIMESTAMP    NOT NULL,
+    updated_at  TIMESTAMP    NULL,
+    deleted_at  TIMESTAMP    NULL,
+    PRIMARY KEY (id)
+);
+
+CREATE TABLE IF NOT EXISTS collection_books
+(
+    collection_id INT UNSIGNED      NOT NULL,
+    book_id       INT UNSIGNED      NOT NULL,
+    position      SMALLINT UNSIGNED NOT NULL DEFAULT 0,
+    PRIMARY KEY (collection_id, book_id),
+    INDEX idx_collection_books_book_id (book_id),
+    CONSTRAINT fk_collection_books_collection FOREIGN KEY (collection_id) REFERENCES collections (id) ON DELETE CASCADE,
+    CONSTRAINT fk_collection_books_book FOREIGN KEY (book_id) REFERENCES books (id) ON DELETE CASCADE
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS collection_books;
+DROP TABLE IF EXISTS collections;
\ No newline at end of file
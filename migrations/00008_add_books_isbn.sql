@@ -0,0 +1,12 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- Books without an ISBN have a NULL one, which the unique index allows many of.
+ALTER TABLE books
+    ADD COLUMN isbn CHAR(13) NULL AFTER description,
+    ADD UNIQUE INDEX uix_books_isbn (isbn);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE books
+    DROP INDEX uix_books_isbn,
+    DROP COLUMN isbn;
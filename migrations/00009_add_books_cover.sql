@@ -0,0 +1,10 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- The cover itself is kept in the blob storage, an empty type meaning no cover.
+ALTER TABLE books
+    ADD COLUMN cover_type VARCHAR(32) NOT NULL DEFAULT '' AFTER isbn;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE books
+    DROP COLUMN cover_type;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- Editors may only modify the books they created; books created before are
+-- left to the admins.
+ALTER TABLE books
+    ADD COLUMN created_by VARCHAR(255) NOT NULL DEFAULT '' AFTER cover_type;
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE books
+    DROP COLUMN created_by;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- Only the SHA-256 of the keys is kept; their prefix tells them apart.
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           INT UNSIGNED  NOT NULL AUTO_INCREMENT,
+    name         VARCHAR(255)  NOT NULL,
+    prefix       VARCHAR(16)   NOT NULL,
+    key_hash     CHAR(64)      NOT NULL,
+    scopes       VARCHAR(1024) NOT NULL DEFAULT '',
+    created_by   VARCHAR(255)  NOT NULL,
+    last_used_at TIMESTAMP     NULL,
+    revoked_at   TIMESTAMP     NULL,
+    created_at   TIMESTAMP     NOT NULL,
+    updated_at   TIMESTAMP     NULL,
+    deleted_at   TIMESTAMP     NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX uix_api_keys_key_hash (key_hash)
+);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- Each upload of a cover is stored under its own keys, which the book
+-- references; existing covers keep the keys they were stored under.
+ALTER TABLE books
+    ADD COLUMN cover_key VARCHAR(64) NOT NULL DEFAULT '' AFTER cover_type;
+
+UPDATE books SET cover_key = CONCAT('covers/', id) WHERE cover_type <> '';
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+ALTER TABLE books
+    DROP COLUMN cover_key;
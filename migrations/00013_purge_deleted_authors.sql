@@ -0,0 +1,11 @@
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+-- Authors are now deleted for good, so that the name of a deleted author is
+-- free again under uix_authors_name; those deleted before go the same way.
+DELETE FROM authors
+WHERE deleted_at IS NOT NULL
+  AND id NOT IN (SELECT author_id FROM book_authors);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+-- The deleted authors are gone for good.
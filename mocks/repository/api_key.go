@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/api_key.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	model "myapp/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockApiKeyRepoInterface is a mock of ApiKeyRepoInterface interface.
+type MockApiKeyRepoInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyRepoInterfaceMockRecorder
+}
+
+// MockApiKeyRepoInterfaceMockRecorder is the mock recorder for MockApiKeyRepoInterface.
+type MockApiKeyRepoInterfaceMockRecorder struct {
+	mock *MockApiKeyRepoInterface
+}
+
+// NewMockApiKeyRepoInterface creates a new mock instance.
+func NewMockApiKeyRepoInterface(ctrl *gomock.Controller) *MockApiKeyRepoInterface {
+	mock := &MockApiKeyRepoInterface{ctrl: ctrl}
+	mock.recorder = &MockApiKeyRepoInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyRepoInterface) EXPECT() *MockApiKeyRepoInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateApiKey mocks base method.
+func (m *MockApiKeyRepoInterface) CreateApiKey(ctx context.Context, key *model.ApiKey) (*model.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKey", ctx, key)
+	ret0, _ := ret[0].(*model.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKey indicates an expected call of CreateApiKey.
+func (mr *MockApiKeyRepoInterfaceMockRecorder) CreateApiKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKey", reflect.TypeOf((*MockApiKeyRepoInterface)(nil).CreateApiKey), ctx, key)
+}
+
+// ListApiKeys mocks base method.
+func (m *MockApiKeyRepoInterface) ListApiKeys(ctx context.Context, query *model.PageQuery) (model.ApiKeys, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApiKeys", ctx, query)
+	ret0, _ := ret[0].(model.ApiKeys)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListApiKeys indicates an expected call of ListApiKeys.
+func (mr *MockApiKeyRepoInterfaceMockRecorder) ListApiKeys(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApiKeys", reflect.TypeOf((*MockApiKeyRepoInterface)(nil).ListApiKeys), ctx, query)
+}
+
+// ReadApiKeyByHash mocks base method.
+func (m *MockApiKeyRepoInterface) ReadApiKeyByHash(ctx context.Context, hash string) (*model.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadApiKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*model.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadApiKeyByHash indicates an expected call of ReadApiKeyByHash.
+func (mr *MockApiKeyRepoInterfaceMockRecorder) ReadApiKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadApiKeyByHash", reflect.TypeOf((*MockApiKeyRepoInterface)(nil).ReadApiKeyByHash), ctx, hash)
+}
+
+// RevokeApiKey mocks base method.
+func (m *MockApiKeyRepoInterface) RevokeApiKey(ctx context.Context, id uint, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApiKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeApiKey indicates an expected call of RevokeApiKey.
+func (mr *MockApiKeyRepoInterfaceMockRecorder) RevokeApiKey(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApiKey", reflect.TypeOf((*MockApiKeyRepoInterface)(nil).RevokeApiKey), ctx, id, at)
+}
+
+// TouchApiKey mocks base method.
+func (m *MockApiKeyRepoInterface) TouchApiKey(ctx context.Context, id uint, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchApiKey", ctx, id, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchApiKey indicates an expected call of TouchApiKey.
+func (mr *MockApiKeyRepoInterfaceMockRecorder) TouchApiKey(ctx, id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchApiKey", reflect.TypeOf((*MockApiKeyRepoInterface)(nil).TouchApiKey), ctx, id, at)
+}
+
+// UpdateApiKeyScopes mocks base method.
+func (m *MockApiKeyRepoInterface) UpdateApiKeyScopes(ctx context.Context, id uint, scopes string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApiKeyScopes", ctx, id, scopes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApiKeyScopes indicates an expected call of UpdateApiKeyScopes.
+func (mr *MockApiKeyRepoInterfaceMockRecorder) UpdateApiKeyScopes(ctx, id, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApiKeyScopes", reflect.TypeOf((*MockApiKeyRepoInterface)(nil).UpdateApiKeyScopes), ctx, id, scopes)
+}
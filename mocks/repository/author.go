@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/author.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	model "myapp/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuthorRepoInterface is a mock of AuthorRepoInterface interface.
+type MockAuthorRepoInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorRepoInterfaceMockRecorder
+}
+
+// MockAuthorRepoInterfaceMockRecorder is the mock recorder for MockAuthorRepoInterface.
+type MockAuthorRepoInterfaceMockRecorder struct {
+	mock *MockAuthorRepoInterface
+}
+
+// NewMockAuthorRepoInterface creates a new mock instance.
+func NewMockAuthorRepoInterface(ctrl *gomock.Controller) *MockAuthorRepoInterface {
+	mock := &MockAuthorRepoInterface{ctrl: ctrl}
+	mock.recorder = &MockAuthorRepoInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorRepoInterface) EXPECT() *MockAuthorRepoInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateAuthor mocks base method.
+func (m *MockAuthorRepoInterface) CreateAuthor(ctx context.Context, author *model.Author) (*model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", ctx, author)
+	ret0, _ := ret[0].(*model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockAuthorRepoInterfaceMockRecorder) CreateAuthor(ctx, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockAuthorRepoInterface)(nil).CreateAuthor), ctx, author)
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockAuthorRepoInterface) DeleteAuthor(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockAuthorRepoInterfaceMockRecorder) DeleteAuthor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockAuthorRepoInterface)(nil).DeleteAuthor), ctx, id)
+}
+
+// ListAuthorBooks mocks base method.
+func (m *MockAuthorRepoInterface) ListAuthorBooks(ctx context.Context, id uint, query *model.PageQuery) (model.Books, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorBooks", ctx, id, query)
+	ret0, _ := ret[0].(model.Books)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAuthorBooks indicates an expected call of ListAuthorBooks.
+func (mr *MockAuthorRepoInterfaceMockRecorder) ListAuthorBooks(ctx, id, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorBooks", reflect.TypeOf((*MockAuthorRepoInterface)(nil).ListAuthorBooks), ctx, id, query)
+}
+
+// ListAuthors mocks base method.
+func (m *MockAuthorRepoInterface) ListAuthors(ctx context.Context, query *model.PageQuery) (model.Authors, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthors", ctx, query)
+	ret0, _ := ret[0].(model.Authors)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAuthors indicates an expected call of ListAuthors.
+func (mr *MockAuthorRepoInterfaceMockRecorder) ListAuthors(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthors", reflect.TypeOf((*MockAuthorRepoInterface)(nil).ListAuthors), ctx, query)
+}
+
+// ReadAuthor mocks base method.
+func (m *MockAuthorRepoInterface) ReadAuthor(ctx context.Context, id uint) (*model.Author, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadAuthor", ctx, id)
+	ret0, _ := ret[0].(*model.Author)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadAuthor indicates an expected call of ReadAuthor.
+func (mr *MockAuthorRepoInterfaceMockRecorder) ReadAuthor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadAuthor", reflect.TypeOf((*MockAuthorRepoInterface)(nil).ReadAuthor), ctx, id)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockAuthorRepoInterface) UpdateAuthor(ctx context.Context, author *model.Author) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, author)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockAuthorRepoInterfaceMockRecorder) UpdateAuthor(ctx, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockAuthorRepoInterface)(nil).UpdateAuthor), ctx, author)
+}
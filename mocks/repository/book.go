@@ -7,7 +7,9 @@ package mock_repository
 import (
 	context "context"
 	model "myapp/model"
+	repository "myapp/repository"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +37,35 @@ func (m *MockBookRepoInterface) EXPECT() *MockBookRepoInterfaceMockRecorder {
 	return m.recorder
 }
 
+// BookAuthorIDs mocks base method.
+func (m *MockBookRepoInterface) BookAuthorIDs(ctx context.Context, bookID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookAuthorIDs", ctx, bookID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookAuthorIDs indicates an expected call of BookAuthorIDs.
+func (mr *MockBookRepoInterfaceMockRecorder) BookAuthorIDs(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookAuthorIDs", reflect.TypeOf((*MockBookRepoInterface)(nil).BookAuthorIDs), ctx, bookID)
+}
+
+// CreateAudits mocks base method.
+func (m *MockBookRepoInterface) CreateAudits(ctx context.Context, audits []*model.BookAudit) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAudits", ctx, audits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAudits indicates an expected call of CreateAudits.
+func (mr *MockBookRepoInterfaceMockRecorder) CreateAudits(ctx, audits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAudits", reflect.TypeOf((*MockBookRepoInterface)(nil).CreateAudits), ctx, audits)
+}
+
 // CreateBook mocks base method.
 func (m *MockBookRepoInterface) CreateBook(ctx context.Context, book *model.Book) (*model.Book, error) {
 	m.ctrl.T.Helper()
@@ -50,33 +81,154 @@ func (mr *MockBookRepoInterfaceMockRecorder) CreateBook(ctx, book interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookRepoInterface)(nil).CreateBook), ctx, book)
 }
 
+// CreateBooks mocks base method.
+func (m *MockBookRepoInterface) CreateBooks(ctx context.Context, books []*model.Book) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBooks", ctx, books)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBooks indicates an expected call of CreateBooks.
+func (mr *MockBookRepoInterfaceMockRecorder) CreateBooks(ctx, books interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBooks", reflect.TypeOf((*MockBookRepoInterface)(nil).CreateBooks), ctx, books)
+}
+
 // DeleteBook mocks base method.
-func (m *MockBookRepoInterface) DeleteBook(ctx context.Context, id uint) error {
+func (m *MockBookRepoInterface) DeleteBook(ctx context.Context, id, version uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id, version)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBook indicates an expected call of DeleteBook.
-func (mr *MockBookRepoInterfaceMockRecorder) DeleteBook(ctx, id interface{}) *gomock.Call {
+func (mr *MockBookRepoInterfaceMockRecorder) DeleteBook(ctx, id, version interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookRepoInterface)(nil).DeleteBook), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookRepoInterface)(nil).DeleteBook), ctx, id, version)
+}
+
+// EachBook mocks base method.
+func (m *MockBookRepoInterface) EachBook(ctx context.Context, fn func(*model.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachBook", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachBook indicates an expected call of EachBook.
+func (mr *MockBookRepoInterfaceMockRecorder) EachBook(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachBook", reflect.TypeOf((*MockBookRepoInterface)(nil).EachBook), ctx, fn)
+}
+
+// EnsureAuthor mocks base method.
+func (m *MockBookRepoInterface) EnsureAuthor(ctx context.Context, name string) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnsureAuthor", ctx, name)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnsureAuthor indicates an expected call of EnsureAuthor.
+func (mr *MockBookRepoInterfaceMockRecorder) EnsureAuthor(ctx, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureAuthor", reflect.TypeOf((*MockBookRepoInterface)(nil).EnsureAuthor), ctx, name)
+}
+
+// ListAudits mocks base method.
+func (m *MockBookRepoInterface) ListAudits(ctx context.Context, bookID uint, query *model.PageQuery) ([]*model.BookAudit, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAudits", ctx, bookID, query)
+	ret0, _ := ret[0].([]*model.BookAudit)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAudits indicates an expected call of ListAudits.
+func (mr *MockBookRepoInterfaceMockRecorder) ListAudits(ctx, bookID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudits", reflect.TypeOf((*MockBookRepoInterface)(nil).ListAudits), ctx, bookID, query)
 }
 
 // ListBooks mocks base method.
-func (m *MockBookRepoInterface) ListBooks(ctx context.Context) (model.Books, error) {
+func (m *MockBookRepoInterface) ListBooks(ctx context.Context, query *model.BookQuery) (model.Books, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListBooks", ctx)
+	ret := m.ctrl.Call(m, "ListBooks", ctx, query)
 	ret0, _ := ret[0].(model.Books)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListBooks indicates an expected call of ListBooks.
+func (mr *MockBookRepoInterfaceMockRecorder) ListBooks(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockBookRepoInterface)(nil).ListBooks), ctx, query)
+}
+
+// ListDeletedBooks mocks base method.
+func (m *MockBookRepoInterface) ListDeletedBooks(ctx context.Context, query *model.PageQuery) (model.Books, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedBooks", ctx, query)
+	ret0, _ := ret[0].(model.Books)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeletedBooks indicates an expected call of ListDeletedBooks.
+func (mr *MockBookRepoInterfaceMockRecorder) ListDeletedBooks(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedBooks", reflect.TypeOf((*MockBookRepoInterface)(nil).ListDeletedBooks), ctx, query)
+}
+
+// LockBook mocks base method.
+func (m *MockBookRepoInterface) LockBook(ctx context.Context, id uint, trashed bool) (*model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBook", ctx, id, trashed)
+	ret0, _ := ret[0].(*model.Book)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListBooks indicates an expected call of ListBooks.
-func (mr *MockBookRepoInterfaceMockRecorder) ListBooks(ctx interface{}) *gomock.Call {
+// LockBook indicates an expected call of LockBook.
+func (mr *MockBookRepoInterfaceMockRecorder) LockBook(ctx, id, trashed interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBook", reflect.TypeOf((*MockBookRepoInterface)(nil).LockBook), ctx, id, trashed)
+}
+
+// LockDeletedBooks mocks base method.
+func (m *MockBookRepoInterface) LockDeletedBooks(ctx context.Context, before time.Time, limit int) (model.Books, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDeletedBooks", ctx, before, limit)
+	ret0, _ := ret[0].(model.Books)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockDeletedBooks indicates an expected call of LockDeletedBooks.
+func (mr *MockBookRepoInterfaceMockRecorder) LockDeletedBooks(ctx, before, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockBookRepoInterface)(nil).ListBooks), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDeletedBooks", reflect.TypeOf((*MockBookRepoInterface)(nil).LockDeletedBooks), ctx, before, limit)
+}
+
+// PurgeBooks mocks base method.
+func (m *MockBookRepoInterface) PurgeBooks(ctx context.Context, ids []uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeBooks", ctx, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeBooks indicates an expected call of PurgeBooks.
+func (mr *MockBookRepoInterfaceMockRecorder) PurgeBooks(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBooks", reflect.TypeOf((*MockBookRepoInterface)(nil).PurgeBooks), ctx, ids)
 }
 
 // ReadBook mocks base method.
@@ -94,6 +246,108 @@ func (mr *MockBookRepoInterfaceMockRecorder) ReadBook(ctx, id interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBook", reflect.TypeOf((*MockBookRepoInterface)(nil).ReadBook), ctx, id)
 }
 
+// ReadBookByISBN mocks base method.
+func (m *MockBookRepoInterface) ReadBookByISBN(ctx context.Context, isbn string) (*model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadBookByISBN", ctx, isbn)
+	ret0, _ := ret[0].(*model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadBookByISBN indicates an expected call of ReadBookByISBN.
+func (mr *MockBookRepoInterfaceMockRecorder) ReadBookByISBN(ctx, isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadBookByISBN", reflect.TypeOf((*MockBookRepoInterface)(nil).ReadBookByISBN), ctx, isbn)
+}
+
+// RestoreBook mocks base method.
+func (m *MockBookRepoInterface) RestoreBook(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockBookRepoInterfaceMockRecorder) RestoreBook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockBookRepoInterface)(nil).RestoreBook), ctx, id)
+}
+
+// SearchBooks mocks base method.
+func (m *MockBookRepoInterface) SearchBooks(ctx context.Context, query *model.BookSearchQuery) (model.BookSearchHits, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, query)
+	ret0, _ := ret[0].(model.BookSearchHits)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockBookRepoInterfaceMockRecorder) SearchBooks(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookRepoInterface)(nil).SearchBooks), ctx, query)
+}
+
+// SetBookAuthors mocks base method.
+func (m *MockBookRepoInterface) SetBookAuthors(ctx context.Context, bookID uint, authorIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookAuthors", ctx, bookID, authorIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBookAuthors indicates an expected call of SetBookAuthors.
+func (mr *MockBookRepoInterfaceMockRecorder) SetBookAuthors(ctx, bookID, authorIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookAuthors", reflect.TypeOf((*MockBookRepoInterface)(nil).SetBookAuthors), ctx, bookID, authorIDs)
+}
+
+// SetBookCover mocks base method.
+func (m *MockBookRepoInterface) SetBookCover(ctx context.Context, id uint, coverType, coverKey string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookCover", ctx, id, coverType, coverKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBookCover indicates an expected call of SetBookCover.
+func (mr *MockBookRepoInterfaceMockRecorder) SetBookCover(ctx, id, coverType, coverKey interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookCover", reflect.TypeOf((*MockBookRepoInterface)(nil).SetBookCover), ctx, id, coverType, coverKey)
+}
+
+// TakenISBNs mocks base method.
+func (m *MockBookRepoInterface) TakenISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakenISBNs", ctx, isbns)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakenISBNs indicates an expected call of TakenISBNs.
+func (mr *MockBookRepoInterfaceMockRecorder) TakenISBNs(ctx, isbns interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakenISBNs", reflect.TypeOf((*MockBookRepoInterface)(nil).TakenISBNs), ctx, isbns)
+}
+
+// Transaction mocks base method.
+func (m *MockBookRepoInterface) Transaction(ctx context.Context, fn func(repository.BookRepoInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockBookRepoInterfaceMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockBookRepoInterface)(nil).Transaction), ctx, fn)
+}
+
 // UpdateBook mocks base method.
 func (m *MockBookRepoInterface) UpdateBook(ctx context.Context, book *model.Book) error {
 	m.ctrl.T.Helper()
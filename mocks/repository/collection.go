@@ -0,0 +1,169 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/collection.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	model "myapp/model"
+	repository "myapp/repository"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCollectionRepoInterface is a mock of CollectionRepoInterface interface.
+type MockCollectionRepoInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionRepoInterfaceMockRecorder
+}
+
+// MockCollectionRepoInterfaceMockRecorder is the mock recorder for MockCollectionRepoInterface.
+type MockCollectionRepoInterfaceMockRecorder struct {
+	mock *MockCollectionRepoInterface
+}
+
+// NewMockCollectionRepoInterface creates a new mock instance.
+func NewMockCollectionRepoInterface(ctrl *gomock.Controller) *MockCollectionRepoInterface {
+	mock := &MockCollectionRepoInterface{ctrl: ctrl}
+	mock.recorder = &MockCollectionRepoInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionRepoInterface) EXPECT() *MockCollectionRepoInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CollectionBooks mocks base method.
+func (m *MockCollectionRepoInterface) CollectionBooks(ctx context.Context, id uint) (model.Books, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollectionBooks", ctx, id)
+	ret0, _ := ret[0].(model.Books)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollectionBooks indicates an expected call of CollectionBooks.
+func (mr *MockCollectionRepoInterfaceMockRecorder) CollectionBooks(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollectionBooks", reflect.TypeOf((*MockCollectionRepoInterface)(nil).CollectionBooks), ctx, id)
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollectionRepoInterface) CreateCollection(ctx context.Context, collection *model.Collection) (*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", ctx, collection)
+	ret0, _ := ret[0].(*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionRepoInterfaceMockRecorder) CreateCollection(ctx, collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollectionRepoInterface)(nil).CreateCollection), ctx, collection)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollectionRepoInterface) DeleteCollection(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionRepoInterfaceMockRecorder) DeleteCollection(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollectionRepoInterface)(nil).DeleteCollection), ctx, id)
+}
+
+// ListCollections mocks base method.
+func (m *MockCollectionRepoInterface) ListCollections(ctx context.Context, query *model.PageQuery) (model.Collections, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollections", ctx, query)
+	ret0, _ := ret[0].(model.Collections)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCollections indicates an expected call of ListCollections.
+func (mr *MockCollectionRepoInterfaceMockRecorder) ListCollections(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollections", reflect.TypeOf((*MockCollectionRepoInterface)(nil).ListCollections), ctx, query)
+}
+
+// LockCollection mocks base method.
+func (m *MockCollectionRepoInterface) LockCollection(ctx context.Context, id uint) (*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockCollection", ctx, id)
+	ret0, _ := ret[0].(*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockCollection indicates an expected call of LockCollection.
+func (mr *MockCollectionRepoInterfaceMockRecorder) LockCollection(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockCollection", reflect.TypeOf((*MockCollectionRepoInterface)(nil).LockCollection), ctx, id)
+}
+
+// ReadCollection mocks base method.
+func (m *MockCollectionRepoInterface) ReadCollection(ctx context.Context, id uint) (*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadCollection", ctx, id)
+	ret0, _ := ret[0].(*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadCollection indicates an expected call of ReadCollection.
+func (mr *MockCollectionRepoInterfaceMockRecorder) ReadCollection(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadCollection", reflect.TypeOf((*MockCollectionRepoInterface)(nil).ReadCollection), ctx, id)
+}
+
+// SetCollectionBooks mocks base method.
+func (m *MockCollectionRepoInterface) SetCollectionBooks(ctx context.Context, id uint, bookIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCollectionBooks", ctx, id, bookIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCollectionBooks indicates an expected call of SetCollectionBooks.
+func (mr *MockCollectionRepoInterfaceMockRecorder) SetCollectionBooks(ctx, id, bookIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCollectionBooks", reflect.TypeOf((*MockCollectionRepoInterface)(nil).SetCollectionBooks), ctx, id, bookIDs)
+}
+
+// Transaction mocks base method.
+func (m *MockCollectionRepoInterface) Transaction(ctx context.Context, fn func(repository.CollectionRepoInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockCollectionRepoInterfaceMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockCollectionRepoInterface)(nil).Transaction), ctx, fn)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollectionRepoInterface) UpdateCollection(ctx context.Context, collection *model.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", ctx, collection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionRepoInterfaceMockRecorder) UpdateCollection(ctx, collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollectionRepoInterface)(nil).UpdateCollection), ctx, collection)
+}
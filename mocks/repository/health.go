@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/health.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHealthRepoInterface is a mock of HealthRepoInterface interface.
+type MockHealthRepoInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthRepoInterfaceMockRecorder
+}
+
+// MockHealthRepoInterfaceMockRecorder is the mock recorder for MockHealthRepoInterface.
+type MockHealthRepoInterfaceMockRecorder struct {
+	mock *MockHealthRepoInterface
+}
+
+// NewMockHealthRepoInterface creates a new mock instance.
+func NewMockHealthRepoInterface(ctrl *gomock.Controller) *MockHealthRepoInterface {
+	mock := &MockHealthRepoInterface{ctrl: ctrl}
+	mock.recorder = &MockHealthRepoInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthRepoInterface) EXPECT() *MockHealthRepoInterfaceMockRecorder {
+	return m.recorder
+}
+
+// LatestMigration mocks base method.
+func (m *MockHealthRepoInterface) LatestMigration() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LatestMigration")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LatestMigration indicates an expected call of LatestMigration.
+func (mr *MockHealthRepoInterfaceMockRecorder) LatestMigration() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestMigration", reflect.TypeOf((*MockHealthRepoInterface)(nil).LatestMigration))
+}
+
+// MigrationVersion mocks base method.
+func (m *MockHealthRepoInterface) MigrationVersion(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MigrationVersion", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MigrationVersion indicates an expected call of MigrationVersion.
+func (mr *MockHealthRepoInterfaceMockRecorder) MigrationVersion(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MigrationVersion", reflect.TypeOf((*MockHealthRepoInterface)(nil).MigrationVersion), ctx)
+}
+
+// Ping mocks base method.
+func (m *MockHealthRepoInterface) Ping(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockHealthRepoInterfaceMockRecorder) Ping(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockHealthRepoInterface)(nil).Ping), ctx)
+}
@@ -0,0 +1,154 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository/label.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	model "myapp/model"
+	repository "myapp/repository"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLabelRepoInterface is a mock of LabelRepoInterface interface.
+type MockLabelRepoInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelRepoInterfaceMockRecorder
+}
+
+// MockLabelRepoInterfaceMockRecorder is the mock recorder for MockLabelRepoInterface.
+type MockLabelRepoInterfaceMockRecorder struct {
+	mock *MockLabelRepoInterface
+}
+
+// NewMockLabelRepoInterface creates a new mock instance.
+func NewMockLabelRepoInterface(ctrl *gomock.Controller) *MockLabelRepoInterface {
+	mock := &MockLabelRepoInterface{ctrl: ctrl}
+	mock.recorder = &MockLabelRepoInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabelRepoInterface) EXPECT() *MockLabelRepoInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AttachLabel mocks base method.
+func (m *MockLabelRepoInterface) AttachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string, create bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLabel", ctx, kind, bookID, name, create)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachLabel indicates an expected call of AttachLabel.
+func (mr *MockLabelRepoInterfaceMockRecorder) AttachLabel(ctx, kind, bookID, name, create interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLabel", reflect.TypeOf((*MockLabelRepoInterface)(nil).AttachLabel), ctx, kind, bookID, name, create)
+}
+
+// BookLabels mocks base method.
+func (m *MockLabelRepoInterface) BookLabels(ctx context.Context, kind model.LabelKind, bookID uint) (model.Labels, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookLabels", ctx, kind, bookID)
+	ret0, _ := ret[0].(model.Labels)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookLabels indicates an expected call of BookLabels.
+func (mr *MockLabelRepoInterfaceMockRecorder) BookLabels(ctx, kind, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookLabels", reflect.TypeOf((*MockLabelRepoInterface)(nil).BookLabels), ctx, kind, bookID)
+}
+
+// CreateLabel mocks base method.
+func (m *MockLabelRepoInterface) CreateLabel(ctx context.Context, kind model.LabelKind, label *model.Label) (*model.Label, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", ctx, kind, label)
+	ret0, _ := ret[0].(*model.Label)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockLabelRepoInterfaceMockRecorder) CreateLabel(ctx, kind, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockLabelRepoInterface)(nil).CreateLabel), ctx, kind, label)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockLabelRepoInterface) DeleteLabel(ctx context.Context, kind model.LabelKind, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", ctx, kind, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockLabelRepoInterfaceMockRecorder) DeleteLabel(ctx, kind, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockLabelRepoInterface)(nil).DeleteLabel), ctx, kind, name)
+}
+
+// DetachLabel mocks base method.
+func (m *MockLabelRepoInterface) DetachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachLabel", ctx, kind, bookID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachLabel indicates an expected call of DetachLabel.
+func (mr *MockLabelRepoInterfaceMockRecorder) DetachLabel(ctx, kind, bookID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLabel", reflect.TypeOf((*MockLabelRepoInterface)(nil).DetachLabel), ctx, kind, bookID, name)
+}
+
+// ListLabels mocks base method.
+func (m *MockLabelRepoInterface) ListLabels(ctx context.Context, kind model.LabelKind, query *model.PageQuery) (model.Labels, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLabels", ctx, kind, query)
+	ret0, _ := ret[0].(model.Labels)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListLabels indicates an expected call of ListLabels.
+func (mr *MockLabelRepoInterfaceMockRecorder) ListLabels(ctx, kind, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLabels", reflect.TypeOf((*MockLabelRepoInterface)(nil).ListLabels), ctx, kind, query)
+}
+
+// LockBook mocks base method.
+func (m *MockLabelRepoInterface) LockBook(ctx context.Context, bookID uint) (*model.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockBook", ctx, bookID)
+	ret0, _ := ret[0].(*model.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockBook indicates an expected call of LockBook.
+func (mr *MockLabelRepoInterfaceMockRecorder) LockBook(ctx, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockBook", reflect.TypeOf((*MockLabelRepoInterface)(nil).LockBook), ctx, bookID)
+}
+
+// Transaction mocks base method.
+func (m *MockLabelRepoInterface) Transaction(ctx context.Context, fn func(repository.LabelRepoInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Transaction", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Transaction indicates an expected call of Transaction.
+func (mr *MockLabelRepoInterfaceMockRecorder) Transaction(ctx, fn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transaction", reflect.TypeOf((*MockLabelRepoInterface)(nil).Transaction), ctx, fn)
+}
@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/api_key_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "myapp/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockApiKeyServiceInterface is a mock of ApiKeyServiceInterface interface.
+type MockApiKeyServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockApiKeyServiceInterfaceMockRecorder
+}
+
+// MockApiKeyServiceInterfaceMockRecorder is the mock recorder for MockApiKeyServiceInterface.
+type MockApiKeyServiceInterfaceMockRecorder struct {
+	mock *MockApiKeyServiceInterface
+}
+
+// NewMockApiKeyServiceInterface creates a new mock instance.
+func NewMockApiKeyServiceInterface(ctrl *gomock.Controller) *MockApiKeyServiceInterface {
+	mock := &MockApiKeyServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockApiKeyServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApiKeyServiceInterface) EXPECT() *MockApiKeyServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockApiKeyServiceInterface) Authenticate(ctx context.Context, key string) (*model.ApiKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*model.ApiKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockApiKeyServiceInterfaceMockRecorder) Authenticate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockApiKeyServiceInterface)(nil).Authenticate), ctx, key)
+}
+
+// CreateApiKey mocks base method.
+func (m *MockApiKeyServiceInterface) CreateApiKey(ctx context.Context, key *model.ApiKeyForm) (*model.ApiKeyCreatedDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApiKey", ctx, key)
+	ret0, _ := ret[0].(*model.ApiKeyCreatedDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApiKey indicates an expected call of CreateApiKey.
+func (mr *MockApiKeyServiceInterfaceMockRecorder) CreateApiKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApiKey", reflect.TypeOf((*MockApiKeyServiceInterface)(nil).CreateApiKey), ctx, key)
+}
+
+// GetListApiKey mocks base method.
+func (m *MockApiKeyServiceInterface) GetListApiKey(ctx context.Context, query *model.PageQuery) (*model.ApiKeyListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListApiKey", ctx, query)
+	ret0, _ := ret[0].(*model.ApiKeyListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListApiKey indicates an expected call of GetListApiKey.
+func (mr *MockApiKeyServiceInterfaceMockRecorder) GetListApiKey(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListApiKey", reflect.TypeOf((*MockApiKeyServiceInterface)(nil).GetListApiKey), ctx, query)
+}
+
+// RevokeApiKey mocks base method.
+func (m *MockApiKeyServiceInterface) RevokeApiKey(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeApiKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeApiKey indicates an expected call of RevokeApiKey.
+func (mr *MockApiKeyServiceInterfaceMockRecorder) RevokeApiKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeApiKey", reflect.TypeOf((*MockApiKeyServiceInterface)(nil).RevokeApiKey), ctx, id)
+}
+
+// UpdateApiKeyScopes mocks base method.
+func (m *MockApiKeyServiceInterface) UpdateApiKeyScopes(ctx context.Context, id uint, scopes *model.ApiKeyScopesForm) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateApiKeyScopes", ctx, id, scopes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateApiKeyScopes indicates an expected call of UpdateApiKeyScopes.
+func (mr *MockApiKeyServiceInterfaceMockRecorder) UpdateApiKeyScopes(ctx, id, scopes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateApiKeyScopes", reflect.TypeOf((*MockApiKeyServiceInterface)(nil).UpdateApiKeyScopes), ctx, id, scopes)
+}
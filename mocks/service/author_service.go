@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/author_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "myapp/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAuthorServiceInterface is a mock of AuthorServiceInterface interface.
+type MockAuthorServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthorServiceInterfaceMockRecorder
+}
+
+// MockAuthorServiceInterfaceMockRecorder is the mock recorder for MockAuthorServiceInterface.
+type MockAuthorServiceInterfaceMockRecorder struct {
+	mock *MockAuthorServiceInterface
+}
+
+// NewMockAuthorServiceInterface creates a new mock instance.
+func NewMockAuthorServiceInterface(ctrl *gomock.Controller) *MockAuthorServiceInterface {
+	mock := &MockAuthorServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAuthorServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuthorServiceInterface) EXPECT() *MockAuthorServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateAuthor mocks base method.
+func (m *MockAuthorServiceInterface) CreateAuthor(ctx context.Context, author *model.AuthorForm) (*model.AuthorDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthor", ctx, author)
+	ret0, _ := ret[0].(*model.AuthorDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthor indicates an expected call of CreateAuthor.
+func (mr *MockAuthorServiceInterfaceMockRecorder) CreateAuthor(ctx, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthor", reflect.TypeOf((*MockAuthorServiceInterface)(nil).CreateAuthor), ctx, author)
+}
+
+// DeleteAuthor mocks base method.
+func (m *MockAuthorServiceInterface) DeleteAuthor(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAuthor", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAuthor indicates an expected call of DeleteAuthor.
+func (mr *MockAuthorServiceInterfaceMockRecorder) DeleteAuthor(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthor", reflect.TypeOf((*MockAuthorServiceInterface)(nil).DeleteAuthor), ctx, id)
+}
+
+// GetAuthorBooks mocks base method.
+func (m *MockAuthorServiceInterface) GetAuthorBooks(ctx context.Context, id uint, query *model.PageQuery) (*model.AuthorBookListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorBooks", ctx, id, query)
+	ret0, _ := ret[0].(*model.AuthorBookListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorBooks indicates an expected call of GetAuthorBooks.
+func (mr *MockAuthorServiceInterfaceMockRecorder) GetAuthorBooks(ctx, id, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorBooks", reflect.TypeOf((*MockAuthorServiceInterface)(nil).GetAuthorBooks), ctx, id, query)
+}
+
+// GetAuthorByID mocks base method.
+func (m *MockAuthorServiceInterface) GetAuthorByID(ctx context.Context, id uint) (*model.AuthorDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuthorByID", ctx, id)
+	ret0, _ := ret[0].(*model.AuthorDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthorByID indicates an expected call of GetAuthorByID.
+func (mr *MockAuthorServiceInterfaceMockRecorder) GetAuthorByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthorByID", reflect.TypeOf((*MockAuthorServiceInterface)(nil).GetAuthorByID), ctx, id)
+}
+
+// GetListAuthor mocks base method.
+func (m *MockAuthorServiceInterface) GetListAuthor(ctx context.Context, query *model.PageQuery) (*model.AuthorListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListAuthor", ctx, query)
+	ret0, _ := ret[0].(*model.AuthorListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListAuthor indicates an expected call of GetListAuthor.
+func (mr *MockAuthorServiceInterfaceMockRecorder) GetListAuthor(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListAuthor", reflect.TypeOf((*MockAuthorServiceInterface)(nil).GetListAuthor), ctx, query)
+}
+
+// UpdateAuthor mocks base method.
+func (m *MockAuthorServiceInterface) UpdateAuthor(ctx context.Context, id uint, author *model.AuthorForm) (*model.AuthorDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAuthor", ctx, id, author)
+	ret0, _ := ret[0].(*model.AuthorDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAuthor indicates an expected call of UpdateAuthor.
+func (mr *MockAuthorServiceInterfaceMockRecorder) UpdateAuthor(ctx, id, author interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthor", reflect.TypeOf((*MockAuthorServiceInterface)(nil).UpdateAuthor), ctx, id, author)
+}
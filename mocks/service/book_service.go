@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "myapp/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,21 @@ func (m *MockBookServiceInterface) EXPECT() *MockBookServiceInterfaceMockRecorde
 	return m.recorder
 }
 
+// BatchBooks mocks base method.
+func (m *MockBookServiceInterface) BatchBooks(ctx context.Context, batch *model.BookBatchForm) (*model.BookBatchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchBooks", ctx, batch)
+	ret0, _ := ret[0].(*model.BookBatchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchBooks indicates an expected call of BatchBooks.
+func (mr *MockBookServiceInterfaceMockRecorder) BatchBooks(ctx, batch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchBooks", reflect.TypeOf((*MockBookServiceInterface)(nil).BatchBooks), ctx, batch)
+}
+
 // CreateBook mocks base method.
 func (m *MockBookServiceInterface) CreateBook(ctx context.Context, book *model.BookForm) (*model.BookDto, error) {
 	m.ctrl.T.Helper()
@@ -51,17 +67,31 @@ func (mr *MockBookServiceInterfaceMockRecorder) CreateBook(ctx, book interface{}
 }
 
 // DeleteBook mocks base method.
-func (m *MockBookServiceInterface) DeleteBook(ctx context.Context, id uint) error {
+func (m *MockBookServiceInterface) DeleteBook(ctx context.Context, id uint, versions []uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id, versions)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteBook indicates an expected call of DeleteBook.
-func (mr *MockBookServiceInterfaceMockRecorder) DeleteBook(ctx, id interface{}) *gomock.Call {
+func (mr *MockBookServiceInterfaceMockRecorder) DeleteBook(ctx, id, versions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookServiceInterface)(nil).DeleteBook), ctx, id, versions)
+}
+
+// ExportBooks mocks base method.
+func (m *MockBookServiceInterface) ExportBooks(ctx context.Context, fn func(*model.BookDto) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBooks", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportBooks indicates an expected call of ExportBooks.
+func (mr *MockBookServiceInterfaceMockRecorder) ExportBooks(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookServiceInterface)(nil).DeleteBook), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBooks", reflect.TypeOf((*MockBookServiceInterface)(nil).ExportBooks), ctx, fn)
 }
 
 // GetBookByID mocks base method.
@@ -79,31 +109,166 @@ func (mr *MockBookServiceInterfaceMockRecorder) GetBookByID(ctx, id interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByID", reflect.TypeOf((*MockBookServiceInterface)(nil).GetBookByID), ctx, id)
 }
 
+// GetBookByISBN mocks base method.
+func (m *MockBookServiceInterface) GetBookByISBN(ctx context.Context, isbn string) (*model.BookDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookByISBN", ctx, isbn)
+	ret0, _ := ret[0].(*model.BookDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookByISBN indicates an expected call of GetBookByISBN.
+func (mr *MockBookServiceInterfaceMockRecorder) GetBookByISBN(ctx, isbn interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookByISBN", reflect.TypeOf((*MockBookServiceInterface)(nil).GetBookByISBN), ctx, isbn)
+}
+
+// GetBookHistory mocks base method.
+func (m *MockBookServiceInterface) GetBookHistory(ctx context.Context, id uint, query *model.PageQuery) (*model.BookHistoryDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookHistory", ctx, id, query)
+	ret0, _ := ret[0].(*model.BookHistoryDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookHistory indicates an expected call of GetBookHistory.
+func (mr *MockBookServiceInterfaceMockRecorder) GetBookHistory(ctx, id, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookHistory", reflect.TypeOf((*MockBookServiceInterface)(nil).GetBookHistory), ctx, id, query)
+}
+
 // GetListBook mocks base method.
-func (m *MockBookServiceInterface) GetListBook(ctx context.Context) ([]model.BookDto, error) {
+func (m *MockBookServiceInterface) GetListBook(ctx context.Context, query *model.BookQuery) (*model.BookListDto, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetListBook", ctx)
-	ret0, _ := ret[0].([]model.BookDto)
+	ret := m.ctrl.Call(m, "GetListBook", ctx, query)
+	ret0, _ := ret[0].(*model.BookListDto)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetListBook indicates an expected call of GetListBook.
-func (mr *MockBookServiceInterfaceMockRecorder) GetListBook(ctx interface{}) *gomock.Call {
+func (mr *MockBookServiceInterfaceMockRecorder) GetListBook(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListBook", reflect.TypeOf((*MockBookServiceInterface)(nil).GetListBook), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListBook", reflect.TypeOf((*MockBookServiceInterface)(nil).GetListBook), ctx, query)
 }
 
-// UpdateBook mocks base method.
-func (m *MockBookServiceInterface) UpdateBook(ctx context.Context, id uint, book *model.BookForm) error {
+// ImportBooks mocks base method.
+func (m *MockBookServiceInterface) ImportBooks(ctx context.Context, rows []*model.BookImportRow, dryRun bool) (*model.BookImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", ctx, rows, dryRun)
+	ret0, _ := ret[0].(*model.BookImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockBookServiceInterfaceMockRecorder) ImportBooks(ctx, rows, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockBookServiceInterface)(nil).ImportBooks), ctx, rows, dryRun)
+}
+
+// ListDeletedBooks mocks base method.
+func (m *MockBookServiceInterface) ListDeletedBooks(ctx context.Context, query *model.PageQuery) (*model.BookTrashListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedBooks", ctx, query)
+	ret0, _ := ret[0].(*model.BookTrashListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedBooks indicates an expected call of ListDeletedBooks.
+func (mr *MockBookServiceInterfaceMockRecorder) ListDeletedBooks(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedBooks", reflect.TypeOf((*MockBookServiceInterface)(nil).ListDeletedBooks), ctx, query)
+}
+
+// PatchBook mocks base method.
+func (m *MockBookServiceInterface) PatchBook(ctx context.Context, id uint, versions []uint, format model.PatchFormat, patch []byte) (*model.BookDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchBook", ctx, id, versions, format, patch)
+	ret0, _ := ret[0].(*model.BookDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchBook indicates an expected call of PatchBook.
+func (mr *MockBookServiceInterfaceMockRecorder) PatchBook(ctx, id, versions, format, patch interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchBook", reflect.TypeOf((*MockBookServiceInterface)(nil).PatchBook), ctx, id, versions, format, patch)
+}
+
+// PurgeBook mocks base method.
+func (m *MockBookServiceInterface) PurgeBook(ctx context.Context, id uint) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateBook", ctx, id, book)
+	ret := m.ctrl.Call(m, "PurgeBook", ctx, id)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// PurgeBook indicates an expected call of PurgeBook.
+func (mr *MockBookServiceInterfaceMockRecorder) PurgeBook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeBook", reflect.TypeOf((*MockBookServiceInterface)(nil).PurgeBook), ctx, id)
+}
+
+// PurgeDeletedBooks mocks base method.
+func (m *MockBookServiceInterface) PurgeDeletedBooks(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedBooks", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedBooks indicates an expected call of PurgeDeletedBooks.
+func (mr *MockBookServiceInterfaceMockRecorder) PurgeDeletedBooks(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedBooks", reflect.TypeOf((*MockBookServiceInterface)(nil).PurgeDeletedBooks), ctx, before)
+}
+
+// RestoreBook mocks base method.
+func (m *MockBookServiceInterface) RestoreBook(ctx context.Context, id uint) (*model.BookDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreBook", ctx, id)
+	ret0, _ := ret[0].(*model.BookDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreBook indicates an expected call of RestoreBook.
+func (mr *MockBookServiceInterfaceMockRecorder) RestoreBook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreBook", reflect.TypeOf((*MockBookServiceInterface)(nil).RestoreBook), ctx, id)
+}
+
+// SearchBooks mocks base method.
+func (m *MockBookServiceInterface) SearchBooks(ctx context.Context, query *model.BookSearchQuery) (*model.BookSearchListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, query)
+	ret0, _ := ret[0].(*model.BookSearchListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockBookServiceInterfaceMockRecorder) SearchBooks(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookServiceInterface)(nil).SearchBooks), ctx, query)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookServiceInterface) UpdateBook(ctx context.Context, id uint, book *model.BookForm, versions []uint) (*model.BookDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, id, book, versions)
+	ret0, _ := ret[0].(*model.BookDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // UpdateBook indicates an expected call of UpdateBook.
-func (mr *MockBookServiceInterfaceMockRecorder) UpdateBook(ctx, id, book interface{}) *gomock.Call {
+func (mr *MockBookServiceInterfaceMockRecorder) UpdateBook(ctx, id, book, versions interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookServiceInterface)(nil).UpdateBook), ctx, id, book)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookServiceInterface)(nil).UpdateBook), ctx, id, book, versions)
 }
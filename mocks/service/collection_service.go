@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/collection_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "myapp/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCollectionServiceInterface is a mock of CollectionServiceInterface interface.
+type MockCollectionServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionServiceInterfaceMockRecorder
+}
+
+// MockCollectionServiceInterfaceMockRecorder is the mock recorder for MockCollectionServiceInterface.
+type MockCollectionServiceInterfaceMockRecorder struct {
+	mock *MockCollectionServiceInterface
+}
+
+// NewMockCollectionServiceInterface creates a new mock instance.
+func NewMockCollectionServiceInterface(ctrl *gomock.Controller) *MockCollectionServiceInterface {
+	mock := &MockCollectionServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCollectionServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionServiceInterface) EXPECT() *MockCollectionServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollectionServiceInterface) CreateCollection(ctx context.Context, collection *model.CollectionForm) (*model.CollectionDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", ctx, collection)
+	ret0, _ := ret[0].(*model.CollectionDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) CreateCollection(ctx, collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).CreateCollection), ctx, collection)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollectionServiceInterface) DeleteCollection(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) DeleteCollection(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).DeleteCollection), ctx, id)
+}
+
+// GetCollectionByID mocks base method.
+func (m *MockCollectionServiceInterface) GetCollectionByID(ctx context.Context, id uint) (*model.CollectionDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionByID", ctx, id)
+	ret0, _ := ret[0].(*model.CollectionDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionByID indicates an expected call of GetCollectionByID.
+func (mr *MockCollectionServiceInterfaceMockRecorder) GetCollectionByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionByID", reflect.TypeOf((*MockCollectionServiceInterface)(nil).GetCollectionByID), ctx, id)
+}
+
+// GetListCollection mocks base method.
+func (m *MockCollectionServiceInterface) GetListCollection(ctx context.Context, query *model.PageQuery) (*model.CollectionListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListCollection", ctx, query)
+	ret0, _ := ret[0].(*model.CollectionListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListCollection indicates an expected call of GetListCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) GetListCollection(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).GetListCollection), ctx, query)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollectionServiceInterface) UpdateCollection(ctx context.Context, id uint, collection *model.CollectionForm) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", ctx, id, collection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionServiceInterfaceMockRecorder) UpdateCollection(ctx, id, collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollectionServiceInterface)(nil).UpdateCollection), ctx, id, collection)
+}
@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/cover_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	io "io"
+	model "myapp/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCoverServiceInterface is a mock of CoverServiceInterface interface.
+type MockCoverServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockCoverServiceInterfaceMockRecorder
+}
+
+// MockCoverServiceInterfaceMockRecorder is the mock recorder for MockCoverServiceInterface.
+type MockCoverServiceInterfaceMockRecorder struct {
+	mock *MockCoverServiceInterface
+}
+
+// NewMockCoverServiceInterface creates a new mock instance.
+func NewMockCoverServiceInterface(ctrl *gomock.Controller) *MockCoverServiceInterface {
+	mock := &MockCoverServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockCoverServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCoverServiceInterface) EXPECT() *MockCoverServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// DeleteCover mocks base method.
+func (m *MockCoverServiceInterface) DeleteCover(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCover", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCover indicates an expected call of DeleteCover.
+func (mr *MockCoverServiceInterfaceMockRecorder) DeleteCover(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCover", reflect.TypeOf((*MockCoverServiceInterface)(nil).DeleteCover), ctx, id)
+}
+
+// GetCover mocks base method.
+func (m *MockCoverServiceInterface) GetCover(ctx context.Context, id uint, size string) (*model.Cover, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCover", ctx, id, size)
+	ret0, _ := ret[0].(*model.Cover)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCover indicates an expected call of GetCover.
+func (mr *MockCoverServiceInterfaceMockRecorder) GetCover(ctx, id, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCover", reflect.TypeOf((*MockCoverServiceInterface)(nil).GetCover), ctx, id, size)
+}
+
+// SetCover mocks base method.
+func (m *MockCoverServiceInterface) SetCover(ctx context.Context, id uint, r io.Reader) (*model.BookDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCover", ctx, id, r)
+	ret0, _ := ret[0].(*model.BookDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCover indicates an expected call of SetCover.
+func (mr *MockCoverServiceInterfaceMockRecorder) SetCover(ctx, id, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCover", reflect.TypeOf((*MockCoverServiceInterface)(nil).SetCover), ctx, id, r)
+}
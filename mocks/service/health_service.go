@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/health_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "myapp/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockHealthServiceInterface is a mock of HealthServiceInterface interface.
+type MockHealthServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthServiceInterfaceMockRecorder
+}
+
+// MockHealthServiceInterfaceMockRecorder is the mock recorder for MockHealthServiceInterface.
+type MockHealthServiceInterfaceMockRecorder struct {
+	mock *MockHealthServiceInterface
+}
+
+// NewMockHealthServiceInterface creates a new mock instance.
+func NewMockHealthServiceInterface(ctrl *gomock.Controller) *MockHealthServiceInterface {
+	mock := &MockHealthServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockHealthServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthServiceInterface) EXPECT() *MockHealthServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Liveness mocks base method.
+func (m *MockHealthServiceInterface) Liveness(ctx context.Context) *model.HealthDto {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Liveness", ctx)
+	ret0, _ := ret[0].(*model.HealthDto)
+	return ret0
+}
+
+// Liveness indicates an expected call of Liveness.
+func (mr *MockHealthServiceInterfaceMockRecorder) Liveness(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Liveness", reflect.TypeOf((*MockHealthServiceInterface)(nil).Liveness), ctx)
+}
+
+// Readiness mocks base method.
+func (m *MockHealthServiceInterface) Readiness(ctx context.Context) *model.HealthDto {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Readiness", ctx)
+	ret0, _ := ret[0].(*model.HealthDto)
+	return ret0
+}
+
+// Readiness indicates an expected call of Readiness.
+func (mr *MockHealthServiceInterfaceMockRecorder) Readiness(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Readiness", reflect.TypeOf((*MockHealthServiceInterface)(nil).Readiness), ctx)
+}
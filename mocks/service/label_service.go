@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: service/label_service.go
+
+// Package mock_service is a generated GoMock package.
+package mock_service
+
+import (
+	context "context"
+	model "myapp/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLabelServiceInterface is a mock of LabelServiceInterface interface.
+type MockLabelServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockLabelServiceInterfaceMockRecorder
+}
+
+// MockLabelServiceInterfaceMockRecorder is the mock recorder for MockLabelServiceInterface.
+type MockLabelServiceInterfaceMockRecorder struct {
+	mock *MockLabelServiceInterface
+}
+
+// NewMockLabelServiceInterface creates a new mock instance.
+func NewMockLabelServiceInterface(ctrl *gomock.Controller) *MockLabelServiceInterface {
+	mock := &MockLabelServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockLabelServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLabelServiceInterface) EXPECT() *MockLabelServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// AttachLabel mocks base method.
+func (m *MockLabelServiceInterface) AttachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachLabel", ctx, kind, bookID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AttachLabel indicates an expected call of AttachLabel.
+func (mr *MockLabelServiceInterfaceMockRecorder) AttachLabel(ctx, kind, bookID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachLabel", reflect.TypeOf((*MockLabelServiceInterface)(nil).AttachLabel), ctx, kind, bookID, name)
+}
+
+// CreateLabel mocks base method.
+func (m *MockLabelServiceInterface) CreateLabel(ctx context.Context, kind model.LabelKind, label *model.LabelForm) (*model.LabelDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLabel", ctx, kind, label)
+	ret0, _ := ret[0].(*model.LabelDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLabel indicates an expected call of CreateLabel.
+func (mr *MockLabelServiceInterfaceMockRecorder) CreateLabel(ctx, kind, label interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLabel", reflect.TypeOf((*MockLabelServiceInterface)(nil).CreateLabel), ctx, kind, label)
+}
+
+// DeleteLabel mocks base method.
+func (m *MockLabelServiceInterface) DeleteLabel(ctx context.Context, kind model.LabelKind, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLabel", ctx, kind, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLabel indicates an expected call of DeleteLabel.
+func (mr *MockLabelServiceInterfaceMockRecorder) DeleteLabel(ctx, kind, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLabel", reflect.TypeOf((*MockLabelServiceInterface)(nil).DeleteLabel), ctx, kind, name)
+}
+
+// DetachLabel mocks base method.
+func (m *MockLabelServiceInterface) DetachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachLabel", ctx, kind, bookID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DetachLabel indicates an expected call of DetachLabel.
+func (mr *MockLabelServiceInterfaceMockRecorder) DetachLabel(ctx, kind, bookID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachLabel", reflect.TypeOf((*MockLabelServiceInterface)(nil).DetachLabel), ctx, kind, bookID, name)
+}
+
+// GetBookLabels mocks base method.
+func (m *MockLabelServiceInterface) GetBookLabels(ctx context.Context, kind model.LabelKind, bookID uint) (*model.BookLabelListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookLabels", ctx, kind, bookID)
+	ret0, _ := ret[0].(*model.BookLabelListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookLabels indicates an expected call of GetBookLabels.
+func (mr *MockLabelServiceInterfaceMockRecorder) GetBookLabels(ctx, kind, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookLabels", reflect.TypeOf((*MockLabelServiceInterface)(nil).GetBookLabels), ctx, kind, bookID)
+}
+
+// GetListLabel mocks base method.
+func (m *MockLabelServiceInterface) GetListLabel(ctx context.Context, kind model.LabelKind, query *model.PageQuery) (*model.LabelListDto, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListLabel", ctx, kind, query)
+	ret0, _ := ret[0].(*model.LabelListDto)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListLabel indicates an expected call of GetListLabel.
+func (mr *MockLabelServiceInterfaceMockRecorder) GetListLabel(ctx, kind, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListLabel", reflect.TypeOf((*MockLabelServiceInterface)(nil).GetListLabel), ctx, kind, query)
+}
@@ -5,6 +5,8 @@
 package mock_logger
 
 import (
+	context "context"
+	logger "myapp/util/logger"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -34,6 +36,20 @@ func (m *MockLoggerInterface) EXPECT() *MockLoggerInterfaceMockRecorder {
 	return m.recorder
 }
 
+// Debug mocks base method.
+func (m *MockLoggerInterface) Debug() *zerolog.Event {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Debug")
+	ret0, _ := ret[0].(*zerolog.Event)
+	return ret0
+}
+
+// Debug indicates an expected call of Debug.
+func (mr *MockLoggerInterfaceMockRecorder) Debug() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Debug", reflect.TypeOf((*MockLoggerInterface)(nil).Debug))
+}
+
 // Fatal mocks base method.
 func (m *MockLoggerInterface) Fatal() *zerolog.Event {
 	m.ctrl.T.Helper()
@@ -75,3 +91,17 @@ func (mr *MockLoggerInterfaceMockRecorder) Warn() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warn", reflect.TypeOf((*MockLoggerInterface)(nil).Warn))
 }
+
+// WithContext mocks base method.
+func (m *MockLoggerInterface) WithContext(ctx context.Context) logger.LoggerInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithContext", ctx)
+	ret0, _ := ret[0].(logger.LoggerInterface)
+	return ret0
+}
+
+// WithContext indicates an expected call of WithContext.
+func (mr *MockLoggerInterfaceMockRecorder) WithContext(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockLoggerInterface)(nil).WithContext), ctx)
+}
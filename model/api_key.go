@@ -0,0 +1,105 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"myapp/util/policy"
+
+	"github.com/jinzhu/gorm"
+)
+
+type ApiKeys []*ApiKey
+
+func (a ApiKeys) ToDto() []ApiKeyDto {
+	keys := make([]ApiKeyDto, 0, len(a))
+
+	for _, key := range a {
+		keys = append(keys, *key.ToDto())
+	}
+
+	return keys
+}
+
+// ApiKey authenticates a service client by the X-API-Key header. The key
+// itself is only shown when minted: its SHA-256 is kept in its place.
+type ApiKey struct {
+	gorm.Model
+	Name string
+
+	// Prefix is the beginning of the key, which tells keys apart.
+	Prefix  string
+	KeyHash string
+
+	// Scopes are the permissions of the key, space separated.
+	Scopes string
+
+	CreatedBy  string
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// Permissions returns the scopes of the key.
+func (a ApiKey) Permissions() []policy.Permission {
+	fields := strings.Fields(a.Scopes)
+	perms := make([]policy.Permission, len(fields))
+	for i, field := range fields {
+		perms[i] = policy.Permission(field)
+	}
+
+	return perms
+}
+
+type ApiKeyDto struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+func (a ApiKey) ToDto() *ApiKeyDto {
+	return &ApiKeyDto{
+		ID:         a.ID,
+		Name:       a.Name,
+		Prefix:     a.Prefix,
+		Scopes:     strings.Fields(a.Scopes),
+		CreatedBy:  a.CreatedBy,
+		CreatedAt:  a.CreatedAt,
+		LastUsedAt: a.LastUsedAt,
+		RevokedAt:  a.RevokedAt,
+	}
+}
+
+// ApiKeyCreatedDto is a newly minted key, the only time the key is shown.
+type ApiKeyCreatedDto struct {
+	ApiKeyDto
+	Key string `json:"key"`
+}
+
+type ApiKeyForm struct {
+	Name   string   `json:"name" form:"required,max=255"`
+	Scopes []string `json:"scopes" form:"required,min=1,unique,dive,scope"`
+}
+
+func (f *ApiKeyForm) ToModel() *ApiKey {
+	return &ApiKey{
+		Name:   f.Name,
+		Scopes: strings.Join(f.Scopes, " "),
+	}
+}
+
+// ApiKeyScopesForm replaces the scopes of a key.
+type ApiKeyScopesForm struct {
+	Scopes []string `json:"scopes" form:"required,min=1,unique,dive,scope"`
+}
+
+type ApiKeyListDto struct {
+	Data   []ApiKeyDto `json:"data"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
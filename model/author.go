@@ -0,0 +1,57 @@
+package model
+
+import "github.com/jinzhu/gorm"
+
+type Authors []*Author
+
+func (a Authors) ToDto() []AuthorDto {
+	authors := make([]AuthorDto, 0, len(a))
+
+	for _, author := range a {
+		authors = append(authors, *author.ToDto())
+	}
+
+	return authors
+}
+
+type Author struct {
+	gorm.Model
+	Name string
+}
+
+type AuthorDto struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func (a Author) ToDto() *AuthorDto {
+	return &AuthorDto{
+		ID:   a.ID,
+		Name: a.Name,
+	}
+}
+
+type AuthorForm struct {
+	Name string `json:"name" form:"required,max=255"`
+}
+
+func (f *AuthorForm) ToModel() *Author {
+	return &Author{
+		Name: f.Name,
+	}
+}
+
+type AuthorListDto struct {
+	Data   []AuthorDto `json:"data"`
+	Total  int64       `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+}
+
+// AuthorBookListDto is a page of the books of an author.
+type AuthorBookListDto struct {
+	Data   []BookDto `json:"data"`
+	Total  int64     `json:"total"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+}
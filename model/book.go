@@ -3,6 +3,8 @@ package model
 import (
 	"time"
 
+	"myapp/util/isbn"
+
 	"github.com/jinzhu/gorm"
 )
 
@@ -19,6 +21,13 @@ func (b Books) ToDto() []BookDto {
 			PublishedDate: b[i].PublishedDate.Format("2006-01-02"),
 			ImageUrl:      b[i].ImageUrl,
 			Description:   b[i].Description,
+			CreatedBy:     b[i].CreatedBy,
+			Version:       b[i].Version,
+			AuthorIDs:     b[i].AuthorIDs,
+		}
+		book.ISBN13, book.ISBN10 = b[i].isbns()
+		if b[i].CoverType != "" {
+			book.ImageUrl, book.Thumbnails = b[i].coverURLs()
 		}
 
 		books = append(books, book)
@@ -34,6 +43,26 @@ type Book struct {
 	PublishedDate time.Time
 	ImageUrl      string
 	Description   string
+
+	// ISBN is the ISBN-13 of the book, ISBN-10s being converted; it's unique
+	// among the books, trashed ones included, and nil when unknown.
+	ISBN *string
+
+	// CoverType is the media type of the uploaded cover, empty when the book
+	// has none, and CoverKey the prefix of the blob keys of the upload.
+	CoverType string
+	CoverKey  string
+
+	// CreatedBy is the actor who created the book, empty for books created
+	// before it was recorded.
+	CreatedBy string
+
+	// Version is incremented on every update; it's the ETag of the book.
+	Version uint
+
+	// AuthorIDs are the authors of the book in order, when they have been
+	// read or are to be set; they are stored in book_authors.
+	AuthorIDs []uint `gorm:"-"`
 }
 
 type BookDto struct {
@@ -43,25 +72,67 @@ type BookDto struct {
 	PublishedDate string `json:"published_date"`
 	ImageUrl      string `json:"image_url"`
 	Description   string `json:"description"`
+	ISBN13        string `json:"isbn_13,omitempty"`
+	ISBN10        string `json:"isbn_10,omitempty"`
+	CreatedBy     string `json:"created_by,omitempty"`
+	Version       uint   `json:"-"`
+	AuthorIDs     []uint `json:"author_ids,omitempty"`
+
+	// Thumbnails maps the sizes of the thumbnails of the uploaded cover to
+	// their URLs; with an uploaded cover, ImageUrl is its URL.
+	Thumbnails map[string]string `json:"thumbnails,omitempty"`
 }
 
 func (b Book) ToDto() *BookDto {
-	return &BookDto{
+	dto := &BookDto{
 		ID:            b.ID,
 		Title:         b.Title,
 		Author:        b.Author,
 		PublishedDate: b.PublishedDate.Format("2006-01-02"),
 		ImageUrl:      b.ImageUrl,
 		Description:   b.Description,
+		CreatedBy:     b.CreatedBy,
+		Version:       b.Version,
+		AuthorIDs:     b.AuthorIDs,
+	}
+	dto.ISBN13, dto.ISBN10 = b.isbns()
+	if b.CoverType != "" {
+		dto.ImageUrl, dto.Thumbnails = b.coverURLs()
 	}
+
+	return dto
+}
+
+// isbns returns the ISBN-13 of the book, and its ISBN-10 when it has one.
+func (b Book) isbns() (string, string) {
+	if b.ISBN == nil {
+		return "", ""
+	}
+
+	isbn10, _ := isbn.To10(*b.ISBN)
+
+	return *b.ISBN, isbn10
 }
 
 type BookForm struct {
-	Title         string `json:"title"`
-	Author        string `json:"author"`
-	PublishedDate string `json:"published_date"`
-	ImageUrl      string `json:"image_url"`
-	Description   string `json:"description"`
+	Title         string `json:"title" form:"required,max=255"`
+	Author        string `json:"author" form:"required,max=255"`
+	PublishedDate string `json:"published_date" form:"required,date,not_future"`
+
+	// ImageUrl is an HTTP URL. The URL of an uploaded cover, which books with
+	// one are read with, is accepted too and leaves the image URL as it is.
+	ImageUrl    string `json:"image_url" form:"omitempty,max=255,http_url|cover_url"`
+	Description string `json:"description" form:"max_bytes=65535"`
+
+	// ISBN is an ISBN-10 or ISBN-13, with or without hyphens; it's stored as
+	// an ISBN-13.
+	ISBN string `json:"isbn,omitempty" form:"omitempty,isbn"`
+
+	// AuthorIDs replaces the authors of the book when set. When omitted, the
+	// author named by Author, created on first use, becomes the only author
+	// of a new book or of a book whose Author changes; otherwise the authors
+	// are left as they are.
+	AuthorIDs []uint `json:"author_ids,omitempty" form:"omitempty,max=20,unique,dive,min=1"`
 }
 
 func (f *BookForm) ToModel() (*Book, error) {
@@ -70,11 +141,22 @@ func (f *BookForm) ToModel() (*Book, error) {
 		return nil, err
 	}
 
-	return &Book{
+	book := &Book{
 		Title:         f.Title,
 		Author:        f.Author,
 		PublishedDate: pubDate,
 		ImageUrl:      f.ImageUrl,
 		Description:   f.Description,
-	}, nil
+		AuthorIDs:     f.AuthorIDs,
+	}
+
+	if f.ISBN != "" {
+		isbn13, err := isbn.Normalize(f.ISBN)
+		if err != nil {
+			return nil, err
+		}
+		book.ISBN = &isbn13
+	}
+
+	return book, nil
 }
@@ -0,0 +1,84 @@
+package model
+
+import "time"
+
+type BookAuditAction string
+
+const (
+	BookAuditCreate  BookAuditAction = "create"
+	BookAuditUpdate  BookAuditAction = "update"
+	BookAuditDelete  BookAuditAction = "delete"
+	BookAuditRestore BookAuditAction = "restore"
+	BookAuditPurge   BookAuditAction = "purge"
+)
+
+// BookSnapshot is the state of a book recorded in its audit trail.
+type BookSnapshot struct {
+	Title         string     `json:"title"`
+	Author        string     `json:"author"`
+	PublishedDate string     `json:"published_date"`
+	ImageUrl      string     `json:"image_url"`
+	Description   string     `json:"description"`
+	ISBN          string     `json:"isbn,omitempty"`
+	CoverType     string     `json:"cover_type,omitempty"`
+	Version       uint       `json:"version"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+}
+
+func (b Book) Snapshot() *BookSnapshot {
+	snapshot := &BookSnapshot{
+		Title:         b.Title,
+		Author:        b.Author,
+		PublishedDate: b.PublishedDate.Format("2006-01-02"),
+		ImageUrl:      b.ImageUrl,
+		Description:   b.Description,
+		CoverType:     b.CoverType,
+		Version:       b.Version,
+		DeletedAt:     b.DeletedAt,
+	}
+	snapshot.ISBN, _ = b.isbns()
+
+	return snapshot
+}
+
+// BookAudit records a change of a book. Before is nil when the book wasn't
+// in the catalog before the change, After when it isn't anymore.
+type BookAudit struct {
+	ID        uint64
+	BookID    uint
+	Action    BookAuditAction
+	Before    *BookSnapshot
+	After     *BookSnapshot
+	Actor     string
+	RequestID string
+	CreatedAt time.Time
+}
+
+type BookAuditDto struct {
+	ID        uint64          `json:"id"`
+	Action    BookAuditAction `json:"action"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	Before    *BookSnapshot   `json:"before"`
+	After     *BookSnapshot   `json:"after"`
+}
+
+func (a BookAudit) ToDto() BookAuditDto {
+	return BookAuditDto{
+		ID:        a.ID,
+		Action:    a.Action,
+		Actor:     a.Actor,
+		RequestID: a.RequestID,
+		CreatedAt: a.CreatedAt,
+		Before:    a.Before,
+		After:     a.After,
+	}
+}
+
+type BookHistoryDto struct {
+	Data   []BookAuditDto `json:"data"`
+	Total  int64          `json:"total"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
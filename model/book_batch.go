@@ -0,0 +1,47 @@
+package model
+
+// BookBatchMode tells how a batch behaves when one of its operations fails.
+type BookBatchMode string
+
+const (
+	// BookBatchAtomic applies all the operations in a single transaction, or none.
+	BookBatchAtomic BookBatchMode = "atomic"
+	// BookBatchBestEffort applies every operation which succeeds.
+	BookBatchBestEffort BookBatchMode = "best_effort"
+)
+
+type BookOperationType string
+
+const (
+	BookOperationCreate BookOperationType = "create"
+	BookOperationUpdate BookOperationType = "update"
+	BookOperationDelete BookOperationType = "delete"
+)
+
+// BookOperation is a single operation of a batch. ID and Version identify the
+// book to update or delete; Version is optional and makes the write conditional.
+type BookOperation struct {
+	Op      BookOperationType `json:"op"`
+	ID      uint              `json:"id,omitempty"`
+	Version uint              `json:"version,omitempty"`
+	Book    *BookForm         `json:"book,omitempty"`
+}
+
+type BookBatchForm struct {
+	Mode       BookBatchMode   `json:"mode"`
+	Operations []BookOperation `json:"operations"`
+}
+
+// BookOperationResult is the outcome of an operation; Err is nil on success.
+type BookOperationResult struct {
+	Op   BookOperationType
+	ID   uint
+	Book *BookDto
+	Err  error
+}
+
+type BookBatchResult struct {
+	Mode      BookBatchMode
+	Committed bool
+	Results   []BookOperationResult
+}
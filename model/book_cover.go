@@ -0,0 +1,99 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+)
+
+// CoverTypes are the media types of the images accepted as covers.
+var CoverTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// CoverSizes maps the names of the cover thumbnails to the maximum length of
+// their sides, in pixels.
+var CoverSizes = map[string]int{
+	"small":  160,
+	"medium": 320,
+	"large":  640,
+}
+
+// CoverThumbnailType is the media type of the cover thumbnails.
+const CoverThumbnailType = "image/jpeg"
+
+// coverPath is the path the API serves the cover of a book at.
+const coverPath = "/api/v1/books/%d/cover"
+
+// NewCoverKey returns the prefix of the blob keys of a new upload of the
+// cover of a book. Uploads have their own keys, so that concurrent ones can't
+// mix their blobs.
+func NewCoverKey(bookID uint) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("covers/%d/%s", bookID, hex.EncodeToString(b)), nil
+}
+
+// CoverKey returns the blob key of the cover uploaded under the prefix
+// coverKey, of its thumbnail of the size when size isn't empty.
+func CoverKey(coverKey, size string) string {
+	if size == "" {
+		return coverKey + "/original"
+	}
+
+	return fmt.Sprintf("%s/%s.jpg", coverKey, size)
+}
+
+// CoverKeys returns the blob keys of the cover uploaded under the prefix
+// coverKey and of its thumbnails.
+func CoverKeys(coverKey string) []string {
+	keys := []string{CoverKey(coverKey, "")}
+	for size := range CoverSizes {
+		keys = append(keys, CoverKey(coverKey, size))
+	}
+
+	return keys
+}
+
+// CoverURL returns the URL of the uploaded cover of the book.
+func CoverURL(bookID uint) string {
+	return fmt.Sprintf(coverPath, bookID)
+}
+
+// IsCoverURL reports whether s is the URL of the uploaded cover of a book, as
+// the image URL of the books with one is.
+func IsCoverURL(s string) bool {
+	var id uint
+	if _, err := fmt.Sscanf(s, coverPath, &id); err != nil {
+		return false
+	}
+
+	return s == CoverURL(id)
+}
+
+// coverURLs returns the URL of the uploaded cover of the book and those of its
+// thumbnails.
+func (b Book) coverURLs() (string, map[string]string) {
+	cover := CoverURL(b.ID)
+
+	thumbnails := make(map[string]string, len(CoverSizes))
+	for size := range CoverSizes {
+		thumbnails[size] = cover + "/" + size
+	}
+
+	return cover, thumbnails
+}
+
+// Cover is an image of the cover of a book read from the storage; its Body
+// must be closed.
+type Cover struct {
+	Body        io.ReadCloser
+	Size        int64
+	ContentType string
+}
@@ -0,0 +1,320 @@
+package model
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BookFileFormat is a file format of the catalog import and export.
+type BookFileFormat string
+
+const (
+	// BookFileCSV has a header row naming the columns, as spreadsheets save it.
+	BookFileCSV BookFileFormat = "csv"
+	// BookFileJSONL has a JSON object per line.
+	BookFileJSONL BookFileFormat = "jsonl"
+)
+
+// BookFileMediaTypes maps the file formats to their media types.
+var BookFileMediaTypes = map[BookFileFormat]string{
+	BookFileCSV:   "text/csv",
+	BookFileJSONL: "application/x-ndjson",
+}
+
+// maxJSONLine bounds a line of a JSON Lines file; a book is far smaller.
+const maxJSONLine = 1 << 20
+
+// utf8BOM is written by spreadsheets at the start of CSV files.
+const utf8BOM = "\ufeff"
+
+// exportColumns are the CSV columns of an export. Imports take the same
+// columns, id being ignored as they always create books.
+var exportColumns = []string{"id", "title", "author", "published_date", "image_url", "description", "isbn"}
+
+// ignoredColumns are the read-only CSV columns of an export, which imports
+// skip so that exports can be imported back.
+var ignoredColumns = map[string]bool{"id": true}
+
+// formulaPrefixes start the cells spreadsheets evaluate as formulas. Such
+// cells are exported with a leading quote, which imports remove.
+const formulaPrefixes = "=+-@\t\r"
+
+// importColumns are the CSV columns of an import, title, author and
+// published_date being required.
+var importColumns = map[string]func(f *BookForm) *string{
+	"title":          func(f *BookForm) *string { return &f.Title },
+	"author":         func(f *BookForm) *string { return &f.Author },
+	"published_date": func(f *BookForm) *string { return &f.PublishedDate },
+	"image_url":      func(f *BookForm) *string { return &f.ImageUrl },
+	"description":    func(f *BookForm) *string { return &f.Description },
+	"isbn":           func(f *BookForm) *string { return &f.ISBN },
+}
+
+// BookImportRow is a book read from an import file, or the reason the line
+// couldn't be read.
+type BookImportRow struct {
+	Line int
+	Form *BookForm
+	Err  error
+}
+
+type BookImportResult struct {
+	DryRun   bool `json:"dry_run"`
+	Rows     int  `json:"rows"`
+	Imported int  `json:"imported"`
+}
+
+// BookReader reads the books of an import file.
+type BookReader interface {
+	// Read returns the next row, or io.EOF after the last one. Other errors
+	// come from the underlying reader and end the file.
+	Read() (*BookImportRow, error)
+}
+
+func NewBookReader(format BookFileFormat, r io.Reader) BookReader {
+	if format == BookFileJSONL {
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 0, 64*1024), maxJSONLine)
+		return &jsonlBookReader{scanner: s}
+	}
+
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	return &csvBookReader{reader: cr}
+}
+
+type csvBookReader struct {
+	reader  *csv.Reader
+	columns []func(f *BookForm) *string
+	done    bool
+}
+
+func (c *csvBookReader) Read() (*BookImportRow, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+
+	if c.columns == nil {
+		row, err := c.readHeader()
+		if row != nil || err != nil {
+			c.done = true
+			return row, err
+		}
+	}
+
+	record, err := c.reader.Read()
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &BookImportRow{Line: parseErr.Line, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	if len(record) != len(c.columns) {
+		return &BookImportRow{Line: line, Err: fmt.Errorf("has %d fields, expected %d", len(record), len(c.columns))}, nil
+	}
+
+	form := &BookForm{}
+	for i, value := range record {
+		if field := c.columns[i]; field != nil {
+			*field(form) = unescapeFormula(value)
+		}
+	}
+
+	return &BookImportRow{Line: line, Form: form}, nil
+}
+
+// readHeader maps the columns of the header row, and returns a row reporting
+// the header when it is invalid.
+func (c *csvBookReader) readHeader() (*BookImportRow, error) {
+	header, err := c.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &BookImportRow{Line: parseErr.Line, Err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	line, _ := c.reader.FieldPos(0)
+	header[0] = strings.TrimPrefix(header[0], utf8BOM)
+
+	seen := make(map[string]bool, len(header))
+	columns := make([]func(f *BookForm) *string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		field, ok := importColumns[name]
+		if !ok && !ignoredColumns[name] {
+			return &BookImportRow{Line: line, Err: fmt.Errorf("unknown column %q", name)}, nil
+		}
+		if seen[name] {
+			return &BookImportRow{Line: line, Err: fmt.Errorf("duplicate column %q", name)}, nil
+		}
+
+		seen[name] = true
+		columns[i] = field
+	}
+
+	for _, name := range []string{"title", "author", "published_date"} {
+		if !seen[name] {
+			return &BookImportRow{Line: line, Err: fmt.Errorf("missing column %q", name)}, nil
+		}
+	}
+
+	c.columns = columns
+
+	return nil, nil
+}
+
+// jsonlBook is a book of a JSON Lines import. It takes the read-only fields of
+// an export too, which are ignored, so that exports can be imported back; the
+// ISBN-13 of an export is the ISBN of the book.
+type jsonlBook struct {
+	BookForm
+	ID         json.RawMessage `json:"id"`
+	ISBN13     string          `json:"isbn_13"`
+	ISBN10     json.RawMessage `json:"isbn_10"`
+	CreatedBy  json.RawMessage `json:"created_by"`
+	Thumbnails json.RawMessage `json:"thumbnails"`
+}
+
+type jsonlBookReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (j *jsonlBookReader) Read() (*BookImportRow, error) {
+	for j.scanner.Scan() {
+		j.line++
+
+		data := bytes.TrimSpace(j.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		book := &jsonlBook{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(book); err != nil {
+			return &BookImportRow{Line: j.line, Err: err}, nil
+		}
+		if dec.More() {
+			return &BookImportRow{Line: j.line, Err: errors.New("has data after the book")}, nil
+		}
+
+		form := &book.BookForm
+		if form.ISBN == "" {
+			form.ISBN = book.ISBN13
+		}
+
+		return &BookImportRow{Line: j.line, Form: form}, nil
+	}
+
+	if err := j.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("line %d is longer than %d bytes", j.line+1, maxJSONLine)
+		}
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+// BookWriter writes the books of an export.
+type BookWriter interface {
+	Write(book *BookDto) error
+	// Flush writes any buffered data, and the CSV header of an empty export.
+	Flush() error
+}
+
+func NewBookWriter(format BookFileFormat, w io.Writer) BookWriter {
+	if format == BookFileJSONL {
+		return &jsonlBookWriter{enc: json.NewEncoder(w)}
+	}
+
+	return &csvBookWriter{writer: csv.NewWriter(w)}
+}
+
+type csvBookWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvBookWriter) Write(book *BookDto) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	return c.writer.Write([]string{
+		strconv.FormatUint(uint64(book.ID), 10),
+		escapeFormula(book.Title),
+		escapeFormula(book.Author),
+		book.PublishedDate,
+		escapeFormula(book.ImageUrl),
+		escapeFormula(book.Description),
+		book.ISBN13,
+	})
+}
+
+// escapeFormula prefixes value with a quote when a spreadsheet would evaluate
+// it as a formula, or when it already starts with a quote, so that unescaping
+// it gives value back.
+func escapeFormula(value string) string {
+	if value != "" && (strings.IndexByte(formulaPrefixes, value[0]) >= 0 || value[0] == '\'') {
+		return "'" + value
+	}
+
+	return value
+}
+
+// unescapeFormula removes the quote escapeFormula prefixed value with.
+func unescapeFormula(value string) string {
+	if len(value) > 1 && value[0] == '\'' && (strings.IndexByte(formulaPrefixes, value[1]) >= 0 || value[1] == '\'') {
+		return value[1:]
+	}
+
+	return value
+}
+
+func (c *csvBookWriter) writeHeader() error {
+	if c.headerWritten {
+		return nil
+	}
+
+	c.headerWritten = true
+	return c.writer.Write(exportColumns)
+}
+
+func (c *csvBookWriter) Flush() error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type jsonlBookWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonlBookWriter) Write(book *BookDto) error {
+	return j.enc.Encode(book)
+}
+
+func (j *jsonlBookWriter) Flush() error {
+	return nil
+}
@@ -0,0 +1,86 @@
+package model_test
+
+import (
+	"bytes"
+	"myapp/model"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookFile_RoundTrip(t *testing.T) {
+	books := []*model.BookDto{
+		{
+			ID:            1,
+			Title:         "Dune",
+			Author:        "Frank Herbert",
+			PublishedDate: "1965-08-01",
+			ImageUrl:      "https://example.com/dune.jpg",
+			Description:   "Spice, sand and \"worms\",\nover two lines",
+			ISBN13:        "9780441013593",
+			ISBN10:        "0441013597",
+			CreatedBy:     "alice",
+			AuthorIDs:     []uint{3},
+			Thumbnails:    map[string]string{"small": "/api/v1/books/1/cover/small"},
+		},
+		{
+			ID:            2,
+			Title:         "=HYPERLINK(\"https://evil.example\")",
+			Author:        "@mention",
+			PublishedDate: "2001-01-01",
+			Description:   "'quoted",
+		},
+	}
+
+	for _, format := range []model.BookFileFormat{model.BookFileCSV, model.BookFileJSONL} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := model.NewBookWriter(format, &buf)
+			for _, book := range books {
+				if err := w.Write(book); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			r := model.NewBookReader(format, &buf)
+			for _, book := range books {
+				row, err := r.Read()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !assert.NoError(t, row.Err) {
+					continue
+				}
+
+				want := &model.BookForm{
+					Title:         book.Title,
+					Author:        book.Author,
+					PublishedDate: book.PublishedDate,
+					ImageUrl:      book.ImageUrl,
+					Description:   book.Description,
+					ISBN:          book.ISBN13,
+				}
+				if format == model.BookFileJSONL {
+					want.AuthorIDs = book.AuthorIDs
+				}
+				assert.Equal(t, want, row.Form)
+			}
+		})
+	}
+}
+
+func TestBookFile_CSVFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w := model.NewBookWriter(model.BookFileCSV, &buf)
+	if err := w.Write(&model.BookDto{ID: 1, Title: "=1+1", Author: "+Author", PublishedDate: "2001-01-01", Description: "-note"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "id,title,author,published_date,image_url,description,isbn\n1,'=1+1,'+Author,2001-01-01,,'-note,\n", buf.String())
+}
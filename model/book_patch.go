@@ -0,0 +1,29 @@
+package model
+
+// PatchFormat is the media type of a PATCH document.
+type PatchFormat string
+
+const (
+	// PatchMerge is a JSON Merge Patch, RFC 7396.
+	PatchMerge PatchFormat = "application/merge-patch+json"
+	// PatchJSON is a JSON Patch, RFC 6902.
+	PatchJSON PatchFormat = "application/json-patch+json"
+)
+
+// PatchFormats lists the formats accepted by PATCH, as advertised in Accept-Patch.
+var PatchFormats = []PatchFormat{PatchMerge, PatchJSON}
+
+// ToForm returns the document patches are applied against.
+func (b Book) ToForm() *BookForm {
+	form := &BookForm{
+		Title:         b.Title,
+		Author:        b.Author,
+		PublishedDate: b.PublishedDate.Format("2006-01-02"),
+		ImageUrl:      b.ImageUrl,
+		Description:   b.Description,
+		AuthorIDs:     b.AuthorIDs,
+	}
+	form.ISBN, _ = b.isbns()
+
+	return form
+}
@@ -0,0 +1,240 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultBookListLimit = 20
+	MaxBookListLimit     = 100
+)
+
+// bookSortColumns maps the sort keys accepted by the API to the books table columns.
+var bookSortColumns = map[string]string{
+	"id":             "id",
+	"title":          "title",
+	"author":         "author",
+	"published_date": "published_date",
+	"created_at":     "created_at",
+}
+
+type BookSort struct {
+	Column string
+	Desc   bool
+}
+
+// BookCursor points at the book a keyset page starts after (or before, when Backward is set).
+type BookCursor struct {
+	Values   []string `json:"v"`
+	ID       uint     `json:"id"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+type BookQuery struct {
+	Limit         int
+	Offset        int
+	Cursor        *BookCursor
+	Author        string
+	Title         string
+	Tag           string
+	Genre         string
+	PublishedFrom *time.Time
+	PublishedTo   *time.Time
+	Sort          []BookSort
+}
+
+// CursorFor builds the cursor pointing at b under the query sort order.
+func (q *BookQuery) CursorFor(b *Book, backward bool) *BookCursor {
+	c := &BookCursor{ID: b.ID, Backward: backward}
+
+	for _, s := range q.Sort {
+		switch s.Column {
+		case "title":
+			c.Values = append(c.Values, b.Title)
+		case "author":
+			c.Values = append(c.Values, b.Author)
+		case "published_date":
+			c.Values = append(c.Values, b.PublishedDate.Format("2006-01-02"))
+		case "created_at":
+			c.Values = append(c.Values, b.CreatedAt.Format(time.RFC3339Nano))
+		case "id":
+			c.Values = append(c.Values, strconv.FormatUint(uint64(b.ID), 10))
+		}
+	}
+
+	return c
+}
+
+// CursorValue converts the i-th cursor value into the type of the matching sort column.
+func (q *BookQuery) CursorValue(i int) (interface{}, error) {
+	v := q.Cursor.Values[i]
+
+	switch q.Sort[i].Column {
+	case "published_date":
+		return time.Parse("2006-01-02", v)
+	case "created_at":
+		return time.Parse(time.RFC3339Nano, v)
+	case "id":
+		return strconv.ParseUint(v, 10, 64)
+	default:
+		return v, nil
+	}
+}
+
+func (c *BookCursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func DecodeBookCursor(s string) (*BookCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &BookCursor{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+type BookListDto struct {
+	Data       []BookDto `json:"data"`
+	Total      int64     `json:"total"`
+	Limit      int       `json:"limit"`
+	Offset     int       `json:"offset"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+	PrevCursor string    `json:"prev_cursor,omitempty"`
+	Links      PageLinks `json:"links"`
+}
+
+type PageLinks struct {
+	Self string `json:"self"`
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+type BookListForm struct {
+	Limit         string
+	Offset        string
+	Cursor        string
+	Author        string
+	Title         string
+	Tag           string
+	Genre         string
+	PublishedFrom string
+	PublishedTo   string
+	Sort          string
+}
+
+func NewBookListForm(v url.Values) *BookListForm {
+	return &BookListForm{
+		Limit:         v.Get("limit"),
+		Offset:        v.Get("offset"),
+		Cursor:        v.Get("cursor"),
+		Author:        v.Get("author"),
+		Title:         v.Get("title"),
+		Tag:           v.Get("tag"),
+		Genre:         v.Get("genre"),
+		PublishedFrom: v.Get("published_from"),
+		PublishedTo:   v.Get("published_to"),
+		Sort:          v.Get("sort"),
+	}
+}
+
+func (f *BookListForm) ToQuery() (*BookQuery, error) {
+	q := &BookQuery{
+		Limit:  DefaultBookListLimit,
+		Author: strings.TrimSpace(f.Author),
+		Title:  strings.TrimSpace(f.Title),
+		Tag:    strings.TrimSpace(f.Tag),
+		Genre:  strings.TrimSpace(f.Genre),
+	}
+
+	if f.Limit != "" {
+		limit, err := strconv.Atoi(f.Limit)
+		if err != nil || limit < 1 || limit > MaxBookListLimit {
+			return nil, fmt.Errorf("limit must be an integer between 1 and %d", MaxBookListLimit)
+		}
+		q.Limit = limit
+	}
+
+	if f.Offset != "" {
+		offset, err := strconv.Atoi(f.Offset)
+		if err != nil || offset < 0 {
+			return nil, errors.New("offset must be a non-negative integer")
+		}
+		q.Offset = offset
+	}
+
+	for _, d := range []struct {
+		raw string
+		dst **time.Time
+		key string
+	}{
+		{f.PublishedFrom, &q.PublishedFrom, "published_from"},
+		{f.PublishedTo, &q.PublishedTo, "published_to"},
+	} {
+		if d.raw == "" {
+			continue
+		}
+		t, err := time.Parse("2006-01-02", d.raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a date in YYYY-MM-DD format", d.key)
+		}
+		*d.dst = &t
+	}
+
+	if q.PublishedFrom != nil && q.PublishedTo != nil && q.PublishedFrom.After(*q.PublishedTo) {
+		return nil, errors.New("published_from must not be after published_to")
+	}
+
+	if f.Sort != "" {
+		seen := map[string]bool{}
+		for _, key := range strings.Split(f.Sort, ",") {
+			key = strings.TrimSpace(key)
+			desc := strings.HasPrefix(key, "-")
+			key = strings.TrimPrefix(key, "-")
+
+			column, ok := bookSortColumns[key]
+			if !ok {
+				return nil, fmt.Errorf("unsupported sort field %q", key)
+			}
+			if seen[column] {
+				return nil, fmt.Errorf("duplicate sort field %q", key)
+			}
+			seen[column] = true
+
+			q.Sort = append(q.Sort, BookSort{Column: column, Desc: desc})
+		}
+	}
+
+	if f.Cursor != "" {
+		if q.Offset != 0 {
+			return nil, errors.New("cursor and offset can not be combined")
+		}
+
+		c, err := DecodeBookCursor(f.Cursor)
+		if err != nil || len(c.Values) != len(q.Sort) {
+			return nil, errors.New("cursor is invalid or does not match the sort order")
+		}
+		q.Cursor = c
+
+		for i := range c.Values {
+			if _, err := q.CursorValue(i); err != nil {
+				return nil, errors.New("cursor is invalid or does not match the sort order")
+			}
+		}
+	}
+
+	return q, nil
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const MaxBookSearchQueryLength = 255
+
+// BookSearchHit is a book matched by the full-text search together with its relevance.
+type BookSearchHit struct {
+	Book
+	Score float64
+}
+
+type BookSearchHits []*BookSearchHit
+
+type BookSearchQuery struct {
+	Q      string
+	Limit  int
+	Offset int
+}
+
+// Terms returns the words of the search query, used to highlight matches.
+func (q *BookSearchQuery) Terms() []string {
+	return strings.FieldsFunc(q.Q, func(r rune) bool {
+		return !(r == '\'' || r == '-' || ('0' <= r && r <= '9') || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || r > 127)
+	})
+}
+
+type BookSearchHitDto struct {
+	BookDto
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+type BookSearchListDto struct {
+	Data   []BookSearchHitDto `json:"data"`
+	Total  int64              `json:"total"`
+	Limit  int                `json:"limit"`
+	Offset int                `json:"offset"`
+}
+
+type BookSearchForm struct {
+	Q      string
+	Limit  string
+	Offset string
+}
+
+func NewBookSearchForm(v url.Values) *BookSearchForm {
+	return &BookSearchForm{
+		Q:      v.Get("q"),
+		Limit:  v.Get("limit"),
+		Offset: v.Get("offset"),
+	}
+}
+
+func (f *BookSearchForm) ToQuery() (*BookSearchQuery, error) {
+	q := &BookSearchQuery{
+		Q:     strings.TrimSpace(f.Q),
+		Limit: DefaultBookListLimit,
+	}
+
+	if q.Q == "" {
+		return nil, errors.New("q is required")
+	}
+	if len(q.Q) > MaxBookSearchQueryLength {
+		return nil, fmt.Errorf("q must be a maximum of %d in length", MaxBookSearchQueryLength)
+	}
+
+	if f.Limit != "" {
+		limit, err := strconv.Atoi(f.Limit)
+		if err != nil || limit < 1 || limit > MaxBookListLimit {
+			return nil, fmt.Errorf("limit must be an integer between 1 and %d", MaxBookListLimit)
+		}
+		q.Limit = limit
+	}
+
+	if f.Offset != "" {
+		offset, err := strconv.Atoi(f.Offset)
+		if err != nil || offset < 0 {
+			return nil, errors.New("offset must be a non-negative integer")
+		}
+		q.Offset = offset
+	}
+
+	return q, nil
+}
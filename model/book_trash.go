@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// DeletedBookDto is a soft-deleted book, as listed in the trash.
+type DeletedBookDto struct {
+	BookDto
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type BookTrashListDto struct {
+	Data   []DeletedBookDto `json:"data"`
+	Total  int64            `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+func (b Books) ToDeletedDto() []DeletedBookDto {
+	books := make([]DeletedBookDto, 0, len(b))
+
+	for _, book := range b {
+		dto := DeletedBookDto{BookDto: *book.ToDto()}
+		if book.DeletedAt != nil {
+			dto.DeletedAt = *book.DeletedAt
+		}
+
+		books = append(books, dto)
+	}
+
+	return books
+}
@@ -0,0 +1,68 @@
+package model
+
+import "github.com/jinzhu/gorm"
+
+type Collections []*Collection
+
+func (c Collections) ToDto() []CollectionDto {
+	collections := make([]CollectionDto, 0, len(c))
+
+	for _, collection := range c {
+		collections = append(collections, *collection.ToDto())
+	}
+
+	return collections
+}
+
+// Collection is an ordered list of books curated by its owner.
+type Collection struct {
+	gorm.Model
+	Name        string
+	Description string
+	Owner       string
+
+	// BookIDs are the books of the collection in order, when they are to be
+	// set; they are stored in collection_books.
+	BookIDs []uint `gorm:"-"`
+}
+
+type CollectionDto struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Owner       string    `json:"owner"`
+	Books       []BookDto `json:"books,omitempty"`
+}
+
+func (c Collection) ToDto() *CollectionDto {
+	return &CollectionDto{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Owner:       c.Owner,
+	}
+}
+
+type CollectionForm struct {
+	Name        string `json:"name" form:"required,max=255"`
+	Description string `json:"description" form:"max_bytes=65535"`
+
+	// BookIDs are the books of the collection in order; they replace the
+	// current ones.
+	BookIDs []uint `json:"book_ids" form:"max=1000,unique,dive,min=1"`
+}
+
+func (f *CollectionForm) ToModel() *Collection {
+	return &Collection{
+		Name:        f.Name,
+		Description: f.Description,
+		BookIDs:     f.BookIDs,
+	}
+}
+
+type CollectionListDto struct {
+	Data   []CollectionDto `json:"data"`
+	Total  int64           `json:"total"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
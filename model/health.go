@@ -0,0 +1,22 @@
+package model
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+type HealthCheckDto struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthDto struct {
+	Status string           `json:"status"`
+	Checks []HealthCheckDto `json:"checks,omitempty"`
+}
+
+func (h *HealthDto) IsUp() bool {
+	return h.Status == HealthStatusUp
+}
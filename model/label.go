@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// LabelKind tells the kinds of labels books are categorized with apart.
+type LabelKind string
+
+const (
+	// LabelTag is a free-form label, created the first time it's attached.
+	LabelTag LabelKind = "tag"
+	// LabelGenre is a curated label, created before books are attached to it.
+	LabelGenre LabelKind = "genre"
+)
+
+type Labels []*Label
+
+func (l Labels) ToDto() []LabelDto {
+	labels := make([]LabelDto, 0, len(l))
+
+	for _, label := range l {
+		labels = append(labels, label.ToDto())
+	}
+
+	return labels
+}
+
+// Label is a tag or a genre, depending on the table it's stored in.
+type Label struct {
+	ID        uint
+	Name      string
+	CreatedAt time.Time
+}
+
+type LabelDto struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+func (l Label) ToDto() LabelDto {
+	return LabelDto{
+		ID:   l.ID,
+		Name: l.Name,
+	}
+}
+
+type LabelForm struct {
+	Name string `json:"name" form:"required,max=64"`
+}
+
+type LabelListDto struct {
+	Data   []LabelDto `json:"data"`
+	Total  int64      `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// BookLabelListDto lists the labels of one kind attached to a book.
+type BookLabelListDto struct {
+	Data []LabelDto `json:"data"`
+}
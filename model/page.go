@@ -0,0 +1,50 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// PageQuery selects a page of a list paginated by offset.
+type PageQuery struct {
+	Limit  int
+	Offset int
+}
+
+type PageForm struct {
+	Limit  string
+	Offset string
+}
+
+func NewPageForm(v url.Values) *PageForm {
+	return &PageForm{
+		Limit:  v.Get("limit"),
+		Offset: v.Get("offset"),
+	}
+}
+
+func (f *PageForm) ToQuery() (*PageQuery, error) {
+	q := &PageQuery{
+		Limit: DefaultBookListLimit,
+	}
+
+	if f.Limit != "" {
+		limit, err := strconv.Atoi(f.Limit)
+		if err != nil || limit < 1 || limit > MaxBookListLimit {
+			return nil, fmt.Errorf("limit must be an integer between 1 and %d", MaxBookListLimit)
+		}
+		q.Limit = limit
+	}
+
+	if f.Offset != "" {
+		offset, err := strconv.Atoi(f.Offset)
+		if err != nil || offset < 0 {
+			return nil, errors.New("offset must be a non-negative integer")
+		}
+		q.Offset = offset
+	}
+
+	return q, nil
+}
@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/util/apperror"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type ApiKeyRepo struct {
+	repo         *gorm.DB
+	queryTimeout time.Duration
+}
+
+func NewApiKeyRepo(conn *gorm.DB, queryTimeout time.Duration) *ApiKeyRepo {
+	return &ApiKeyRepo{
+		repo:         conn,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// ListApiKeys returns a page of the API keys in id order, the revoked ones
+// included, and their total count.
+func (r *ApiKeyRepo) ListApiKeys(ctx context.Context, query *model.PageQuery) (model.ApiKeys, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Model(&model.ApiKey{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "api key")
+	}
+
+	keys := make([]*model.ApiKey, 0)
+	if err := db.Order("id ASC").Offset(query.Offset).Limit(query.Limit).Find(&keys).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "api key")
+	}
+
+	return keys, total, nil
+}
+
+func (r *ApiKeyRepo) CreateApiKey(ctx context.Context, key *model.ApiKey) (*model.ApiKey, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	if err := conn.Create(key).Error; err != nil {
+		return nil, translateError(ctx, err, "api key")
+	}
+
+	return key, nil
+}
+
+// ReadApiKeyByHash returns the API key of the SHA-256 of a key, revoked or not.
+func (r *ApiKeyRepo) ReadApiKeyByHash(ctx context.Context, hash string) (*model.ApiKey, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	key := &model.ApiKey{}
+	if err := conn.Where("key_hash = ?", hash).First(key).Error; err != nil {
+		return nil, translateError(ctx, err, "api key")
+	}
+
+	return key, nil
+}
+
+// UpdateApiKeyScopes replaces the scopes of the API key, unless it's revoked.
+func (r *ApiKeyRepo) UpdateApiKeyScopes(ctx context.Context, id uint, scopes string) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Model(&model.ApiKey{}).Where("id = ? AND revoked_at IS NULL", id).Updates(map[string]interface{}{
+		"scopes": scopes,
+	})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "api key")
+	}
+
+	// Matched rows are affected, changed or not (ClientFoundRows), so no
+	// affected row means no matching row.
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("api key not found", nil)
+	}
+
+	return nil
+}
+
+// RevokeApiKey revokes the API key at the time at; a key already revoked
+// keeps the time it was revoked at.
+func (r *ApiKeyRepo) RevokeApiKey(ctx context.Context, id uint, at time.Time) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Model(&model.ApiKey{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"revoked_at": gorm.Expr("COALESCE(revoked_at, ?)", at),
+	})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "api key")
+	}
+
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("api key not found", nil)
+	}
+
+	return nil
+}
+
+// TouchApiKey records that the API key was last used at the time at.
+func (r *ApiKeyRepo) TouchApiKey(ctx context.Context, id uint, at time.Time) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	if err := conn.Model(&model.ApiKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error; err != nil {
+		return translateError(ctx, err, "api key")
+	}
+
+	return nil
+}
+
+type ApiKeyRepoInterface interface {
+	ListApiKeys(ctx context.Context, query *model.PageQuery) (model.ApiKeys, int64, error)
+	CreateApiKey(ctx context.Context, key *model.ApiKey) (*model.ApiKey, error)
+	ReadApiKeyByHash(ctx context.Context, hash string) (*model.ApiKey, error)
+	UpdateApiKeyScopes(ctx context.Context, id uint, scopes string) error
+	RevokeApiKey(ctx context.Context, id uint, at time.Time) error
+	TouchApiKey(ctx context.Context, id uint, at time.Time) error
+}
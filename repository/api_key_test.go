@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"context"
+	"myapp/repository"
+	"myapp/util/apperror"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApiKeyRepo_ReadApiKeyByHash(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewApiKeyRepo(db, time.Second)
+
+	query := "SELECT * FROM `api_keys` WHERE `api_keys`.`deleted_at` IS NULL AND ((key_hash = ?)) ORDER BY `api_keys`.`id` ASC LIMIT 1"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("5e88").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name", "scopes"}).AddRow(1, "nightly import", "books:read books:write"))
+
+		key, err := repo.ReadApiKeyByHash(context.Background(), "5e88")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), key.ID)
+		assert.Equal(t, "books:read books:write", key.Scopes)
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("5e88").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.ReadApiKeyByHash(context.Background(), "5e88")
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestApiKeyRepo_UpdateApiKeyScopes(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewApiKeyRepo(db, time.Second)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `api_keys` SET `scopes` = ?, `updated_at` = ? WHERE `api_keys`.`deleted_at` IS NULL AND ((id = ? AND revoked_at IS NULL))").
+		WithArgs("books:read", AnyTime{}, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.UpdateApiKeyScopes(context.Background(), 1, "books:read")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestApiKeyRepo_RevokeApiKey(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewApiKeyRepo(db, time.Second)
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `api_keys` SET `revoked_at` = COALESCE(revoked_at, ?), `updated_at` = ? WHERE `api_keys`.`deleted_at` IS NULL AND ((id = ?))").
+		WithArgs(now, AnyTime{}, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.RevokeApiKey(context.Background(), 1, now)
+	assert.NoError(t, err)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
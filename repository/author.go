@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/util/apperror"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type AuthorRepo struct {
+	repo         *gorm.DB
+	queryTimeout time.Duration
+}
+
+func NewAuthorRepo(conn *gorm.DB, queryTimeout time.Duration) *AuthorRepo {
+	return &AuthorRepo{
+		repo:         conn,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// ListAuthors returns a page of the authors in name order and their total count.
+func (r *AuthorRepo) ListAuthors(ctx context.Context, query *model.PageQuery) (model.Authors, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Model(&model.Author{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "author")
+	}
+
+	authors := make([]*model.Author, 0)
+	if err := db.Order("name ASC").Order("id ASC").Offset(query.Offset).Limit(query.Limit).Find(&authors).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "author")
+	}
+
+	return authors, total, nil
+}
+
+func (r *AuthorRepo) ReadAuthor(ctx context.Context, id uint) (*model.Author, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	author := &model.Author{}
+	if err := conn.Where("id = ?", id).First(author).Error; err != nil {
+		return nil, translateError(ctx, err, "author")
+	}
+
+	return author, nil
+}
+
+func (r *AuthorRepo) CreateAuthor(ctx context.Context, author *model.Author) (*model.Author, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	if err := conn.Create(author).Error; err != nil {
+		return nil, translateError(ctx, err, "author")
+	}
+
+	return author, nil
+}
+
+func (r *AuthorRepo) UpdateAuthor(ctx context.Context, author *model.Author) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Model(&model.Author{}).Where("id = ?", author.ID).Updates(map[string]interface{}{
+		"name": author.Name,
+	})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "author")
+	}
+
+	// Matched rows are affected, changed or not (ClientFoundRows), so no
+	// affected row means no matching row.
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("author not found", nil)
+	}
+
+	return nil
+}
+
+// DeleteAuthor deletes the author for good, unless books still refer to it,
+// those in the trash included: its name is free for a new author. The author
+// is locked first, so a book can't be linked to it between the check and the
+// delete.
+func (r *AuthorRepo) DeleteAuthor(ctx context.Context, id uint) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	return inTransaction(ctx, conn, "author", func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Set("gorm:query_option", "FOR UPDATE").
+			Where("id = ?", id).
+			First(&model.Author{}).Error; err != nil {
+			return translateError(ctx, err, "author")
+		}
+
+		var books int64
+		if err := tx.Table("book_authors").Where("author_id = ?", id).Count(&books).Error; err != nil {
+			return translateError(ctx, err, "book author")
+		}
+
+		if books > 0 {
+			return apperror.Conflict("author still has books", nil)
+		}
+
+		if err := tx.Unscoped().Where("id = ?", id).Delete(&model.Author{}).Error; err != nil {
+			return translateError(ctx, err, "author")
+		}
+
+		return nil
+	})
+}
+
+// ListAuthorBooks returns a page of the books of the author in id order and
+// their total count.
+func (r *AuthorRepo) ListAuthorBooks(ctx context.Context, id uint, query *model.PageQuery) (model.Books, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Model(&model.Book{}).
+		Joins("JOIN book_authors ON book_authors.book_id = books.id").
+		Where("book_authors.author_id = ?", id)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	books := make([]*model.Book, 0)
+	if err := db.Select("books.*").Order("books.id ASC").Offset(query.Offset).Limit(query.Limit).Find(&books).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	return books, total, nil
+}
+
+type AuthorRepoInterface interface {
+	ListAuthors(ctx context.Context, query *model.PageQuery) (model.Authors, int64, error)
+	ReadAuthor(ctx context.Context, id uint) (*model.Author, error)
+	CreateAuthor(ctx context.Context, author *model.Author) (*model.Author, error)
+	UpdateAuthor(ctx context.Context, author *model.Author) error
+	DeleteAuthor(ctx context.Context, id uint) error
+	ListAuthorBooks(ctx context.Context, id uint, query *model.PageQuery) (model.Books, int64, error)
+}
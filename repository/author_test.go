@@ -0,0 +1,223 @@
+package repository_test
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/apperror"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorRepo_ReadAuthor(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewAuthorRepo(db, time.Second)
+
+	query := "SELECT * FROM `authors`  WHERE `authors`.`deleted_at` IS NULL AND ((id = ?)) ORDER BY `authors`.`id` ASC LIMIT 1"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "J. R. R. Tolkien"))
+
+		author, err := repo.ReadAuthor(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "J. R. R. Tolkien", author.Name)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not found call", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		_, err := repo.ReadAuthor(context.Background(), 2)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestAuthorRepo_CreateAuthor(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewAuthorRepo(db, time.Second)
+
+	query := "INSERT INTO `authors` (`created_at`,`updated_at`,`deleted_at`,`name`) VALUES (?,?,?,?)"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(AnyTime{}, AnyTime{}, nil, "J. R. R. Tolkien").
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectCommit()
+
+		author, err := repo.CreateAuthor(context.Background(), &model.Author{Name: "J. R. R. Tolkien"})
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), author.ID)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Duplicate name call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(AnyTime{}, AnyTime{}, nil, "J. R. R. Tolkien").
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'J. R. R. Tolkien' for key 'uix_authors_name'"})
+		mock.ExpectRollback()
+
+		_, err := repo.CreateAuthor(context.Background(), &model.Author{Name: "J. R. R. Tolkien"})
+		assert.ErrorIs(t, err, apperror.ErrConflict)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestAuthorRepo_UpdateAuthor(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewAuthorRepo(db, time.Second)
+
+	query := "UPDATE `authors` SET `name` = ?, `updated_at` = ? WHERE `authors`.`deleted_at` IS NULL AND ((id = ?))"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs("Tolkien", AnyTime{}, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateAuthor(context.Background(), &model.Author{Model: gorm.Model{ID: 1}, Name: "Tolkien"})
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not found call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs("Tolkien", AnyTime{}, 2).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.UpdateAuthor(context.Background(), &model.Author{Model: gorm.Model{ID: 2}, Name: "Tolkien"})
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestAuthorRepo_DeleteAuthor(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewAuthorRepo(db, time.Second)
+
+	lockQuery := "SELECT * FROM `authors`  WHERE (id = ?) ORDER BY `authors`.`id` ASC LIMIT 1 FOR UPDATE"
+	countQuery := "SELECT count(*) FROM `book_authors`  WHERE (author_id = ?)"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Alan Donovan"))
+		mock.ExpectQuery(countQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+		mock.ExpectExec("DELETE FROM `authors`  WHERE (id = ?)").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.DeleteAuthor(context.Background(), 1)
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Author of books call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "Alan Donovan"))
+		mock.ExpectQuery(countQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+		mock.ExpectRollback()
+
+		err := repo.DeleteAuthor(context.Background(), 1)
+		assert.ErrorIs(t, err, apperror.ErrConflict)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not found call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(lockQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+		mock.ExpectRollback()
+
+		err := repo.DeleteAuthor(context.Background(), 1)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestAuthorRepo_ListAuthorBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewAuthorRepo(db, time.Second)
+
+	mock.ExpectQuery("SELECT count(*) FROM `books` JOIN book_authors ON book_authors.book_id = books.id WHERE `books`.`deleted_at` IS NULL AND ((book_authors.author_id = ?))").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(3))
+	mock.ExpectQuery("SELECT books.* FROM `books` JOIN book_authors ON book_authors.book_id = books.id WHERE `books`.`deleted_at` IS NULL AND ((book_authors.author_id = ?)) ORDER BY books.id ASC LIMIT 2 OFFSET 1").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(2, "title 2").AddRow(5, "title 5"))
+
+	books, total, err := repo.ListAuthorBooks(context.Background(), 1, &model.PageQuery{Limit: 2, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, books, 2)
+	assert.Equal(t, uint(5), books[1].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
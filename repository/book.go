@@ -2,71 +2,666 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	dbConn "myapp/adapter/gorm"
 	"myapp/model"
+	"myapp/util/apperror"
+	"strings"
+	"time"
 
 	"github.com/jinzhu/gorm"
 )
 
 type BookRepo struct {
-	repo *gorm.DB
+	repo         *gorm.DB
+	queryTimeout time.Duration
 }
 
-func NewBookRepo(conn *gorm.DB) *BookRepo {
+func NewBookRepo(conn *gorm.DB, queryTimeout time.Duration) *BookRepo {
 	return &BookRepo{
-		repo: conn,
+		repo:         conn,
+		queryTimeout: queryTimeout,
 	}
 }
 
-func (r *BookRepo) ListBooks(ctx context.Context) (model.Books, error) {
+func (r *BookRepo) ListBooks(ctx context.Context, query *model.BookQuery) (model.Books, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := filterBooks(conn.Model(&model.Book{}), query)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	backward := query.Cursor != nil && query.Cursor.Backward
+	if query.Cursor != nil {
+		cond, args, err := keysetCondition(query)
+		if err != nil {
+			return nil, 0, err
+		}
+		db = db.Where(cond, args...)
+	}
+
+	for _, s := range query.Sort {
+		db = db.Order(orderClause(s.Column, s.Desc != backward))
+	}
+	if !sortsByID(query) {
+		db = db.Order(orderClause("id", backward))
+	}
+
+	// One extra row tells the caller whether there is another page.
 	books := make([]*model.Book, 0)
-	if err := r.repo.Find(&books).Error; err != nil {
-		return nil, err
+	if err := db.Offset(query.Offset).Limit(query.Limit + 1).Find(&books).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	if backward {
+		for i, j := 0, len(books)-1; i < j; i, j = i+1, j-1 {
+			books[i], books[j] = books[j], books[i]
+		}
+	}
+
+	return books, total, nil
+}
+
+// eachBookBatchSize is the number of books EachBook reads per query.
+const eachBookBatchSize = 500
+
+// EachBook calls fn with every book in id order without holding them all in
+// memory. Books are read in batches, each query under its own timeout, so fn
+// may take as long as it needs; books written meanwhile may or may not be seen.
+func (r *BookRepo) EachBook(ctx context.Context, fn func(book *model.Book) error) error {
+	var lastID uint
+	for {
+		books, err := r.booksAfter(ctx, lastID, eachBookBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, b := range books {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+
+		if len(books) < eachBookBatchSize {
+			return nil
+		}
+		lastID = books[len(books)-1].ID
+	}
+}
+
+func (r *BookRepo) booksAfter(ctx context.Context, id uint, limit int) (model.Books, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	books := make([]*model.Book, 0, limit)
+	if err := conn.Where("id > ?", id).Order("id ASC").Limit(limit).Find(&books).Error; err != nil {
+		return nil, translateError(ctx, err, "book")
 	}
 
 	return books, nil
 }
 
+func filterBooks(db *gorm.DB, query *model.BookQuery) *gorm.DB {
+	if query.Author != "" {
+		db = db.Where("author = ?", query.Author)
+	}
+	if query.Title != "" {
+		db = db.Where("title LIKE ?", "%"+escapeLike(query.Title)+"%")
+	}
+	if query.Tag != "" {
+		db = db.Where("id IN (SELECT book_tags.book_id FROM book_tags JOIN tags ON tags.id = book_tags.tag_id WHERE tags.name = ?)", query.Tag)
+	}
+	if query.Genre != "" {
+		db = db.Where("id IN (SELECT book_genres.book_id FROM book_genres JOIN genres ON genres.id = book_genres.genre_id WHERE genres.name = ?)", query.Genre)
+	}
+	if query.PublishedFrom != nil {
+		db = db.Where("published_date >= ?", *query.PublishedFrom)
+	}
+	if query.PublishedTo != nil {
+		db = db.Where("published_date <= ?", *query.PublishedTo)
+	}
+
+	return db
+}
+
+// keysetCondition builds the WHERE clause selecting the rows after (or before) the cursor
+// for the query sort order, with the id column as the final tie-breaker.
+func keysetCondition(query *model.BookQuery) (string, []interface{}, error) {
+	type key struct {
+		column string
+		desc   bool
+		value  interface{}
+	}
+
+	keys := make([]key, 0, len(query.Sort)+1)
+	for i, s := range query.Sort {
+		v, err := query.CursorValue(i)
+		if err != nil {
+			return "", nil, err
+		}
+		keys = append(keys, key{column: s.Column, desc: s.Desc, value: v})
+	}
+	if !sortsByID(query) {
+		keys = append(keys, key{column: "id", value: query.Cursor.ID})
+	}
+
+	var (
+		ors  []string
+		args []interface{}
+	)
+	for i, k := range keys {
+		ands := make([]string, 0, i+1)
+		for _, prev := range keys[:i] {
+			ands = append(ands, prev.column+" = ?")
+			args = append(args, prev.value)
+		}
+
+		op := ">"
+		if k.desc != query.Cursor.Backward {
+			op = "<"
+		}
+		ands = append(ands, fmt.Sprintf("%s %s ?", k.column, op))
+		args = append(args, k.value)
+
+		ors = append(ors, "("+strings.Join(ands, " AND ")+")")
+	}
+
+	return strings.Join(ors, " OR "), args, nil
+}
+
+func sortsByID(query *model.BookQuery) bool {
+	for _, s := range query.Sort {
+		if s.Column == "id" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func orderClause(column string, desc bool) string {
+	if desc {
+		return column + " DESC"
+	}
+
+	return column + " ASC"
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+const bookMatchAgainst = "MATCH (title, author, description) AGAINST (? IN NATURAL LANGUAGE MODE)"
+
+func (r *BookRepo) SearchBooks(ctx context.Context, query *model.BookSearchQuery) (model.BookSearchHits, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Model(&model.Book{}).Where(bookMatchAgainst, query.Q)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	hits := make([]*model.BookSearchHit, 0)
+	if err := db.Select("*, "+bookMatchAgainst+" AS score", query.Q).
+		Order("score DESC").
+		Order("id ASC").
+		Offset(query.Offset).
+		Limit(query.Limit).
+		Scan(&hits).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	return hits, total, nil
+}
+
 func (r *BookRepo) ReadBook(ctx context.Context, id uint) (*model.Book, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
 	book := &model.Book{}
-	if err := r.repo.Where("id = ?", id).First(&book).Error; err != nil {
-		return nil, err
+	if err := conn.Where("id = ?", id).First(&book).Error; err != nil {
+		return nil, translateError(ctx, err, "book")
 	}
 
 	return book, nil
 }
 
-func (r *BookRepo) DeleteBook(ctx context.Context, id uint) error {
+// ReadBookByISBN reads the book with the normalized ISBN-13.
+func (r *BookRepo) ReadBookByISBN(ctx context.Context, isbn string) (*model.Book, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
 	book := &model.Book{}
-	if err := r.repo.Where("id = ?", id).Delete(&book).Error; err != nil {
-		return err
+	if err := conn.Where("isbn = ?", isbn).First(&book).Error; err != nil {
+		return nil, translateError(ctx, err, "book")
+	}
+
+	return book, nil
+}
+
+// TakenISBNs returns those of the normalized ISBN-13s which are the ISBN of a
+// book, soft-deleted ones included since the unique index spans them too.
+func (r *BookRepo) TakenISBNs(ctx context.Context, isbns []string) ([]string, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	taken := make([]string, 0)
+	if err := conn.Unscoped().Model(&model.Book{}).Where("isbn IN (?)", isbns).Pluck("isbn", &taken).Error; err != nil {
+		return nil, translateError(ctx, err, "book")
+	}
+
+	return taken, nil
+}
+
+// LockBook reads the book and locks it for update until the end of the
+// transaction. With trashed set it reads a soft-deleted book instead.
+func (r *BookRepo) LockBook(ctx context.Context, id uint, trashed bool) (*model.Book, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", id)
+	entity := "book"
+	if trashed {
+		db = db.Unscoped().Where("deleted_at IS NOT NULL")
+		entity = "deleted book"
+	}
+
+	book := &model.Book{}
+	if err := db.First(book).Error; err != nil {
+		return nil, translateError(ctx, err, entity)
+	}
+
+	return book, nil
+}
+
+// DeleteBook deletes the book; when version isn't 0 only if the book is still
+// at that version.
+func (r *BookRepo) DeleteBook(ctx context.Context, id uint, version uint) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Where("id = ?", id)
+	if version != 0 {
+		db = db.Where("version = ?", version)
+	}
+
+	book := &model.Book{}
+	res := db.Delete(&book)
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	if res.RowsAffected == 0 {
+		if version == 0 {
+			return apperror.NotFound("book not found", nil)
+		}
+		return r.explainNoMatch(ctx, conn, id)
 	}
 
 	return nil
 }
 
+// ListDeletedBooks returns a page of the soft-deleted books, most recently
+// deleted first, and their total count.
+func (r *BookRepo) ListDeletedBooks(ctx context.Context, query *model.PageQuery) (model.Books, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Unscoped().Model(&model.Book{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	books := make([]*model.Book, 0)
+	if err := db.Order("deleted_at DESC").Order("id DESC").Offset(query.Offset).Limit(query.Limit).Find(&books).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book")
+	}
+
+	return books, total, nil
+}
+
+// RestoreBook undeletes a soft-deleted book and bumps its version.
+func (r *BookRepo) RestoreBook(ctx context.Context, id uint) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Unscoped().Model(&model.Book{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"version":    gorm.Expr("version + 1"),
+		})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("deleted book not found", nil)
+	}
+
+	return nil
+}
+
+// LockDeletedBooks returns, locked for update, up to limit books soft-deleted
+// before the given time.
+func (r *BookRepo) LockDeletedBooks(ctx context.Context, before time.Time, limit int) (model.Books, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	books := make([]*model.Book, 0)
+	if err := conn.Unscoped().Set("gorm:query_option", "FOR UPDATE").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("id ASC").
+		Limit(limit).
+		Find(&books).Error; err != nil {
+		return nil, translateError(ctx, err, "book")
+	}
+
+	return books, nil
+}
+
+// PurgeBooks permanently deletes the soft-deleted books among ids and returns
+// how many were removed.
+func (r *BookRepo) PurgeBooks(ctx context.Context, ids []uint) (int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Unscoped().Where("id IN (?) AND deleted_at IS NOT NULL", ids).Delete(&model.Book{})
+	if err := res.Error; err != nil {
+		return 0, translateError(ctx, err, "book")
+	}
+
+	return res.RowsAffected, nil
+}
+
 func (r *BookRepo) CreateBook(ctx context.Context, book *model.Book) (*model.Book, error) {
-	if err := r.repo.Create(book).Error; err != nil {
-		return nil, err
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	book.Version = 1
+	if err := conn.Create(book).Error; err != nil {
+		return nil, translateError(ctx, err, "book")
 	}
 
 	return book, nil
 }
 
-func (r *BookRepo) UpdateBook(ctx context.Context, book *model.Book) error {
-	if err := r.repo.Model(&model.Book{}).Select("updated_at", "title", "author", "published_date", "image_url", "description").Where("id = ?", book.ID).Updates(book).Error; err != nil {
+// insertBatchSize bounds the rows of a single INSERT so the statement stays
+// well under max_allowed_packet.
+const insertBatchSize = 500
+
+// CreateBooks inserts the books with multi-row INSERTs of up to
+// insertBatchSize rows, each with its own query timeout, and sets their IDs.
+// Unless it runs in a transaction, the books inserted before a failure are
+// kept: those are the ones with a non-zero ID.
+func (r *BookRepo) CreateBooks(ctx context.Context, books []*model.Book) error {
+	// TIMESTAMP columns keep whole seconds: the IDs are read back by creation time.
+	now := gorm.NowFunc().Truncate(time.Second)
+	for start := 0; start < len(books); start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > len(books) {
+			end = len(books)
+		}
+
+		if err := r.createChunk(ctx, books[start:end], now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// createChunk inserts the books with a single INSERT and reads their IDs back.
+func (r *BookRepo) createChunk(ctx context.Context, chunk []*model.Book, now time.Time) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	rows := make([]string, 0, len(chunk))
+	args := make([]interface{}, 0, len(chunk)*10)
+	for _, b := range chunk {
+		rows = append(rows, "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, now, now, b.Title, b.Author, b.PublishedDate, b.ImageUrl, b.Description, b.ISBN, b.CreatedBy, 1)
+	}
+
+	res, err := dbConn.Exec(conn, "books", "INSERT INTO `books` (`created_at`, `updated_at`, `title`, `author`, `published_date`, `image_url`, `description`, `isbn`, `created_by`, `version`) VALUES "+strings.Join(rows, ", "), args...)
+	if err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	first, err := res.LastInsertId()
+	if err != nil {
 		return err
 	}
 
-	// Now - r.repo.Model(&model.Book{}).Select("updated_at", "title", "author", "published_date", "image_url", "description").Where("id = ?", book.ID).Updates(book).Error
-	// WAS - r.repo.First(&model.Book{}, book.ID).Update(book).Error
+	// The IDs of the rows of a multi-row INSERT are increasing but not always
+	// consecutive, e.g. with innodb_autoinc_lock_mode=2, where concurrent
+	// inserts interleave: the rows of the chunk are the first ones from the
+	// reported ID on which match the books in order.
+	created, err := conn.Raw("SELECT `id`, `title`, `author`, `created_by` FROM `books` WHERE `id` >= ? AND `created_at` = ? ORDER BY `id`", first, now).Rows()
+	if err != nil {
+		return translateError(ctx, err, "book")
+	}
+	defer created.Close()
+
+	ids := make([]uint, 0, len(chunk))
+	for created.Next() && len(ids) < len(chunk) {
+		var (
+			id                       uint
+			title, author, createdBy string
+		)
+		if err := created.Scan(&id, &title, &author, &createdBy); err != nil {
+			return translateError(ctx, err, "book")
+		}
+
+		b := chunk[len(ids)]
+		if title == b.Title && author == b.Author && createdBy == b.CreatedBy {
+			ids = append(ids, id)
+		}
+	}
+	if err := created.Err(); err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	if len(ids) != len(chunk) {
+		return fmt.Errorf("read back %d of the IDs of %d created books", len(ids), len(chunk))
+	}
+
+	for i, b := range chunk {
+		b.ID, b.CreatedAt, b.UpdatedAt, b.Version = ids[i], now, now, 1
+	}
+
+	return nil
+}
+
+// SetBookCover records the media type and the blob key prefix of the
+// uploaded cover of the book, empty ones when the cover is removed, and bumps
+// the version of the book.
+func (r *BookRepo) SetBookCover(ctx context.Context, id uint, coverType, coverKey string) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Model(&model.Book{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"cover_type": coverType,
+		"cover_key":  coverKey,
+		"version":    gorm.Expr("version + 1"),
+	})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("book not found", nil)
+	}
 
 	return nil
 }
 
+// Transaction calls fn with a repository whose writes are committed together
+// when fn returns nil, and rolled back otherwise. The query timeout applies to
+// each call made on that repository, and a call which times out fails the
+// transaction; the transaction as a whole is bounded by ctx.
+// Called on a repository which is already in a transaction, fn joins it.
+func (r *BookRepo) Transaction(ctx context.Context, fn func(repo BookRepoInterface) error) error {
+	return inTransaction(ctx, r.repo, "book", func(tx *gorm.DB) error {
+		return fn(&BookRepo{repo: tx, queryTimeout: r.queryTimeout})
+	})
+}
+
+// UpdateBook saves the book and bumps its version. When book.Version isn't 0
+// the write is conditional on the book still being at that version, and
+// book.Version is set to the new version on success.
+func (r *BookRepo) UpdateBook(ctx context.Context, book *model.Book) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Model(&model.Book{}).Where("id = ?", book.ID)
+	if book.Version != 0 {
+		db = db.Where("version = ?", book.Version)
+	}
+
+	res := db.Updates(map[string]interface{}{
+		"title":          book.Title,
+		"author":         book.Author,
+		"published_date": book.PublishedDate,
+		"image_url":      book.ImageUrl,
+		"description":    book.Description,
+		"isbn":           book.ISBN,
+		"version":        gorm.Expr("version + 1"),
+	})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	// The version always changes, so no affected row means no matching row.
+	if res.RowsAffected == 0 {
+		return r.explainNoMatch(ctx, conn, book.ID)
+	}
+
+	if book.Version != 0 {
+		book.Version++
+	}
+
+	return nil
+}
+
+// BookAuthorIDs returns the authors of the book in order.
+func (r *BookRepo) BookAuthorIDs(ctx context.Context, bookID uint) ([]uint, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	ids := make([]uint, 0)
+	if err := conn.Table("book_authors").Where("book_id = ?", bookID).Order("position ASC").Pluck("author_id", &ids).Error; err != nil {
+		return nil, translateError(ctx, err, "book author")
+	}
+
+	return ids, nil
+}
+
+// EnsureAuthor returns the ID of the author named name, who is created unless
+// there is one already.
+func (r *BookRepo) EnsureAuthor(ctx context.Context, name string) (uint, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	// LAST_INSERT_ID(id) reports the ID of the existing author, if any.
+	now := gorm.NowFunc()
+	res, err := dbConn.Exec(conn, "authors", "INSERT INTO `authors` (`name`, `created_at`, `updated_at`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `id` = LAST_INSERT_ID(`id`)", name, now, now)
+	if err != nil {
+		return 0, translateError(ctx, err, "author")
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return uint(id), nil
+}
+
+// SetBookAuthors replaces the authors of the book by authorIDs, in order. It
+// fails with a validation error when one of them doesn't exist.
+func (r *BookRepo) SetBookAuthors(ctx context.Context, bookID uint, authorIDs []uint) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	if len(authorIDs) > 0 {
+		var found int64
+		if err := conn.Model(&model.Author{}).Where("id IN (?)", authorIDs).Count(&found).Error; err != nil {
+			return translateError(ctx, err, "author")
+		}
+
+		if found != int64(len(authorIDs)) {
+			return apperror.Validation("unknown author", nil, apperror.FieldError{Name: "author_ids", Reason: "must reference existing authors"})
+		}
+	}
+
+	if _, err := dbConn.Exec(conn, "book_authors", "DELETE FROM `book_authors` WHERE `book_id` = ?", bookID); err != nil {
+		return translateError(ctx, err, "book author")
+	}
+
+	if len(authorIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]string, 0, len(authorIDs))
+	args := make([]interface{}, 0, len(authorIDs)*3)
+	for i, id := range authorIDs {
+		rows = append(rows, "(?, ?, ?)")
+		args = append(args, bookID, id, i)
+	}
+
+	if _, err := dbConn.Exec(conn, "book_authors", "INSERT INTO `book_authors` (`book_id`, `author_id`, `position`) VALUES "+strings.Join(rows, ", "), args...); err != nil {
+		return translateError(ctx, err, "book author")
+	}
+
+	return nil
+}
+
+// explainNoMatch tells why a write on the book matched no row: either the book
+// doesn't exist or it's no longer at the expected version.
+func (r *BookRepo) explainNoMatch(ctx context.Context, conn *gorm.DB, id uint) error {
+	var count int64
+	if err := conn.Model(&model.Book{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	if count == 0 {
+		return apperror.NotFound("book not found", nil)
+	}
+
+	return apperror.PreconditionFailed("book has been modified", nil)
+}
+
 type BookRepoInterface interface {
-	ListBooks(ctx context.Context) (model.Books, error)
+	ListBooks(ctx context.Context, query *model.BookQuery) (model.Books, int64, error)
+	SearchBooks(ctx context.Context, query *model.BookSearchQuery) (model.BookSearchHits, int64, error)
 	ReadBook(ctx context.Context, id uint) (*model.Book, error)
-	DeleteBook(ctx context.Context, id uint) error
+	ReadBookByISBN(ctx context.Context, isbn string) (*model.Book, error)
+	TakenISBNs(ctx context.Context, isbns []string) ([]string, error)
+	DeleteBook(ctx context.Context, id uint, version uint) error
 	CreateBook(ctx context.Context, book *model.Book) (*model.Book, error)
 	UpdateBook(ctx context.Context, book *model.Book) error
+	SetBookCover(ctx context.Context, id uint, coverType, coverKey string) error
+	CreateBooks(ctx context.Context, books []*model.Book) error
+	Transaction(ctx context.Context, fn func(repo BookRepoInterface) error) error
+	EachBook(ctx context.Context, fn func(book *model.Book) error) error
+	ListDeletedBooks(ctx context.Context, query *model.PageQuery) (model.Books, int64, error)
+	RestoreBook(ctx context.Context, id uint) error
+	LockBook(ctx context.Context, id uint, trashed bool) (*model.Book, error)
+	LockDeletedBooks(ctx context.Context, before time.Time, limit int) (model.Books, error)
+	PurgeBooks(ctx context.Context, ids []uint) (int64, error)
+	CreateAudits(ctx context.Context, audits []*model.BookAudit) error
+	ListAudits(ctx context.Context, bookID uint, query *model.PageQuery) ([]*model.BookAudit, int64, error)
+	BookAuthorIDs(ctx context.Context, bookID uint) ([]uint, error)
+	EnsureAuthor(ctx context.Context, name string) (uint, error)
+	SetBookAuthors(ctx context.Context, bookID uint, authorIDs []uint) error
 }
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	dbConn "myapp/adapter/gorm"
+	"myapp/model"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// bookAuditRow is a row of the book_audit table, its snapshots stored as JSON.
+type bookAuditRow struct {
+	ID             uint64
+	BookID         uint
+	Action         string
+	BeforeSnapshot sql.NullString
+	AfterSnapshot  sql.NullString
+	Actor          string
+	RequestID      string
+	CreatedAt      time.Time
+}
+
+func (bookAuditRow) TableName() string {
+	return "book_audit"
+}
+
+func (row *bookAuditRow) toModel() (*model.BookAudit, error) {
+	audit := &model.BookAudit{
+		ID:        row.ID,
+		BookID:    row.BookID,
+		Action:    model.BookAuditAction(row.Action),
+		Actor:     row.Actor,
+		RequestID: row.RequestID,
+		CreatedAt: row.CreatedAt,
+	}
+
+	for _, s := range []struct {
+		raw sql.NullString
+		dst **model.BookSnapshot
+	}{
+		{row.BeforeSnapshot, &audit.Before},
+		{row.AfterSnapshot, &audit.After},
+	} {
+		if !s.raw.Valid {
+			continue
+		}
+		*s.dst = &model.BookSnapshot{}
+		if err := json.Unmarshal([]byte(s.raw.String), *s.dst); err != nil {
+			return nil, err
+		}
+	}
+
+	return audit, nil
+}
+
+func snapshotValue(s *model.BookSnapshot) (interface{}, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// CreateAudits appends the entries to the audit trail of the books, with
+// multi-row INSERTs. Entries without a creation time are stamped now.
+func (r *BookRepo) CreateAudits(ctx context.Context, audits []*model.BookAudit) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	now := gorm.NowFunc()
+	for start := 0; start < len(audits); start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > len(audits) {
+			end = len(audits)
+		}
+		chunk := audits[start:end]
+
+		rows := make([]string, 0, len(chunk))
+		args := make([]interface{}, 0, len(chunk)*7)
+		for _, a := range chunk {
+			if a.CreatedAt.IsZero() {
+				a.CreatedAt = now
+			}
+
+			before, err := snapshotValue(a.Before)
+			if err != nil {
+				return err
+			}
+			after, err := snapshotValue(a.After)
+			if err != nil {
+				return err
+			}
+
+			rows = append(rows, "(?, ?, ?, ?, ?, ?, ?)")
+			args = append(args, a.BookID, string(a.Action), before, after, a.Actor, a.RequestID, a.CreatedAt)
+		}
+
+		if _, err := dbConn.Exec(conn, "book_audit", "INSERT INTO `book_audit` (`book_id`, `action`, `before_snapshot`, `after_snapshot`, `actor`, `request_id`, `created_at`) VALUES "+strings.Join(rows, ", "), args...); err != nil {
+			return translateError(ctx, err, "book audit")
+		}
+	}
+
+	return nil
+}
+
+// ListAudits returns a page of the audit trail of a book, most recent first,
+// and its total count.
+func (r *BookRepo) ListAudits(ctx context.Context, bookID uint, query *model.PageQuery) ([]*model.BookAudit, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Model(&bookAuditRow{}).Where("book_id = ?", bookID)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book audit")
+	}
+
+	rows := make([]*bookAuditRow, 0)
+	if err := db.Order("id DESC").Offset(query.Offset).Limit(query.Limit).Find(&rows).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "book audit")
+	}
+
+	audits := make([]*model.BookAudit, 0, len(rows))
+	for _, row := range rows {
+		audit, err := row.toModel()
+		if err != nil {
+			return nil, 0, err
+		}
+		audits = append(audits, audit)
+	}
+
+	return audits, total, nil
+}
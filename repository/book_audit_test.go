@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"myapp/model"
+	"myapp/repository"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookRepo_CreateAudits(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "INSERT INTO `book_audit` (`book_id`, `action`, `before_snapshot`, `after_snapshot`, `actor`, `request_id`, `created_at`) VALUES (?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?)"
+
+	audits := func() []*model.BookAudit {
+		return []*model.BookAudit{
+			{BookID: 1, Action: model.BookAuditCreate, After: &model.BookSnapshot{Title: "title", PublishedDate: "2006-01-02", Version: 1}, Actor: "anonymous", RequestID: "req-1"},
+			{BookID: 2, Action: model.BookAuditDelete, Before: &model.BookSnapshot{Title: "other", PublishedDate: "2007-01-02", Version: 3}, Actor: "anonymous"},
+		}
+	}
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectExec(query).WithArgs(
+			1, "create", nil, `{"title":"title","author":"","published_date":"2006-01-02","image_url":"","description":"","version":1}`, "anonymous", "req-1", AnyTime{},
+			2, "delete", `{"title":"other","author":"","published_date":"2007-01-02","image_url":"","description":"","version":3}`, nil, "anonymous", "", AnyTime{},
+		).WillReturnResult(sqlmock.NewResult(1, 2))
+
+		entries := audits()
+		err := repo.CreateAudits(context.Background(), entries)
+		assert.NoError(t, err)
+		assert.False(t, entries[0].CreatedAt.IsZero())
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		mock.ExpectExec(query).WillReturnError(errors.New("error"))
+
+		err := repo.CreateAudits(context.Background(), audits())
+		assert.Error(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_ListAudits(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	countQuery := "SELECT count(*) FROM `book_audit`  WHERE (book_id = ?)"
+	query := "SELECT * FROM `book_audit`  WHERE (book_id = ?) ORDER BY id DESC LIMIT 20 OFFSET 0"
+
+	t.Run("Success call", func(t *testing.T) {
+		createdAt := time.Now()
+
+		mock.ExpectQuery(countQuery).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+		mock.ExpectQuery(query).WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "book_id", "action", "before_snapshot", "after_snapshot", "actor", "request_id", "created_at"}).
+				AddRow(7, 1, "update", `{"title":"title","version":1}`, `{"title":"new title","version":2}`, "anonymous", "req-2", createdAt).
+				AddRow(3, 1, "create", nil, `{"title":"title","version":1}`, "anonymous", "req-1", createdAt))
+
+		audits, total, err := repo.ListAudits(context.Background(), 1, &model.PageQuery{Limit: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		assert.Len(t, audits, 2)
+		assert.Equal(t, &model.BookAudit{
+			ID:        7,
+			BookID:    1,
+			Action:    model.BookAuditUpdate,
+			Before:    &model.BookSnapshot{Title: "title", Version: 1},
+			After:     &model.BookSnapshot{Title: "new title", Version: 2},
+			Actor:     "anonymous",
+			RequestID: "req-2",
+			CreatedAt: createdAt,
+		}, audits[0])
+		assert.Nil(t, audits[1].Before)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).WithArgs(1).WillReturnError(errors.New("error"))
+
+		_, _, err := repo.ListAudits(context.Background(), 1, &model.PageQuery{Limit: 20})
+		assert.Error(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
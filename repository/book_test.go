@@ -7,12 +7,21 @@ import (
 	"log"
 	"myapp/model"
 	"myapp/repository"
+	"myapp/util/apperror"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/jinzhu/gorm"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
 func NewMock() (*gorm.DB, sqlmock.Sqlmock) {
@@ -48,7 +57,7 @@ func TestBookRepo_ReadBook(t *testing.T) {
 
 	defer db.Close()
 
-	repo := repository.NewBookRepo(db)
+	repo := repository.NewBookRepo(db, time.Second)
 
 	query := "SELECT * FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((id = ?)) ORDER BY `books`.`id` ASC LIMIT 1"
 
@@ -78,6 +87,20 @@ func TestBookRepo_ReadBook(t *testing.T) {
 		}
 	})
 
+	t.Run("Not found call", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, err := repo.ReadBook(context.Background(), book.ID)
+		assert.Empty(t, resp)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
 	t.Run("Error call", func(t *testing.T) {
 		mock.ExpectQuery(query).
 			WillReturnError(errors.New("error"))
@@ -90,18 +113,102 @@ func TestBookRepo_ReadBook(t *testing.T) {
 			t.Errorf("there were unfulfilled expectations: %s", err)
 		}
 	})
+
+	t.Run("Canceled call", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		resp, err := repo.ReadBook(ctx, book.ID)
+		assert.Empty(t, resp)
+		assert.ErrorIs(t, err, apperror.ErrCanceled)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Timeout call", func(t *testing.T) {
+		repo := repository.NewBookRepo(db, 10*time.Millisecond)
+
+		mock.ExpectQuery(query).
+			WithArgs(book.ID).
+			WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, err := repo.ReadBook(context.Background(), book.ID)
+		assert.Empty(t, resp)
+		assert.ErrorIs(t, err, apperror.ErrTimeout)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
 }
 
+func TestBookRepo_ReadBookByISBN(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "SELECT * FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((isbn = ?)) ORDER BY `books`.`id` ASC LIMIT 1"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("9780306406157").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "isbn"}).AddRow(book.ID, book.Title, "9780306406157"))
+
+		resp, err := repo.ReadBookByISBN(context.Background(), "9780306406157")
+		assert.NoError(t, err)
+		assert.Equal(t, book.ID, resp.ID)
+		if assert.NotNil(t, resp.ISBN) {
+			assert.Equal(t, "9780306406157", *resp.ISBN)
+		}
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not found call", func(t *testing.T) {
+		mock.ExpectQuery(query).
+			WithArgs("9780306406157").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, err := repo.ReadBookByISBN(context.Background(), "9780306406157")
+		assert.Empty(t, resp)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
 func TestBookRepo_ListBook(t *testing.T) {
 	db, mock := NewMock()
 
 	defer db.Close()
 
-	repo := repository.NewBookRepo(db)
+	repo := repository.NewBookRepo(db, time.Second)
+
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := &model.BookQuery{
+		Limit:         10,
+		Author:        "author",
+		Title:         "tit_le",
+		PublishedFrom: &from,
+		Sort:          []model.BookSort{{Column: "title"}, {Column: "published_date", Desc: true}},
+	}
 
-	query := "SELECT * FROM `books` WHERE `books`.`deleted_at` IS NULL"
+	countQuery := "SELECT count(*) FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((author = ?) AND (title LIKE ?) AND (published_date >= ?))"
+	selectQuery := "SELECT * FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((author = ?) AND (title LIKE ?) AND (published_date >= ?)) ORDER BY title ASC,published_date DESC,id ASC LIMIT 11 OFFSET 0"
 
 	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WithArgs("author", `%tit\_le%`, from).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+
 		rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at", "deleted_at", "title", "author", "published_date", "image_url", "description"}).
 			AddRow(
 				book.ID,
@@ -114,12 +221,56 @@ func TestBookRepo_ListBook(t *testing.T) {
 				book.ImageUrl,
 				book.Description)
 
-		mock.ExpectQuery(query).
+		mock.ExpectQuery(selectQuery).
+			WithArgs("author", `%tit\_le%`, from).
 			WillReturnRows(rows)
 
-		resp, err := repo.ListBooks(context.Background())
+		resp, total, err := repo.ListBooks(context.Background(), query)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, resp)
+		assert.Equal(t, int64(1), total)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Cursor call", func(t *testing.T) {
+		cursorQuery := &model.BookQuery{
+			Limit:  10,
+			Sort:   []model.BookSort{{Column: "title", Desc: true}},
+			Cursor: &model.BookCursor{Values: []string{"title"}, ID: 5},
+		}
+
+		mock.ExpectQuery("SELECT count(*) FROM `books` WHERE `books`.`deleted_at` IS NULL").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		mock.ExpectQuery("SELECT * FROM `books` WHERE `books`.`deleted_at` IS NULL AND (((title < ?) OR (title = ? AND id > ?))) ORDER BY title DESC,id ASC LIMIT 11 OFFSET 0").
+			WithArgs("title", "title", uint(5)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, _, err := repo.ListBooks(context.Background(), cursorQuery)
+		assert.NoError(t, err)
+		assert.Empty(t, resp)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Label call", func(t *testing.T) {
+		labelQuery := &model.BookQuery{Limit: 10, Tag: "classic", Genre: "fantasy"}
+		where := "WHERE `books`.`deleted_at` IS NULL AND ((id IN (SELECT book_tags.book_id FROM book_tags JOIN tags ON tags.id = book_tags.tag_id WHERE tags.name = ?)) AND (id IN (SELECT book_genres.book_id FROM book_genres JOIN genres ON genres.id = book_genres.genre_id WHERE genres.name = ?)))"
+
+		mock.ExpectQuery("SELECT count(*) FROM `books` "+where).
+			WithArgs("classic", "fantasy").
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+		mock.ExpectQuery("SELECT * FROM `books` "+where+" ORDER BY id ASC LIMIT 11 OFFSET 0").
+			WithArgs("classic", "fantasy").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		resp, _, err := repo.ListBooks(context.Background(), labelQuery)
+		assert.NoError(t, err)
+		assert.Empty(t, resp)
 
 		if err := mock.ExpectationsWereMet(); err != nil {
 			t.Errorf("there were unfulfilled expectations: %s", err)
@@ -127,10 +278,60 @@ func TestBookRepo_ListBook(t *testing.T) {
 	})
 
 	t.Run("Error call", func(t *testing.T) {
-		mock.ExpectQuery(query).
+		mock.ExpectQuery(countQuery).
+			WillReturnError(errors.New("error"))
+
+		resp, _, err := repo.ListBooks(context.Background(), query)
+		assert.Empty(t, resp)
+		assert.Error(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_SearchBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := &model.BookSearchQuery{Q: "hobbit", Limit: 10}
+
+	countQuery := "SELECT count(*) FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((MATCH (title, author, description) AGAINST (? IN NATURAL LANGUAGE MODE)))"
+	selectQuery := "SELECT *, MATCH (title, author, description) AGAINST (? IN NATURAL LANGUAGE MODE) AS score FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((MATCH (title, author, description) AGAINST (? IN NATURAL LANGUAGE MODE))) ORDER BY score DESC,id ASC LIMIT 10 OFFSET 0"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WithArgs(query.Q).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+
+		rows := sqlmock.NewRows([]string{"id", "title", "author", "description", "score"}).
+			AddRow(book.ID, book.Title, book.Author, book.Description, 1.5)
+
+		mock.ExpectQuery(selectQuery).
+			WithArgs(query.Q, query.Q).
+			WillReturnRows(rows)
+
+		resp, total, err := repo.SearchBooks(context.Background(), query)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, resp, 1)
+		assert.Equal(t, book.ID, resp[0].ID)
+		assert.Equal(t, 1.5, resp[0].Score)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
 			WillReturnError(errors.New("error"))
 
-		resp, err := repo.ListBooks(context.Background())
+		resp, _, err := repo.SearchBooks(context.Background(), query)
 		assert.Empty(t, resp)
 		assert.Error(t, err)
 
@@ -145,7 +346,7 @@ func TestBookRepo_DeleteBook(t *testing.T) {
 
 	defer db.Close()
 
-	repo := repository.NewBookRepo(db)
+	repo := repository.NewBookRepo(db, time.Second)
 
 	query := "UPDATE `books` SET `deleted_at`=? WHERE `books`.`deleted_at` IS NULL AND ((id = ?))"
 
@@ -158,7 +359,7 @@ func TestBookRepo_DeleteBook(t *testing.T) {
 			).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := repo.DeleteBook(context.Background(), book.ID)
+		err := repo.DeleteBook(context.Background(), book.ID, 0)
 		assert.NoError(t, err)
 
 		if err := mock.ExpectationsWereMet(); err != nil {
@@ -166,6 +367,44 @@ func TestBookRepo_DeleteBook(t *testing.T) {
 		}
 	})
 
+	t.Run("Stale version call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE `books` SET `deleted_at`=? WHERE `books`.`deleted_at` IS NULL AND ((id = ?) AND (version = ?))").
+			WithArgs(
+				AnyTime{},
+				book.ID,
+				2,
+			).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectQuery("SELECT count(*) FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((id = ?))").
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+
+		err := repo.DeleteBook(context.Background(), book.ID, 2)
+		assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not found call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(
+				AnyTime{},
+				book.ID,
+			).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.DeleteBook(context.Background(), book.ID, 0)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
 	t.Run("Error call", func(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec(query).
@@ -175,7 +414,7 @@ func TestBookRepo_DeleteBook(t *testing.T) {
 			).WillReturnError(errors.New("error"))
 		mock.ExpectRollback()
 
-		err := repo.DeleteBook(context.Background(), book.ID)
+		err := repo.DeleteBook(context.Background(), book.ID, 0)
 		assert.Error(t, err)
 
 		if err := mock.ExpectationsWereMet(); err != nil {
@@ -196,7 +435,7 @@ func TestBookRepo_CreateBook(t *testing.T) {
 		log.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
 	}
 
-	repo := repository.NewBookRepo(gormDB)
+	repo := repository.NewBookRepo(gormDB, time.Second)
 
 	t.Run("Success call", func(t *testing.T) {
 		mock.ExpectBegin()
@@ -210,6 +449,11 @@ func TestBookRepo_CreateBook(t *testing.T) {
 			book.PublishedDate,
 			book.ImageUrl,
 			book.Description,
+			book.ISBN,
+			book.CoverType,
+			book.CoverKey,
+			book.CreatedBy,
+			1,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
@@ -222,6 +466,21 @@ func TestBookRepo_CreateBook(t *testing.T) {
 		}
 	})
 
+	t.Run("Duplicate call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO `books`").
+			WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+		mock.ExpectRollback()
+
+		resp, err := repo.CreateBook(context.Background(), book)
+		assert.Empty(t, resp)
+		assert.ErrorIs(t, err, apperror.ErrConflict)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
 	t.Run("Error call", func(t *testing.T) {
 		mock.ExpectBegin()
 		mock.ExpectExec("INSERT INTO `books`").WithArgs(
@@ -234,6 +493,11 @@ func TestBookRepo_CreateBook(t *testing.T) {
 			book.PublishedDate,
 			book.ImageUrl,
 			book.Description,
+			book.ISBN,
+			book.CoverType,
+			book.CoverKey,
+			book.CreatedBy,
+			1,
 		).WillReturnError(errors.New("error"))
 		mock.ExpectRollback()
 
@@ -260,25 +524,93 @@ func TestBookRepo_UpdateBook(t *testing.T) {
 
 	defer db.Close()
 
-	repo := repository.NewBookRepo(db)
+	repo := repository.NewBookRepo(db, time.Second)
 
-	query := "UPDATE `books` SET `author` = ?, `description` = ?, `image_url` = ?, `published_date` = ?, `title` = ?, `updated_at` = ? WHERE `books`.`deleted_at` IS NULL AND ((id = ?))"
+	query := "UPDATE `books` SET `author` = ?, `description` = ?, `image_url` = ?, `isbn` = ?, `published_date` = ?, `title` = ?, `updated_at` = ?, `version` = version + 1 WHERE `books`.`deleted_at` IS NULL AND ((id = ?))"
+	conditionalQuery := "UPDATE `books` SET `author` = ?, `description` = ?, `image_url` = ?, `isbn` = ?, `published_date` = ?, `title` = ?, `updated_at` = ?, `version` = version + 1 WHERE `books`.`deleted_at` IS NULL AND ((id = ?) AND (version = ?))"
+	countQuery := "SELECT count(*) FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((id = ?))"
 
 	t.Run("Success call", func(t *testing.T) {
+		b := *book
+		b.Version = 0
+
 		mock.ExpectBegin()
 		mock.ExpectExec(query).WithArgs(
-			book.Author,
-			book.Description,
-			book.ImageUrl,
-			book.PublishedDate,
-			book.Title,
+			b.Author,
+			b.Description,
+			b.ImageUrl,
+			b.ISBN,
+			b.PublishedDate,
+			b.Title,
 			AnyTime{},
-			book.ID,
+			b.ID,
+		).WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.UpdateBook(context.Background(), &b)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(0), b.Version)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Conditional call", func(t *testing.T) {
+		b := *book
+		b.Version = 3
+
+		mock.ExpectBegin()
+		mock.ExpectExec(conditionalQuery).WithArgs(
+			b.Author,
+			b.Description,
+			b.ImageUrl,
+			b.ISBN,
+			b.PublishedDate,
+			b.Title,
+			AnyTime{},
+			b.ID,
+			3,
 		).WillReturnResult(sqlmock.NewResult(1, 1))
 		mock.ExpectCommit()
 
-		err := repo.UpdateBook(context.Background(), book)
+		err := repo.UpdateBook(context.Background(), &b)
 		assert.NoError(t, err)
+		assert.Equal(t, uint(4), b.Version)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Stale version call", func(t *testing.T) {
+		b := *book
+		b.Version = 3
+
+		mock.ExpectBegin()
+		mock.ExpectExec(conditionalQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectQuery(countQuery).WithArgs(b.ID).WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+
+		err := repo.UpdateBook(context.Background(), &b)
+		assert.ErrorIs(t, err, apperror.ErrPreconditionFailed)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not found call", func(t *testing.T) {
+		b := *book
+		b.Version = 0
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+		mock.ExpectQuery(countQuery).WithArgs(b.ID).WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+
+		err := repo.UpdateBook(context.Background(), &b)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
 
 		if err := mock.ExpectationsWereMet(); err != nil {
 			t.Errorf("there were unfulfilled expectations: %s", err)
@@ -286,19 +618,23 @@ func TestBookRepo_UpdateBook(t *testing.T) {
 	})
 
 	t.Run("Error call", func(t *testing.T) {
+		b := *book
+		b.Version = 0
+
 		mock.ExpectBegin()
 		mock.ExpectExec(query).WithArgs(
-			book.Author,
-			book.Description,
-			book.ImageUrl,
-			book.PublishedDate,
-			book.Title,
+			b.Author,
+			b.Description,
+			b.ImageUrl,
+			b.ISBN,
+			b.PublishedDate,
+			b.Title,
 			AnyTime{},
-			book.ID,
+			b.ID,
 		).WillReturnError(errors.New("error"))
 		mock.ExpectRollback()
 
-		err := repo.UpdateBook(context.Background(), book)
+		err := repo.UpdateBook(context.Background(), &b)
 		assert.Error(t, err)
 
 		if err := mock.ExpectationsWereMet(); err != nil {
@@ -306,3 +642,644 @@ func TestBookRepo_UpdateBook(t *testing.T) {
 		}
 	})
 }
+
+func TestBookRepo_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "SELECT * FROM `books` WHERE `books`.`deleted_at` IS NULL AND ((id = ?)) ORDER BY `books`.`id` ASC LIMIT 1"
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "parent")
+
+	mock.ExpectQuery(query).
+		WithArgs(book.ID).
+		WillReturnError(errors.New("error"))
+
+	_, err := repo.ReadBook(ctx, book.ID)
+	assert.Error(t, err)
+	parent.End()
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 2) {
+		span := spans[0]
+		assert.Equal(t, "gorm.Query", span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, codes.Error, span.Status().Code)
+		attrs := map[attribute.Key]string{}
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value.Emit()
+		}
+		assert.Equal(t, "books", attrs[semconv.DBSQLTableKey])
+		assert.Contains(t, attrs[semconv.DBStatementKey], "SELECT * FROM `books`")
+	}
+
+	// Statements gorm doesn't build are traced too.
+	exec := "INSERT INTO `authors` (`name`, `created_at`, `updated_at`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `id` = LAST_INSERT_ID(`id`)"
+
+	ctx, parent = otel.Tracer("test").Start(context.Background(), "parent")
+
+	mock.ExpectExec(exec).
+		WithArgs("author", AnyTime{}, AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+
+	_, err = repo.EnsureAuthor(ctx, "author")
+	assert.NoError(t, err)
+	parent.End()
+
+	spans = recorder.Ended()
+	if assert.Len(t, spans, 4) {
+		span := spans[2]
+		assert.Equal(t, "gorm.Exec", span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		attrs := map[attribute.Key]string{}
+		for _, kv := range span.Attributes() {
+			attrs[kv.Key] = kv.Value.Emit()
+		}
+		assert.Equal(t, "authors", attrs[semconv.DBSQLTableKey])
+		assert.Equal(t, exec, attrs[semconv.DBStatementKey])
+		assert.Equal(t, "1", attrs["db.rows_affected"])
+	}
+}
+
+func TestBookRepo_CreateBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	columns := "INSERT INTO `books` (`created_at`, `updated_at`, `title`, `author`, `published_date`, `image_url`, `description`, `isbn`, `created_by`, `version`) VALUES "
+	query := columns + "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	readBack := "SELECT `id`, `title`, `author`, `created_by` FROM `books` WHERE `id` >= ? AND `created_at` = ? ORDER BY `id`"
+	readBackColumns := []string{"id", "title", "author", "created_by"}
+
+	isbn := "9780306406157"
+	newBooks := func() []*model.Book {
+		return []*model.Book{
+			{Title: "first", Author: "author", PublishedDate: book.PublishedDate},
+			{Title: "second", Author: "author", PublishedDate: book.PublishedDate, ISBN: &isbn, CreatedBy: "alice"},
+		}
+	}
+
+	t.Run("Success call", func(t *testing.T) {
+		books := newBooks()
+
+		mock.ExpectExec(query).
+			WithArgs(
+				AnyTime{}, AnyTime{}, "first", "author", book.PublishedDate, "", "", nil, "", 1,
+				AnyTime{}, AnyTime{}, "second", "author", book.PublishedDate, "", "", isbn, "alice", 1,
+			).
+			WillReturnResult(sqlmock.NewResult(7, 2))
+		// A concurrent insert interleaved its row with the rows of the INSERT.
+		mock.ExpectQuery(readBack).
+			WithArgs(7, AnyTime{}).
+			WillReturnRows(sqlmock.NewRows(readBackColumns).
+				AddRow(7, "first", "author", "").
+				AddRow(8, "other", "author", "bob").
+				AddRow(9, "second", "author", "alice"))
+
+		err := repo.CreateBooks(context.Background(), books)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(7), books[0].ID)
+		assert.Equal(t, uint(9), books[1].ID)
+		assert.Equal(t, uint(1), books[1].Version)
+		assert.Equal(t, books[0].CreatedAt.Truncate(time.Second), books[0].CreatedAt)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Chunked call", func(t *testing.T) {
+		books := make([]*model.Book, 501)
+		for i := range books {
+			books[i] = &model.Book{Title: "title", Author: "author", PublishedDate: book.PublishedDate}
+		}
+
+		first := sqlmock.NewRows(readBackColumns)
+		for i := 0; i < 500; i++ {
+			first.AddRow(i+1, "title", "author", "")
+		}
+
+		mock.ExpectExec(columns + strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?, ?, ?, ?), ", 500), ", ")).
+			WillReturnResult(sqlmock.NewResult(1, 500))
+		mock.ExpectQuery(readBack).WithArgs(1, AnyTime{}).WillReturnRows(first)
+		mock.ExpectExec(columns + "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)").
+			WillReturnResult(sqlmock.NewResult(501, 1))
+		mock.ExpectQuery(readBack).
+			WithArgs(501, AnyTime{}).
+			WillReturnRows(sqlmock.NewRows(readBackColumns).AddRow(501, "title", "author", ""))
+
+		err := repo.CreateBooks(context.Background(), books)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), books[0].ID)
+		assert.Equal(t, uint(500), books[499].ID)
+		assert.Equal(t, uint(501), books[500].ID)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		books := newBooks()
+
+		mock.ExpectExec(query).WillReturnError(errors.New("error"))
+
+		err := repo.CreateBooks(context.Background(), books)
+		assert.Error(t, err)
+		assert.Equal(t, uint(0), books[0].ID)
+		assert.Equal(t, uint(0), books[1].ID)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Missing rows call", func(t *testing.T) {
+		books := newBooks()
+
+		mock.ExpectExec(query).WillReturnResult(sqlmock.NewResult(7, 2))
+		mock.ExpectQuery(readBack).
+			WithArgs(7, AnyTime{}).
+			WillReturnRows(sqlmock.NewRows(readBackColumns).AddRow(7, "first", "author", ""))
+
+		err := repo.CreateBooks(context.Background(), books)
+		assert.Error(t, err)
+		assert.Equal(t, uint(0), books[0].ID)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_Transaction(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "UPDATE `books` SET `deleted_at`=? WHERE `books`.`deleted_at` IS NULL AND ((id = ?))"
+
+	t.Run("Commit call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(AnyTime{}, book.ID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.Transaction(context.Background(), func(tx repository.BookRepoInterface) error {
+			return tx.DeleteBook(context.Background(), book.ID, 0)
+		})
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Nested call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(AnyTime{}, book.ID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		err := repo.Transaction(context.Background(), func(tx repository.BookRepoInterface) error {
+			return tx.Transaction(context.Background(), func(nested repository.BookRepoInterface) error {
+				return nested.DeleteBook(context.Background(), book.ID, 0)
+			})
+		})
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Rollback call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(AnyTime{}, book.ID).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		err := repo.Transaction(context.Background(), func(tx repository.BookRepoInterface) error {
+			if err := tx.DeleteBook(context.Background(), book.ID, 0); err != nil {
+				return err
+			}
+			return apperror.Conflict("book already exists", nil)
+		})
+		assert.ErrorIs(t, err, apperror.ErrConflict)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Query timeout call", func(t *testing.T) {
+		repo := repository.NewBookRepo(db, 10*time.Millisecond)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(AnyTime{}, book.ID).
+			WillDelayFor(time.Second).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectRollback()
+
+		start := time.Now()
+		err := repo.Transaction(context.Background(), func(tx repository.BookRepoInterface) error {
+			return tx.DeleteBook(context.Background(), book.ID, 0)
+		})
+		assert.Error(t, err)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_EachBook(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "SELECT * FROM `books`  WHERE `books`.`deleted_at` IS NULL AND ((id > ?)) ORDER BY id ASC LIMIT 500"
+
+	t.Run("Success call", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "title", "author"}).
+			AddRow(1, "first", "author").
+			AddRow(4, "second", "author")
+		mock.ExpectQuery(query).WithArgs(0).WillReturnRows(rows)
+
+		var ids []uint
+		err := repo.EachBook(context.Background(), func(b *model.Book) error {
+			ids = append(ids, b.ID)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []uint{1, 4}, ids)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Callback error call", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "title", "author"}).
+			AddRow(1, "first", "author").
+			AddRow(4, "second", "author")
+		mock.ExpectQuery(query).WithArgs(0).WillReturnRows(rows)
+
+		calls := 0
+		err := repo.EachBook(context.Background(), func(b *model.Book) error {
+			calls++
+			return errors.New("write failed")
+		})
+		assert.EqualError(t, err, "write failed")
+		assert.Equal(t, 1, calls)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		mock.ExpectQuery(query).WithArgs(0).WillReturnError(errors.New("error"))
+
+		err := repo.EachBook(context.Background(), func(b *model.Book) error {
+			return nil
+		})
+		assert.Error(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_ListDeletedBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	countQuery := "SELECT count(*) FROM `books`  WHERE (deleted_at IS NOT NULL)"
+	query := "SELECT * FROM `books`  WHERE (deleted_at IS NOT NULL) ORDER BY deleted_at DESC,id DESC LIMIT 20 OFFSET 0"
+
+	t.Run("Success call", func(t *testing.T) {
+		deletedAt := time.Now()
+
+		mock.ExpectQuery(countQuery).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		mock.ExpectQuery(query).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "deleted_at"}).AddRow(1, "title", deletedAt))
+
+		books, total, err := repo.ListDeletedBooks(context.Background(), &model.PageQuery{Limit: 20})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Len(t, books, 1)
+		assert.Equal(t, deletedAt, *books[0].DeletedAt)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).WillReturnError(errors.New("error"))
+
+		_, _, err := repo.ListDeletedBooks(context.Background(), &model.PageQuery{Limit: 20})
+		assert.Error(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_SetBookCover(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "UPDATE `books` SET `cover_key` = ?, `cover_type` = ?, `updated_at` = ?, `version` = version + 1 WHERE `books`.`deleted_at` IS NULL AND ((id = ?))"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs("covers/1/5c1f", "image/png", AnyTime{}, book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.SetBookCover(context.Background(), book.ID, "image/png", "covers/1/5c1f")
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not found call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs("", "", AnyTime{}, book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.SetBookCover(context.Background(), book.ID, "", "")
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_RestoreBook(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "UPDATE `books` SET `deleted_at` = ?, `updated_at` = ?, `version` = version + 1 WHERE (id = ? AND deleted_at IS NOT NULL)"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(nil, AnyTime{}, book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.RestoreBook(context.Background(), book.ID)
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not in trash call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(nil, AnyTime{}, book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		err := repo.RestoreBook(context.Background(), book.ID)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_LockBook(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery("SELECT * FROM `books`  WHERE `books`.`deleted_at` IS NULL AND ((id = ?)) ORDER BY `books`.`id` ASC LIMIT 1 FOR UPDATE").
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "version"}).AddRow(book.ID, "title", 3))
+
+		b, err := repo.LockBook(context.Background(), book.ID, false)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(3), b.Version)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not in trash call", func(t *testing.T) {
+		mock.ExpectQuery("SELECT * FROM `books`  WHERE (id = ?) AND (deleted_at IS NOT NULL) ORDER BY `books`.`id` ASC LIMIT 1 FOR UPDATE").
+			WithArgs(book.ID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := repo.LockBook(context.Background(), book.ID, true)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_LockDeletedBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	before := time.Now().Add(-time.Hour)
+
+	mock.ExpectQuery("SELECT * FROM `books`  WHERE (deleted_at IS NOT NULL AND deleted_at < ?) ORDER BY id ASC LIMIT 100 FOR UPDATE").
+		WithArgs(before).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "deleted_at"}).AddRow(2, before.Add(-time.Hour)).AddRow(5, before.Add(-time.Minute)))
+
+	books, err := repo.LockDeletedBooks(context.Background(), before, 100)
+	assert.NoError(t, err)
+	assert.Len(t, books, 2)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBookRepo_PurgeBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	query := "DELETE FROM `books`  WHERE (id IN (?,?) AND deleted_at IS NOT NULL)"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(2, 5).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		purged, err := repo.PurgeBooks(context.Background(), []uint{2, 5})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(query).
+			WithArgs(2, 5).
+			WillReturnError(errors.New("error"))
+		mock.ExpectRollback()
+
+		_, err := repo.PurgeBooks(context.Background(), []uint{2, 5})
+		assert.Error(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestBookRepo_BookAuthorIDs(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	mock.ExpectQuery("SELECT author_id FROM `book_authors`  WHERE (book_id = ?) ORDER BY position ASC").
+		WithArgs(book.ID).
+		WillReturnRows(sqlmock.NewRows([]string{"author_id"}).AddRow(4).AddRow(2))
+
+	ids, err := repo.BookAuthorIDs(context.Background(), book.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{4, 2}, ids)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBookRepo_EnsureAuthor(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	// An existing author is reported by LAST_INSERT_ID(id) as if inserted.
+	mock.ExpectExec("INSERT INTO `authors` (`name`, `created_at`, `updated_at`) VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE `id` = LAST_INSERT_ID(`id`)").
+		WithArgs("J. R. R. Tolkien", AnyTime{}, AnyTime{}).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+
+	id, err := repo.EnsureAuthor(context.Background(), "J. R. R. Tolkien")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), id)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestBookRepo_SetBookAuthors(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewBookRepo(db, time.Second)
+
+	countQuery := "SELECT count(*) FROM `authors`  WHERE `authors`.`deleted_at` IS NULL AND ((id IN (?,?)))"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WithArgs(4, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+		mock.ExpectExec("DELETE FROM `book_authors` WHERE `book_id` = ?").
+			WithArgs(book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `book_authors` (`book_id`, `author_id`, `position`) VALUES (?, ?, ?), (?, ?, ?)").
+			WithArgs(book.ID, 4, 0, book.ID, 2, 1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := repo.SetBookAuthors(context.Background(), book.ID, []uint{4, 2})
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Unknown author call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WithArgs(4, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+
+		err := repo.SetBookAuthors(context.Background(), book.ID, []uint{4, 2})
+		assert.ErrorIs(t, err, apperror.ErrValidation)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("No authors call", func(t *testing.T) {
+		mock.ExpectExec("DELETE FROM `book_authors` WHERE `book_id` = ?").
+			WithArgs(book.ID).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := repo.SetBookAuthors(context.Background(), book.ID, []uint{})
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
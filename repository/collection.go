@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	dbConn "myapp/adapter/gorm"
+	"myapp/model"
+	"myapp/util/apperror"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+type CollectionRepo struct {
+	repo         *gorm.DB
+	queryTimeout time.Duration
+}
+
+func NewCollectionRepo(conn *gorm.DB, queryTimeout time.Duration) *CollectionRepo {
+	return &CollectionRepo{
+		repo:         conn,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// Transaction calls fn with a repository whose writes are committed together
+// when fn returns nil, and rolled back otherwise.
+func (r *CollectionRepo) Transaction(ctx context.Context, fn func(repo CollectionRepoInterface) error) error {
+	return inTransaction(ctx, r.repo, "collection", func(tx *gorm.DB) error {
+		return fn(&CollectionRepo{repo: tx, queryTimeout: r.queryTimeout})
+	})
+}
+
+// ListCollections returns a page of the collections, most recent first, and
+// their total count.
+func (r *CollectionRepo) ListCollections(ctx context.Context, query *model.PageQuery) (model.Collections, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	db := conn.Model(&model.Collection{})
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "collection")
+	}
+
+	collections := make([]*model.Collection, 0)
+	if err := db.Order("id DESC").Offset(query.Offset).Limit(query.Limit).Find(&collections).Error; err != nil {
+		return nil, 0, translateError(ctx, err, "collection")
+	}
+
+	return collections, total, nil
+}
+
+func (r *CollectionRepo) ReadCollection(ctx context.Context, id uint) (*model.Collection, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	collection := &model.Collection{}
+	if err := conn.Where("id = ?", id).First(collection).Error; err != nil {
+		return nil, translateError(ctx, err, "collection")
+	}
+
+	return collection, nil
+}
+
+// LockCollection reads the collection and locks it until the end of the
+// transaction.
+func (r *CollectionRepo) LockCollection(ctx context.Context, id uint) (*model.Collection, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	collection := &model.Collection{}
+	if err := conn.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", id).First(collection).Error; err != nil {
+		return nil, translateError(ctx, err, "collection")
+	}
+
+	return collection, nil
+}
+
+// CollectionBooks returns the books of the collection in order; books in the
+// trash are left out.
+func (r *CollectionRepo) CollectionBooks(ctx context.Context, id uint) (model.Books, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	books := make([]*model.Book, 0)
+	if err := conn.Model(&model.Book{}).
+		Joins("JOIN collection_books ON collection_books.book_id = books.id").
+		Where("collection_books.collection_id = ?", id).
+		Order("collection_books.position ASC").
+		Select("books.*").
+		Find(&books).Error; err != nil {
+		return nil, translateError(ctx, err, "book")
+	}
+
+	return books, nil
+}
+
+func (r *CollectionRepo) CreateCollection(ctx context.Context, collection *model.Collection) (*model.Collection, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	if err := conn.Create(collection).Error; err != nil {
+		return nil, translateError(ctx, err, "collection")
+	}
+
+	return collection, nil
+}
+
+// UpdateCollection saves the name and description of the collection.
+func (r *CollectionRepo) UpdateCollection(ctx context.Context, collection *model.Collection) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Model(&model.Collection{}).Where("id = ?", collection.ID).Updates(map[string]interface{}{
+		"name":        collection.Name,
+		"description": collection.Description,
+	})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "collection")
+	}
+
+	// Matched rows are affected, changed or not (ClientFoundRows), so no
+	// affected row means no matching row.
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("collection not found", nil)
+	}
+
+	return nil
+}
+
+func (r *CollectionRepo) DeleteCollection(ctx context.Context, id uint) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Where("id = ?", id).Delete(&model.Collection{})
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, "collection")
+	}
+
+	if res.RowsAffected == 0 {
+		return apperror.NotFound("collection not found", nil)
+	}
+
+	return nil
+}
+
+// SetCollectionBooks replaces the books of the collection by bookIDs, in
+// order. It fails with a validation error when one of them doesn't exist.
+func (r *CollectionRepo) SetCollectionBooks(ctx context.Context, id uint, bookIDs []uint) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	if len(bookIDs) > 0 {
+		var found int64
+		if err := conn.Model(&model.Book{}).Where("id IN (?)", bookIDs).Count(&found).Error; err != nil {
+			return translateError(ctx, err, "book")
+		}
+
+		if found != int64(len(bookIDs)) {
+			return apperror.Validation("unknown book", nil, apperror.FieldError{Name: "book_ids", Reason: "must reference existing books"})
+		}
+	}
+
+	if _, err := dbConn.Exec(conn, "collection_books", "DELETE FROM `collection_books` WHERE `collection_id` = ?", id); err != nil {
+		return translateError(ctx, err, "collection book")
+	}
+
+	for start := 0; start < len(bookIDs); start += insertBatchSize {
+		end := start + insertBatchSize
+		if end > len(bookIDs) {
+			end = len(bookIDs)
+		}
+
+		rows := make([]string, 0, end-start)
+		args := make([]interface{}, 0, (end-start)*3)
+		for i := start; i < end; i++ {
+			rows = append(rows, "(?, ?, ?)")
+			args = append(args, id, bookIDs[i], i)
+		}
+
+		if _, err := dbConn.Exec(conn, "collection_books", "INSERT INTO `collection_books` (`collection_id`, `book_id`, `position`) VALUES "+strings.Join(rows, ", "), args...); err != nil {
+			return translateError(ctx, err, "collection book")
+		}
+	}
+
+	return nil
+}
+
+type CollectionRepoInterface interface {
+	Transaction(ctx context.Context, fn func(repo CollectionRepoInterface) error) error
+	ListCollections(ctx context.Context, query *model.PageQuery) (model.Collections, int64, error)
+	ReadCollection(ctx context.Context, id uint) (*model.Collection, error)
+	LockCollection(ctx context.Context, id uint) (*model.Collection, error)
+	CollectionBooks(ctx context.Context, id uint) (model.Books, error)
+	CreateCollection(ctx context.Context, collection *model.Collection) (*model.Collection, error)
+	UpdateCollection(ctx context.Context, collection *model.Collection) error
+	DeleteCollection(ctx context.Context, id uint) error
+	SetCollectionBooks(ctx context.Context, id uint, bookIDs []uint) error
+}
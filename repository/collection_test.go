@@ -0,0 +1,118 @@
+package repository_test
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/apperror"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionRepo_CollectionBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewCollectionRepo(db, time.Second)
+
+	mock.ExpectQuery("SELECT books.* FROM `books` JOIN collection_books ON collection_books.book_id = books.id WHERE `books`.`deleted_at` IS NULL AND ((collection_books.collection_id = ?)) ORDER BY collection_books.position ASC").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(3, "The Hobbit").AddRow(1, "Dune"))
+
+	books, err := repo.CollectionBooks(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, books, 2)
+	assert.Equal(t, uint(3), books[0].ID)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCollectionRepo_LockCollection(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewCollectionRepo(db, time.Second)
+
+	mock.ExpectQuery("SELECT * FROM `collections`  WHERE `collections`.`deleted_at` IS NULL AND ((id = ?)) ORDER BY `collections`.`id` ASC LIMIT 1 FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "owner"}).AddRow(1, "Favorites", "alice"))
+
+	collection, err := repo.LockCollection(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", collection.Owner)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCollectionRepo_UpdateCollection(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewCollectionRepo(db, time.Second)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE `collections` SET `description` = ?, `name` = ?, `updated_at` = ? WHERE `collections`.`deleted_at` IS NULL AND ((id = ?))").
+		WithArgs("", "Favorites", AnyTime{}, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.UpdateCollection(context.Background(), &model.Collection{Model: gorm.Model{ID: 2}, Name: "Favorites"})
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCollectionRepo_SetCollectionBooks(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewCollectionRepo(db, time.Second)
+
+	countQuery := "SELECT count(*) FROM `books`  WHERE `books`.`deleted_at` IS NULL AND ((id IN (?,?)))"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WithArgs(3, 1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+		mock.ExpectExec("DELETE FROM `collection_books` WHERE `collection_id` = ?").
+			WithArgs(1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("INSERT INTO `collection_books` (`collection_id`, `book_id`, `position`) VALUES (?, ?, ?), (?, ?, ?)").
+			WithArgs(1, 3, 0, 1, 1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := repo.SetCollectionBooks(context.Background(), 1, []uint{3, 1})
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Unknown book call", func(t *testing.T) {
+		mock.ExpectQuery(countQuery).
+			WithArgs(3, 4).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+
+		err := repo.SetCollectionBooks(context.Background(), 1, []uint{3, 4})
+		assert.ErrorIs(t, err, apperror.ErrValidation)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	dbConn "myapp/adapter/gorm"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// withTimeout returns a handle on db whose queries are cancelled with ctx or
+// once timeout elapses. The returned cancel function must be called.
+func withTimeout(ctx context.Context, db *gorm.DB, timeout time.Duration) (context.Context, *gorm.DB, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	return ctx, dbConn.WithContext(ctx, db), cancel
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"myapp/util/apperror"
+	"myapp/util/logger"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jinzhu/gorm"
+)
+
+// mysqlErrDuplicateEntry is the MySQL error number for unique key violations.
+const mysqlErrDuplicateEntry = 1062
+
+// translateError converts ORM and driver errors into domain errors so the
+// layers above don't depend on gorm or the MySQL driver. Errors without a
+// domain meaning are logged with the request ID before being returned as is.
+func translateError(ctx context.Context, err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+
+	// The driver reports an interrupted query in various ways, the context tells why.
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return apperror.Canceled("request canceled while querying "+entity, err)
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return apperror.Timeout("query on "+entity+" timed out", err)
+	}
+
+	if gorm.IsRecordNotFoundError(err) {
+		return apperror.NotFound(entity+" not found", err)
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) && myErr.Number == mysqlErrDuplicateEntry {
+		return apperror.Conflict(entity+" already exists", err)
+	}
+
+	logger.Ctx(ctx).Warn().Err(err).Str("entity", entity).Msg("Database query failed")
+
+	return err
+}
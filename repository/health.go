@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pressly/goose"
+)
+
+type HealthRepo struct {
+	repo          *gorm.DB
+	migrationsDir string
+}
+
+func NewHealthRepo(conn *gorm.DB, migrationsDir string) *HealthRepo {
+	return &HealthRepo{
+		repo:          conn,
+		migrationsDir: migrationsDir,
+	}
+}
+
+func (r *HealthRepo) Ping(ctx context.Context) error {
+	return r.repo.DB().PingContext(ctx)
+}
+
+// MigrationVersion returns the schema version applied by goose. Unlike
+// goose.GetDBVersion it never creates the version table.
+func (r *HealthRepo) MigrationVersion(ctx context.Context) (int64, error) {
+	rows, err := r.repo.DB().QueryContext(ctx, fmt.Sprintf("SELECT version_id, is_applied FROM %s ORDER BY id DESC", goose.TableName()))
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	// The most recent record of each version tells whether it was applied or
+	// rolled back; the first applied one is the current version.
+	rolledBack := map[int64]bool{}
+	for rows.Next() {
+		var (
+			version int64
+			applied bool
+		)
+		if err := rows.Scan(&version, &applied); err != nil {
+			return 0, err
+		}
+
+		if rolledBack[version] {
+			continue
+		}
+		if applied {
+			return version, nil
+		}
+		rolledBack[version] = true
+	}
+
+	return 0, rows.Err()
+}
+
+// LatestMigration returns the version of the newest migration shipped with the application.
+func (r *HealthRepo) LatestMigration() (int64, error) {
+	migrations, err := goose.CollectMigrations(r.migrationsDir, 0, goose.MaxVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	last, err := migrations.Last()
+	if err != nil {
+		return 0, err
+	}
+
+	return last.Version, nil
+}
+
+type HealthRepoInterface interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (int64, error)
+	LatestMigration() (int64, error)
+}
@@ -0,0 +1,70 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"myapp/repository"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthRepo_MigrationVersion(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewHealthRepo(db, "")
+
+	query := "SELECT version_id, is_applied FROM goose_db_version ORDER BY id DESC"
+
+	t.Run("Success call", func(t *testing.T) {
+		// Version 3 was applied then rolled back, so the schema is at version 2.
+		rows := sqlmock.NewRows([]string{"version_id", "is_applied"}).
+			AddRow(3, false).
+			AddRow(3, true).
+			AddRow(2, true).
+			AddRow(1, true)
+
+		mock.ExpectQuery(query).WillReturnRows(rows)
+
+		version, err := repo.MigrationVersion(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), version)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Error call", func(t *testing.T) {
+		mock.ExpectQuery(query).WillReturnError(errors.New("error"))
+
+		_, err := repo.MigrationVersion(context.Background())
+		assert.Error(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestHealthRepo_LatestMigration(t *testing.T) {
+	db, _ := NewMock()
+
+	defer db.Close()
+
+	dir := t.TempDir()
+	for _, name := range []string{"00001_create_books_table.sql", "00002_add_books_fulltext_index.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	version, err := repository.NewHealthRepo(db, dir).LatestMigration()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+}
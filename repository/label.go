@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/util/apperror"
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// labelTable is where labels of a kind are stored and attached to books.
+type labelTable struct {
+	table  string
+	join   string
+	column string
+}
+
+var labelTables = map[model.LabelKind]labelTable{
+	model.LabelTag:   {table: "tags", join: "book_tags", column: "tag_id"},
+	model.LabelGenre: {table: "genres", join: "book_genres", column: "genre_id"},
+}
+
+type LabelRepo struct {
+	repo         *gorm.DB
+	queryTimeout time.Duration
+}
+
+func NewLabelRepo(conn *gorm.DB, queryTimeout time.Duration) *LabelRepo {
+	return &LabelRepo{
+		repo:         conn,
+		queryTimeout: queryTimeout,
+	}
+}
+
+func (r *LabelRepo) Transaction(ctx context.Context, fn func(repo LabelRepoInterface) error) error {
+	return inTransaction(ctx, r.repo, "label", func(tx *gorm.DB) error {
+		return fn(&LabelRepo{repo: tx, queryTimeout: r.queryTimeout})
+	})
+}
+
+// LockBook reads the book labels are attached to and locks it until the end
+// of the transaction.
+func (r *LabelRepo) LockBook(ctx context.Context, bookID uint) (*model.Book, error) {
+	return (&BookRepo{repo: r.repo, queryTimeout: r.queryTimeout}).LockBook(ctx, bookID, false)
+}
+
+// ListLabels returns a page of the labels of the kind in name order and their
+// total count.
+func (r *LabelRepo) ListLabels(ctx context.Context, kind model.LabelKind, query *model.PageQuery) (model.Labels, int64, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	t := labelTables[kind]
+	db := conn.Table(t.table)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, translateError(ctx, err, string(kind))
+	}
+
+	labels := make([]*model.Label, 0)
+	if err := db.Order("name ASC").Offset(query.Offset).Limit(query.Limit).Find(&labels).Error; err != nil {
+		return nil, 0, translateError(ctx, err, string(kind))
+	}
+
+	return labels, total, nil
+}
+
+func (r *LabelRepo) CreateLabel(ctx context.Context, kind model.LabelKind, label *model.Label) (*model.Label, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	if err := conn.Table(labelTables[kind].table).Create(label).Error; err != nil {
+		return nil, translateError(ctx, err, string(kind))
+	}
+
+	return label, nil
+}
+
+// DeleteLabel deletes the label and detaches it from its books.
+func (r *LabelRepo) DeleteLabel(ctx context.Context, kind model.LabelKind, name string) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	res := conn.Exec("DELETE FROM `"+labelTables[kind].table+"` WHERE `name` = ?", name)
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, string(kind))
+	}
+
+	if res.RowsAffected == 0 {
+		return apperror.NotFound(string(kind)+" not found", nil)
+	}
+
+	return nil
+}
+
+// BookLabels returns the labels of the kind attached to the book, in name order.
+func (r *LabelRepo) BookLabels(ctx context.Context, kind model.LabelKind, bookID uint) (model.Labels, error) {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	t := labelTables[kind]
+
+	labels := make([]*model.Label, 0)
+	if err := conn.Table(t.table).
+		Joins("JOIN "+t.join+" ON "+t.join+"."+t.column+" = "+t.table+".id").
+		Where(t.join+".book_id = ?", bookID).
+		Order(t.table + ".name ASC").
+		Select(t.table + ".*").
+		Find(&labels).Error; err != nil {
+		return nil, translateError(ctx, err, string(kind))
+	}
+
+	return labels, nil
+}
+
+// AttachLabel attaches the label to the book, unless it already is. With
+// create set a missing label is created, otherwise it's not found.
+func (r *LabelRepo) AttachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string, create bool) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	t := labelTables[kind]
+
+	var books int64
+	if err := conn.Model(&model.Book{}).Where("id = ?", bookID).Count(&books).Error; err != nil {
+		return translateError(ctx, err, "book")
+	}
+
+	if books == 0 {
+		return apperror.NotFound("book not found", nil)
+	}
+
+	if create {
+		if err := conn.Exec("INSERT INTO `"+t.table+"` (`name`, `created_at`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `id` = `id`", name, gorm.NowFunc()).Error; err != nil {
+			return translateError(ctx, err, string(kind))
+		}
+	}
+
+	label := &model.Label{}
+	if err := conn.Table(t.table).Where("name = ?", name).First(label).Error; err != nil {
+		return translateError(ctx, err, string(kind))
+	}
+
+	if err := conn.Exec("INSERT INTO `"+t.join+"` (`book_id`, `"+t.column+"`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `book_id` = `book_id`", bookID, label.ID).Error; err != nil {
+		return translateError(ctx, err, string(kind))
+	}
+
+	return nil
+}
+
+// DetachLabel detaches the label from the book.
+func (r *LabelRepo) DetachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) error {
+	ctx, conn, cancel := withTimeout(ctx, r.repo, r.queryTimeout)
+	defer cancel()
+
+	t := labelTables[kind]
+
+	res := conn.Exec("DELETE `"+t.join+"` FROM `"+t.join+"` JOIN `"+t.table+"` ON `"+t.table+"`.`id` = `"+t.join+"`.`"+t.column+"` WHERE `"+t.join+"`.`book_id` = ? AND `"+t.table+"`.`name` = ?", bookID, name)
+	if err := res.Error; err != nil {
+		return translateError(ctx, err, string(kind))
+	}
+
+	if res.RowsAffected == 0 {
+		return apperror.NotFound(string(kind)+" not attached to the book", nil)
+	}
+
+	return nil
+}
+
+type LabelRepoInterface interface {
+	Transaction(ctx context.Context, fn func(repo LabelRepoInterface) error) error
+	LockBook(ctx context.Context, bookID uint) (*model.Book, error)
+	ListLabels(ctx context.Context, kind model.LabelKind, query *model.PageQuery) (model.Labels, int64, error)
+	CreateLabel(ctx context.Context, kind model.LabelKind, label *model.Label) (*model.Label, error)
+	DeleteLabel(ctx context.Context, kind model.LabelKind, name string) error
+	BookLabels(ctx context.Context, kind model.LabelKind, bookID uint) (model.Labels, error)
+	AttachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string, create bool) error
+	DetachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) error
+}
@@ -0,0 +1,173 @@
+package repository_test
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/apperror"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelRepo_ListLabels(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewLabelRepo(db, time.Second)
+
+	mock.ExpectQuery("SELECT count(*) FROM `genres`").
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(2))
+	mock.ExpectQuery("SELECT * FROM `genres`   ORDER BY name ASC LIMIT 1 OFFSET 1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(2, "science fiction"))
+
+	labels, total, err := repo.ListLabels(context.Background(), model.LabelGenre, &model.PageQuery{Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, model.Labels{{ID: 2, Name: "science fiction"}}, labels)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestLabelRepo_BookLabels(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewLabelRepo(db, time.Second)
+
+	mock.ExpectQuery("SELECT tags.* FROM `tags` JOIN book_tags ON book_tags.tag_id = tags.id WHERE (book_tags.book_id = ?) ORDER BY tags.name ASC").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "classic").AddRow(1, "favorite"))
+
+	labels, err := repo.BookLabels(context.Background(), model.LabelTag, 1)
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestLabelRepo_AttachLabel(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewLabelRepo(db, time.Second)
+
+	bookQuery := "SELECT count(*) FROM `books`  WHERE `books`.`deleted_at` IS NULL AND ((id = ?))"
+
+	t.Run("New tag call", func(t *testing.T) {
+		mock.ExpectQuery(bookQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		mock.ExpectExec("INSERT INTO `tags` (`name`, `created_at`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `id` = `id`").
+			WithArgs("classic", AnyTime{}).
+			WillReturnResult(sqlmock.NewResult(3, 1))
+		mock.ExpectQuery("SELECT * FROM `tags`  WHERE (name = ?) ORDER BY `tags`.`id` ASC LIMIT 1").
+			WithArgs("classic").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(3, "classic"))
+		mock.ExpectExec("INSERT INTO `book_tags` (`book_id`, `tag_id`) VALUES (?, ?) ON DUPLICATE KEY UPDATE `book_id` = `book_id`").
+			WithArgs(1, 3).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.AttachLabel(context.Background(), model.LabelTag, 1, "classic", true)
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Unknown genre call", func(t *testing.T) {
+		mock.ExpectQuery(bookQuery).
+			WithArgs(1).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(1))
+		mock.ExpectQuery("SELECT * FROM `genres`  WHERE (name = ?) ORDER BY `genres`.`id` ASC LIMIT 1").
+			WithArgs("fantasy").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+		err := repo.AttachLabel(context.Background(), model.LabelGenre, 1, "fantasy", false)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+		assert.EqualError(t, err, "genre not found: record not found")
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Unknown book call", func(t *testing.T) {
+		mock.ExpectQuery(bookQuery).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(0))
+
+		err := repo.AttachLabel(context.Background(), model.LabelTag, 2, "classic", true)
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestLabelRepo_DetachLabel(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewLabelRepo(db, time.Second)
+
+	query := "DELETE `book_genres` FROM `book_genres` JOIN `genres` ON `genres`.`id` = `book_genres`.`genre_id` WHERE `book_genres`.`book_id` = ? AND `genres`.`name` = ?"
+
+	t.Run("Success call", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(1, "fantasy").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := repo.DetachLabel(context.Background(), model.LabelGenre, 1, "fantasy")
+		assert.NoError(t, err)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("Not attached call", func(t *testing.T) {
+		mock.ExpectExec(query).
+			WithArgs(1, "fantasy").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := repo.DetachLabel(context.Background(), model.LabelGenre, 1, "fantasy")
+		assert.ErrorIs(t, err, apperror.ErrNotFound)
+
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("there were unfulfilled expectations: %s", err)
+		}
+	})
+}
+
+func TestLabelRepo_DeleteLabel(t *testing.T) {
+	db, mock := NewMock()
+
+	defer db.Close()
+
+	repo := repository.NewLabelRepo(db, time.Second)
+
+	mock.ExpectExec("DELETE FROM `tags` WHERE `name` = ?").
+		WithArgs("classic").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.DeleteLabel(context.Background(), model.LabelTag, "classic")
+	assert.ErrorIs(t, err, apperror.ErrNotFound)
+	assert.EqualError(t, err, "tag not found")
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	dbConn "myapp/adapter/gorm"
+
+	"github.com/jinzhu/gorm"
+)
+
+// inTransaction calls fn with a handle on a new transaction of conn, which is
+// committed when fn returns nil and rolled back otherwise. When conn is
+// already a transaction, fn is called with it and joins it.
+func inTransaction(ctx context.Context, conn *gorm.DB, entity string, fn func(tx *gorm.DB) error) error {
+	if _, ok := conn.CommonDB().(*sql.Tx); ok {
+		return fn(conn)
+	}
+
+	tx := dbConn.WithContext(ctx, conn).BeginTx(ctx, &sql.TxOptions{})
+	if err := tx.Error; err != nil {
+		return translateError(ctx, err, entity)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return translateError(ctx, tx.Commit().Error, entity)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"myapp/util/logger"
+	"strings"
+	"time"
+)
+
+const (
+	// apiKeyPrefix starts every key, so that leaked keys are easy to spot.
+	apiKeyPrefix = "mk_"
+
+	// apiKeyPrefixLength is the length of the beginning of a key which is
+	// kept to tell keys apart.
+	apiKeyPrefixLength = len(apiKeyPrefix) + 8
+
+	// apiKeyTouchInterval bounds how often the last use of a key is
+	// recorded, so busy clients don't write on every request.
+	apiKeyTouchInterval = time.Minute
+)
+
+type ApiKeyService struct {
+	apiKeyRepo repository.ApiKeyRepoInterface
+	now        func() time.Time
+}
+
+func NewApiKeyService(apiKeyRepo repository.ApiKeyRepoInterface) *ApiKeyService {
+	return &ApiKeyService{apiKeyRepo: apiKeyRepo, now: time.Now}
+}
+
+type ApiKeyServiceInterface interface {
+	CreateApiKey(ctx context.Context, key *model.ApiKeyForm) (*model.ApiKeyCreatedDto, error)
+	GetListApiKey(ctx context.Context, query *model.PageQuery) (*model.ApiKeyListDto, error)
+	UpdateApiKeyScopes(ctx context.Context, id uint, scopes *model.ApiKeyScopesForm) error
+	RevokeApiKey(ctx context.Context, id uint) error
+	Authenticate(ctx context.Context, key string) (*model.ApiKey, error)
+}
+
+// CreateApiKey mints a key created by the actor of the request. The key is
+// returned this once: only its hash is stored.
+func (a *ApiKeyService) CreateApiKey(ctx context.Context, key *model.ApiKeyForm) (_ *model.ApiKeyCreatedDto, err error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.CreateApiKey")
+	defer func() { endSpan(span, err) }()
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	secret := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+
+	keyModel := key.ToModel()
+	keyModel.Prefix = secret[:apiKeyPrefixLength]
+	keyModel.KeyHash = hashApiKey(secret)
+	keyModel.CreatedBy = actor.FromContext(ctx)
+	if _, err := a.apiKeyRepo.CreateApiKey(ctx, keyModel); err != nil {
+		return nil, err
+	}
+
+	return &model.ApiKeyCreatedDto{ApiKeyDto: *keyModel.ToDto(), Key: secret}, nil
+}
+
+func (a *ApiKeyService) GetListApiKey(ctx context.Context, query *model.PageQuery) (_ *model.ApiKeyListDto, err error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.GetListApiKey")
+	defer func() { endSpan(span, err) }()
+
+	keys, total, err := a.apiKeyRepo.ListApiKeys(ctx, query)
+	if err != nil {
+		return &model.ApiKeyListDto{}, err
+	}
+
+	return &model.ApiKeyListDto{
+		Data:   keys.ToDto(),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}, nil
+}
+
+// UpdateApiKeyScopes replaces the scopes of the key; those of revoked keys
+// can't be changed.
+func (a *ApiKeyService) UpdateApiKeyScopes(ctx context.Context, id uint, scopes *model.ApiKeyScopesForm) (err error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.UpdateApiKeyScopes")
+	defer func() { endSpan(span, err) }()
+
+	return a.apiKeyRepo.UpdateApiKeyScopes(ctx, id, strings.Join(scopes.Scopes, " "))
+}
+
+// RevokeApiKey revokes the key for good; revoking it again has no effect.
+func (a *ApiKeyService) RevokeApiKey(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.RevokeApiKey")
+	defer func() { endSpan(span, err) }()
+
+	return a.apiKeyRepo.RevokeApiKey(ctx, id, a.now())
+}
+
+// Authenticate returns the API key of key, unless it's unknown or revoked,
+// and records its use.
+func (a *ApiKeyService) Authenticate(ctx context.Context, key string) (_ *model.ApiKey, err error) {
+	ctx, span := tracer.Start(ctx, "ApiKeyService.Authenticate")
+	defer func() { endSpan(span, err) }()
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return nil, apperror.Unauthorized("invalid api key", nil)
+	}
+
+	keyModel, err := a.apiKeyRepo.ReadApiKeyByHash(ctx, hashApiKey(key))
+	if errors.Is(err, apperror.ErrNotFound) {
+		return nil, apperror.Unauthorized("invalid api key", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if keyModel.RevokedAt != nil {
+		return nil, apperror.Unauthorized("api key revoked", nil)
+	}
+
+	now := a.now()
+	if keyModel.LastUsedAt == nil || now.Sub(*keyModel.LastUsedAt) >= apiKeyTouchInterval {
+		// The request goes on without it: the last use is only informative.
+		if err := a.apiKeyRepo.TouchApiKey(ctx, keyModel.ID, now); err != nil {
+			logger.Ctx(ctx).Warn().Err(err).Uint("api_key", keyModel.ID).Msg("API key use not recorded")
+		} else {
+			keyModel.LastUsedAt = &now
+		}
+	}
+
+	return keyModel, nil
+}
+
+// hashApiKey returns the SHA-256 of key, hex encoded. Keys are random, so a
+// fast hash is enough to keep them from being recovered from the database.
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
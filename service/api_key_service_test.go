@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"myapp/model"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	mock_repository "myapp/mocks/repository"
+)
+
+func TestApiKeyService_CreateApiKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	var stored *model.ApiKey
+	mockRepo := mock_repository.NewMockApiKeyRepoInterface(ctrl)
+	mockRepo.EXPECT().CreateApiKey(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key *model.ApiKey) (*model.ApiKey, error) {
+			key.ID = 1
+			stored = key
+			return key, nil
+		})
+
+	svc := NewApiKeyService(mockRepo)
+
+	got, err := svc.CreateApiKey(actor.NewContext(context.Background(), "alice"), &model.ApiKeyForm{Name: "nightly import", Scopes: []string{"books:read", "books:write"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, strings.HasPrefix(got.Key, "mk_"))
+	assert.Equal(t, got.Key[:11], got.Prefix)
+	assert.Equal(t, uint(1), got.ID)
+	assert.Equal(t, "nightly import", got.Name)
+	assert.Equal(t, []string{"books:read", "books:write"}, got.Scopes)
+	assert.Equal(t, "alice", got.CreatedBy)
+
+	assert.Equal(t, hashApiKey(got.Key), stored.KeyHash)
+	assert.NotContains(t, stored.KeyHash, got.Key)
+	assert.Equal(t, "books:read books:write", stored.Scopes)
+}
+
+func TestApiKeyService_Authenticate(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	recently := now.Add(-10 * time.Second)
+	lastHour := now.Add(-time.Hour)
+	key := "mk_secret"
+
+	tests := []struct {
+		name        string
+		key         string
+		want        *model.ApiKey
+		wantErr     string
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockApiKeyRepoInterface)
+	}{
+		{
+			name: "first use",
+			key:  key,
+			want: &model.ApiKey{Model: gorm.Model{ID: 1}, Scopes: "books:read", LastUsedAt: &now},
+			prepareMock: func(mockRepo *mock_repository.MockApiKeyRepoInterface) {
+				mockRepo.EXPECT().ReadApiKeyByHash(gomock.Any(), hashApiKey(key)).Return(&model.ApiKey{Model: gorm.Model{ID: 1}, Scopes: "books:read"}, nil)
+				mockRepo.EXPECT().TouchApiKey(gomock.Any(), uint(1), now).Return(nil)
+			},
+		},
+		{
+			name: "used recently",
+			key:  key,
+			want: &model.ApiKey{Model: gorm.Model{ID: 1}, LastUsedAt: &recently},
+			prepareMock: func(mockRepo *mock_repository.MockApiKeyRepoInterface) {
+				mockRepo.EXPECT().ReadApiKeyByHash(gomock.Any(), hashApiKey(key)).Return(&model.ApiKey{Model: gorm.Model{ID: 1}, LastUsedAt: &recently}, nil)
+			},
+		},
+		{
+			name: "use not recorded",
+			key:  key,
+			want: &model.ApiKey{Model: gorm.Model{ID: 1}, LastUsedAt: &lastHour},
+			prepareMock: func(mockRepo *mock_repository.MockApiKeyRepoInterface) {
+				mockRepo.EXPECT().ReadApiKeyByHash(gomock.Any(), hashApiKey(key)).Return(&model.ApiKey{Model: gorm.Model{ID: 1}, LastUsedAt: &lastHour}, nil)
+				mockRepo.EXPECT().TouchApiKey(gomock.Any(), uint(1), now).Return(errors.New("connection lost"))
+			},
+		},
+		{
+			name:        "malformed key",
+			key:         "secret",
+			wantErr:     "invalid api key",
+			wantErrIs:   apperror.ErrUnauthorized,
+			prepareMock: func(mockRepo *mock_repository.MockApiKeyRepoInterface) {},
+		},
+		{
+			name:      "unknown key",
+			key:       key,
+			wantErr:   "invalid api key",
+			wantErrIs: apperror.ErrUnauthorized,
+			prepareMock: func(mockRepo *mock_repository.MockApiKeyRepoInterface) {
+				mockRepo.EXPECT().ReadApiKeyByHash(gomock.Any(), hashApiKey(key)).Return(nil, apperror.NotFound("api key not found", nil))
+			},
+		},
+		{
+			name:      "revoked key",
+			key:       key,
+			wantErr:   "api key revoked",
+			wantErrIs: apperror.ErrUnauthorized,
+			prepareMock: func(mockRepo *mock_repository.MockApiKeyRepoInterface) {
+				mockRepo.EXPECT().ReadApiKeyByHash(gomock.Any(), hashApiKey(key)).Return(&model.ApiKey{Model: gorm.Model{ID: 1}, RevokedAt: &lastHour}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockApiKeyRepoInterface(ctrl)
+			tt.prepareMock(mockRepo)
+
+			svc := NewApiKeyService(mockRepo)
+			svc.now = func() time.Time { return now }
+
+			got, err := svc.Authenticate(context.Background(), tt.key)
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/repository"
+)
+
+type AuthorService struct {
+	authorRepo repository.AuthorRepoInterface
+}
+
+func NewAuthorService(authorRepo repository.AuthorRepoInterface) *AuthorService {
+	return &AuthorService{authorRepo: authorRepo}
+}
+
+type AuthorServiceInterface interface {
+	CreateAuthor(ctx context.Context, author *model.AuthorForm) (*model.AuthorDto, error)
+	GetAuthorByID(ctx context.Context, id uint) (*model.AuthorDto, error)
+	GetListAuthor(ctx context.Context, query *model.PageQuery) (*model.AuthorListDto, error)
+	UpdateAuthor(ctx context.Context, id uint, author *model.AuthorForm) (*model.AuthorDto, error)
+	DeleteAuthor(ctx context.Context, id uint) error
+	GetAuthorBooks(ctx context.Context, id uint, query *model.PageQuery) (*model.AuthorBookListDto, error)
+}
+
+func (a *AuthorService) CreateAuthor(ctx context.Context, author *model.AuthorForm) (_ *model.AuthorDto, err error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.CreateAuthor")
+	defer func() { endSpan(span, err) }()
+
+	authorModel, err := a.authorRepo.CreateAuthor(ctx, author.ToModel())
+	if err != nil {
+		return &model.AuthorDto{}, err
+	}
+
+	return authorModel.ToDto(), nil
+}
+
+func (a *AuthorService) GetAuthorByID(ctx context.Context, id uint) (_ *model.AuthorDto, err error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.GetAuthorByID")
+	defer func() { endSpan(span, err) }()
+
+	author, err := a.authorRepo.ReadAuthor(ctx, id)
+	if err != nil {
+		return &model.AuthorDto{}, err
+	}
+
+	return author.ToDto(), nil
+}
+
+func (a *AuthorService) GetListAuthor(ctx context.Context, query *model.PageQuery) (_ *model.AuthorListDto, err error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.GetListAuthor")
+	defer func() { endSpan(span, err) }()
+
+	authors, total, err := a.authorRepo.ListAuthors(ctx, query)
+	if err != nil {
+		return &model.AuthorListDto{}, err
+	}
+
+	return &model.AuthorListDto{
+		Data:   authors.ToDto(),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}, nil
+}
+
+func (a *AuthorService) UpdateAuthor(ctx context.Context, id uint, author *model.AuthorForm) (_ *model.AuthorDto, err error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.UpdateAuthor")
+	defer func() { endSpan(span, err) }()
+
+	authorModel := author.ToModel()
+	authorModel.ID = id
+	if err := a.authorRepo.UpdateAuthor(ctx, authorModel); err != nil {
+		return &model.AuthorDto{}, err
+	}
+
+	return authorModel.ToDto(), nil
+}
+
+// DeleteAuthor deletes the author; authors of books can't be deleted.
+func (a *AuthorService) DeleteAuthor(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.DeleteAuthor")
+	defer func() { endSpan(span, err) }()
+
+	return a.authorRepo.DeleteAuthor(ctx, id)
+}
+
+// GetAuthorBooks returns a page of the books of the author.
+func (a *AuthorService) GetAuthorBooks(ctx context.Context, id uint, query *model.PageQuery) (_ *model.AuthorBookListDto, err error) {
+	ctx, span := tracer.Start(ctx, "AuthorService.GetAuthorBooks")
+	defer func() { endSpan(span, err) }()
+
+	// An unknown author isn't an author without books.
+	if _, err := a.authorRepo.ReadAuthor(ctx, id); err != nil {
+		return &model.AuthorBookListDto{}, err
+	}
+
+	books, total, err := a.authorRepo.ListAuthorBooks(ctx, id, query)
+	if err != nil {
+		return &model.AuthorBookListDto{}, err
+	}
+
+	return &model.AuthorBookListDto{
+		Data:   books.ToDto(),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"myapp/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	mock_repository "myapp/mocks/repository"
+	"myapp/util/apperror"
+)
+
+func TestAuthorService_CreateAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockAuthorRepoInterface(ctrl)
+	mockRepo.EXPECT().CreateAuthor(gomock.Any(), &model.Author{Name: "J. R. R. Tolkien"}).DoAndReturn(func(ctx context.Context, author *model.Author) (*model.Author, error) {
+		author.ID = 1
+		return author, nil
+	})
+
+	svc := NewAuthorService(mockRepo)
+
+	got, err := svc.CreateAuthor(context.Background(), &model.AuthorForm{Name: "J. R. R. Tolkien"})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.AuthorDto{ID: 1, Name: "J. R. R. Tolkien"}, got)
+}
+
+func TestAuthorService_UpdateAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockAuthorRepoInterface(ctrl)
+	mockRepo.EXPECT().UpdateAuthor(gomock.Any(), &model.Author{Model: gorm.Model{ID: 1}, Name: "Tolkien"}).Return(nil)
+
+	svc := NewAuthorService(mockRepo)
+
+	got, err := svc.UpdateAuthor(context.Background(), 1, &model.AuthorForm{Name: "Tolkien"})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.AuthorDto{ID: 1, Name: "Tolkien"}, got)
+}
+
+func TestAuthorService_GetListAuthor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockAuthorRepoInterface(ctrl)
+	mockRepo.EXPECT().ListAuthors(gomock.Any(), &model.PageQuery{Limit: 1, Offset: 1}).
+		Return(model.Authors{{Model: gorm.Model{ID: 2}, Name: "Ursula K. Le Guin"}}, int64(2), nil)
+
+	svc := NewAuthorService(mockRepo)
+
+	got, err := svc.GetListAuthor(context.Background(), &model.PageQuery{Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.AuthorListDto{
+		Data:   []model.AuthorDto{{ID: 2, Name: "Ursula K. Le Guin"}},
+		Total:  2,
+		Limit:  1,
+		Offset: 1,
+	}, got)
+}
+
+func TestAuthorService_GetAuthorBooks(t *testing.T) {
+	tests := []struct {
+		name        string
+		want        *model.AuthorBookListDto
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockAuthorRepoInterface)
+	}{
+		{
+			name: "success call",
+			want: &model.AuthorBookListDto{
+				Data:  []model.BookDto{{ID: 3, Title: "The Hobbit", PublishedDate: "0001-01-01"}},
+				Total: 1,
+				Limit: 10,
+			},
+			prepareMock: func(mockRepo *mock_repository.MockAuthorRepoInterface) {
+				mockRepo.EXPECT().ReadAuthor(gomock.Any(), uint(1)).Return(&model.Author{Model: gorm.Model{ID: 1}}, nil)
+				mockRepo.EXPECT().ListAuthorBooks(gomock.Any(), uint(1), &model.PageQuery{Limit: 10}).
+					Return(model.Books{{Model: gorm.Model{ID: 3}, Title: "The Hobbit"}}, int64(1), nil)
+			},
+		},
+		{
+			name:      "unknown author",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockAuthorRepoInterface) {
+				mockRepo.EXPECT().ReadAuthor(gomock.Any(), uint(1)).Return(nil, apperror.NotFound("author not found", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockAuthorRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+
+			svc := NewAuthorService(mockRepo)
+
+			got, err := svc.GetAuthorBooks(context.Background(), 1, &model.PageQuery{Limit: 10})
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
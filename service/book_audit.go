@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"myapp/util/policy"
+	"myapp/util/requestid"
+)
+
+// audited runs change in a transaction of repo together with the audit
+// entries it returns, so that no change is committed without its audit trail.
+func audited(ctx context.Context, repo repository.BookRepoInterface, change func(tx repository.BookRepoInterface) ([]*model.BookAudit, error)) error {
+	return repo.Transaction(ctx, func(tx repository.BookRepoInterface) error {
+		audits, err := change(tx)
+		if err != nil || len(audits) == 0 {
+			return err
+		}
+
+		return tx.CreateAudits(ctx, audits)
+	})
+}
+
+// newAudit records action on a book by the actor of the request.
+func newAudit(ctx context.Context, action model.BookAuditAction, bookID uint, before, after *model.Book) *model.BookAudit {
+	audit := &model.BookAudit{
+		BookID:    bookID,
+		Action:    action,
+		Actor:     actor.FromContext(ctx),
+		RequestID: requestid.FromContext(ctx),
+	}
+	if before != nil {
+		audit.Before = before.Snapshot()
+	}
+	if after != nil {
+		audit.After = after.Snapshot()
+	}
+
+	return audit
+}
+
+// updateBook replaces the book in tx, when versions are given only if it's
+// still at one of them. book.Version is set to the new version.
+func updateBook(ctx context.Context, tx repository.BookRepoInterface, book *model.Book, versions []uint) ([]*model.BookAudit, error) {
+	before, err := tx.LockBook(ctx, book.ID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeBook(ctx, before, policy.BooksWrite); err != nil {
+		return nil, err
+	}
+
+	if !matchesVersion(before.Version, versions) {
+		return nil, apperror.PreconditionFailed("book has been modified", nil)
+	}
+
+	book.Version = before.Version
+	book.CreatedAt = before.CreatedAt
+	book.CoverType = before.CoverType
+	book.CoverKey = before.CoverKey
+	book.CreatedBy = before.CreatedBy
+	keepImageURL(book, before)
+	if err := tx.UpdateBook(ctx, book); err != nil {
+		return nil, err
+	}
+
+	if err := setAuthors(ctx, tx, book, before); err != nil {
+		return nil, err
+	}
+
+	return []*model.BookAudit{newAudit(ctx, model.BookAuditUpdate, book.ID, before, book)}, nil
+}
+
+// deleteBook deletes the book in tx, when versions are given only if it's
+// still at one of them.
+func deleteBook(ctx context.Context, tx repository.BookRepoInterface, id uint, versions []uint) ([]*model.BookAudit, error) {
+	before, err := tx.LockBook(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeBook(ctx, before, policy.BooksDelete); err != nil {
+		return nil, err
+	}
+
+	if !matchesVersion(before.Version, versions) {
+		return nil, apperror.PreconditionFailed("book has been modified", nil)
+	}
+
+	if err := tx.DeleteBook(ctx, id, before.Version); err != nil {
+		return nil, err
+	}
+
+	return []*model.BookAudit{newAudit(ctx, model.BookAuditDelete, id, before, nil)}, nil
+}
+
+// matchesVersion reports whether a book at version is at one of versions, any
+// version matching when there are none.
+func matchesVersion(version uint, versions []uint) bool {
+	if len(versions) == 0 {
+		return true
+	}
+
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetBookHistory returns a page of the audit trail of the book, most recent
+// change first. The book may have been deleted, even purged, since.
+func (b *BookService) GetBookHistory(ctx context.Context, id uint, query *model.PageQuery) (_ *model.BookHistoryDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.GetBookHistory")
+	defer func() { endSpan(span, err) }()
+
+	audits, total, err := b.bookRepo.ListAudits(ctx, id, query)
+	if err != nil {
+		return &model.BookHistoryDto{}, err
+	}
+
+	// Books created before the audit trail have none: tell them from unknown books.
+	if total == 0 {
+		if _, err := b.bookRepo.ReadBook(ctx, id); err != nil {
+			return &model.BookHistoryDto{}, err
+		}
+	}
+
+	history := &model.BookHistoryDto{
+		Data:   make([]model.BookAuditDto, 0, len(audits)),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+	for _, a := range audits {
+		history.Data = append(history.Data, a.ToDto())
+	}
+
+	return history, nil
+}
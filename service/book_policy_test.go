@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"myapp/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	mock_repository "myapp/mocks/repository"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"myapp/util/policy"
+	"myapp/util/principal"
+	"myapp/util/validator"
+)
+
+// withPrincipal returns a context of a request of subject with the roles of
+// the default policy.
+func withPrincipal(subject string, roles ...string) context.Context {
+	ctx := principal.NewContext(context.Background(), &principal.Principal{
+		Subject:     subject,
+		Roles:       roles,
+		Permissions: policy.Default.Permissions(roles),
+	})
+
+	return actor.NewContext(ctx, subject)
+}
+
+func TestBookService_Authorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		createdBy string
+		delete    bool
+		wantErrIs error
+	}{
+		{name: "editor updates own book", ctx: withPrincipal("alice", policy.RoleEditor), createdBy: "alice"},
+		{name: "editor updates book of another", ctx: withPrincipal("alice", policy.RoleEditor), createdBy: "bob", wantErrIs: apperror.ErrForbidden},
+		{name: "editor updates book of unknown creator", ctx: withPrincipal("alice", policy.RoleEditor), wantErrIs: apperror.ErrForbidden},
+		{name: "admin updates book of another", ctx: withPrincipal("carol", policy.RoleAdmin), createdBy: "bob"},
+		{name: "reader updates", ctx: withPrincipal("dave", policy.RoleReader), createdBy: "dave", wantErrIs: apperror.ErrForbidden},
+		{name: "editor deletes own book", ctx: withPrincipal("alice", policy.RoleEditor), createdBy: "alice", delete: true, wantErrIs: apperror.ErrForbidden},
+		{name: "admin deletes book of another", ctx: withPrincipal("carol", policy.RoleAdmin), createdBy: "bob", delete: true},
+		{name: "without authentication", ctx: context.Background(), createdBy: "bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+			mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, CreatedBy: tt.createdBy, Version: 2}, nil)
+			if tt.wantErrIs == nil {
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(1)
+				mockRepo.EXPECT().DeleteBook(gomock.Any(), uint(1), uint(2)).Return(nil).MaxTimes(1)
+			}
+			expectTransactions(mockRepo)
+
+			b := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			var err error
+			if tt.delete {
+				err = b.DeleteBook(tt.ctx, 1, nil)
+			} else {
+				var book *model.BookDto
+				book, err = b.UpdateBook(tt.ctx, 1, bookForm, nil)
+				if err == nil {
+					assert.Equal(t, tt.createdBy, book.CreatedBy, "creator kept")
+				}
+			}
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBookService_CreateBookCreator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+	mockRepo.EXPECT().CreateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) (*model.Book, error) {
+		assert.Equal(t, "alice", book.CreatedBy)
+		book.ID = 1
+		return book, nil
+	})
+	expectTransactions(mockRepo)
+
+	b := NewBookService(mockRepo, nil, validator.New(), 10)
+
+	book, err := b.CreateBook(withPrincipal("alice", policy.RoleEditor), bookForm)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "alice", book.CreatedBy)
+	}
+}
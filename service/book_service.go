@@ -1,85 +1,903 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"myapp/adapter/blob"
 	"myapp/model"
 	"myapp/repository"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"myapp/util/highlight"
+	"myapp/util/isbn"
+	"myapp/util/logger"
+	"myapp/util/policy"
+	vr "myapp/util/validator"
+	"strings"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"gopkg.in/go-playground/validator.v9"
 )
 
+// snippetWidth is the number of characters shown around a search match.
+const snippetWidth = 160
+
+// maxImportErrors bounds the invalid fields reported for an import file.
+const maxImportErrors = 100
+
+// importBatchSize bounds the books created by each step of ImportBooks, so
+// that each runs within the query timeout whatever the size of the file.
+const importBatchSize = 500
+
+// purgeBatchSize bounds the books purged by each transaction of
+// PurgeDeletedBooks, so a large backlog doesn't hold locks for long.
+const purgeBatchSize = 1000
+
 type BookService struct {
-	bookRepo repository.BookRepoInterface
+	bookRepo     repository.BookRepoInterface
+	covers       blob.Store
+	validator    *validator.Validate
+	maxBatchSize int
 }
 
-func NewBookService(bookRepo repository.BookRepoInterface) *BookService {
-	return &BookService{bookRepo: bookRepo}
+// NewBookService returns the service of the books, whose uploaded covers are
+// kept in covers; those of the purged books are removed.
+func NewBookService(bookRepo repository.BookRepoInterface, covers blob.Store, validator *validator.Validate, maxBatchSize int) *BookService {
+	return &BookService{bookRepo: bookRepo, covers: covers, validator: validator, maxBatchSize: maxBatchSize}
 }
 
 type BookServiceInterface interface {
 	CreateBook(ctx context.Context, book *model.BookForm) (*model.BookDto, error)
 	GetBookByID(ctx context.Context, id uint) (*model.BookDto, error)
-	GetListBook(ctx context.Context) ([]model.BookDto, error)
-	UpdateBook(ctx context.Context, id uint, book *model.BookForm) error
-	DeleteBook(ctx context.Context, id uint) error
+	GetBookByISBN(ctx context.Context, isbn string) (*model.BookDto, error)
+	GetListBook(ctx context.Context, query *model.BookQuery) (*model.BookListDto, error)
+	SearchBooks(ctx context.Context, query *model.BookSearchQuery) (*model.BookSearchListDto, error)
+	UpdateBook(ctx context.Context, id uint, book *model.BookForm, versions []uint) (*model.BookDto, error)
+	PatchBook(ctx context.Context, id uint, versions []uint, format model.PatchFormat, patch []byte) (*model.BookDto, error)
+	DeleteBook(ctx context.Context, id uint, versions []uint) error
+	BatchBooks(ctx context.Context, batch *model.BookBatchForm) (*model.BookBatchResult, error)
+	ExportBooks(ctx context.Context, fn func(book *model.BookDto) error) error
+	ImportBooks(ctx context.Context, rows []*model.BookImportRow, dryRun bool) (*model.BookImportResult, error)
+	ListDeletedBooks(ctx context.Context, query *model.PageQuery) (*model.BookTrashListDto, error)
+	RestoreBook(ctx context.Context, id uint) (*model.BookDto, error)
+	PurgeBook(ctx context.Context, id uint) error
+	PurgeDeletedBooks(ctx context.Context, before time.Time) (int64, error)
+	GetBookHistory(ctx context.Context, id uint, query *model.PageQuery) (*model.BookHistoryDto, error)
 }
 
-func (b *BookService) CreateBook(ctx context.Context, book *model.BookForm) (*model.BookDto, error) {
+func (b *BookService) CreateBook(ctx context.Context, book *model.BookForm) (_ *model.BookDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.CreateBook")
+	defer func() { endSpan(span, err) }()
+
 	bookModel, err := book.ToModel()
 	if err != nil {
-		return &model.BookDto{}, err
+		return &model.BookDto{}, apperror.Validation("invalid book form", err, apperror.FieldError{Name: "published_date", Reason: "must be a valid date"})
 	}
 
-	respBook, err := b.bookRepo.CreateBook(ctx, bookModel)
+	bookModel.CreatedBy = actor.FromContext(ctx)
+	keepImageURL(bookModel, nil)
+	err = audited(ctx, b.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		if _, err := tx.CreateBook(ctx, bookModel); err != nil {
+			return nil, err
+		}
+
+		if err := setAuthors(ctx, tx, bookModel, nil); err != nil {
+			return nil, err
+		}
+
+		return []*model.BookAudit{newAudit(ctx, model.BookAuditCreate, bookModel.ID, nil, bookModel)}, nil
+	})
 	if err != nil {
 		return &model.BookDto{}, err
 	}
 
-	resp := respBook.ToDto()
+	resp := bookModel.ToDto()
 
 	return resp, nil
 }
 
-func (b *BookService) GetBookByID(ctx context.Context, id uint) (*model.BookDto, error) {
+func (b *BookService) GetBookByID(ctx context.Context, id uint) (_ *model.BookDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.GetBookByID")
+	defer func() { endSpan(span, err) }()
+
 	book, err := b.bookRepo.ReadBook(ctx, id)
 	if err != nil {
 		return &model.BookDto{}, err
 	}
 
+	if book.AuthorIDs, err = b.bookRepo.BookAuthorIDs(ctx, id); err != nil {
+		return &model.BookDto{}, err
+	}
+
 	bookDto := book.ToDto()
 
 	return bookDto, nil
 }
 
-func (b *BookService) GetListBook(ctx context.Context) ([]model.BookDto, error) {
-	books, err := b.bookRepo.ListBooks(ctx)
+// GetBookByISBN returns the book with the ISBN, which may be an ISBN-10.
+func (b *BookService) GetBookByISBN(ctx context.Context, code string) (_ *model.BookDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.GetBookByISBN")
+	defer func() { endSpan(span, err) }()
+
+	isbn13, err := isbn.Normalize(code)
+	if err != nil {
+		return &model.BookDto{}, apperror.Validation("invalid ISBN", err, apperror.FieldError{Name: "isbn", Reason: "must be a valid ISBN-10 or ISBN-13"})
+	}
+
+	book, err := b.bookRepo.ReadBookByISBN(ctx, isbn13)
+	if err != nil {
+		return &model.BookDto{}, err
+	}
+
+	if book.AuthorIDs, err = b.bookRepo.BookAuthorIDs(ctx, book.ID); err != nil {
+		return &model.BookDto{}, err
+	}
+
+	return book.ToDto(), nil
+}
+
+func (b *BookService) GetListBook(ctx context.Context, query *model.BookQuery) (_ *model.BookListDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.GetListBook")
+	defer func() { endSpan(span, err) }()
+
+	logger.Ctx(ctx).Debug().Int("limit", query.Limit).Int("offset", query.Offset).Bool("cursor", query.Cursor != nil).Msg("Listing books")
+
+	books, total, err := b.bookRepo.ListBooks(ctx, query)
+	if err != nil {
+		return &model.BookListDto{}, err
+	}
+
+	hasMore := len(books) > query.Limit
+	if hasMore {
+		if query.Cursor != nil && query.Cursor.Backward {
+			books = books[1:]
+		} else {
+			books = books[:query.Limit]
+		}
+	}
+
+	list := &model.BookListDto{
+		Data:   books.ToDto(),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	if len(books) == 0 {
+		return list, nil
+	}
+
+	backward := query.Cursor != nil && query.Cursor.Backward
+	if hasMore || backward {
+		list.NextCursor = query.CursorFor(books[len(books)-1], false).Encode()
+	}
+	if (hasMore && backward) || (query.Cursor != nil && !backward) || query.Offset > 0 {
+		list.PrevCursor = query.CursorFor(books[0], true).Encode()
+	}
+
+	return list, nil
+}
+
+func (b *BookService) SearchBooks(ctx context.Context, query *model.BookSearchQuery) (_ *model.BookSearchListDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.SearchBooks")
+	defer func() { endSpan(span, err) }()
+
+	logger.Ctx(ctx).Debug().Str("q", query.Q).Int("limit", query.Limit).Int("offset", query.Offset).Msg("Searching books")
+
+	hits, total, err := b.bookRepo.SearchBooks(ctx, query)
 	if err != nil {
-		return []model.BookDto{}, err
+		return &model.BookSearchListDto{}, err
 	}
 
-	booksDto := books.ToDto()
+	terms := query.Terms()
 
-	return booksDto, nil
+	list := &model.BookSearchListDto{
+		Data:   make([]model.BookSearchHitDto, 0, len(hits)),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+
+	for _, hit := range hits {
+		dto := model.BookSearchHitDto{
+			BookDto:    *hit.Book.ToDto(),
+			Score:      hit.Score,
+			Highlights: map[string]string{},
+		}
+
+		for field, text := range map[string]string{
+			"title":       hit.Title,
+			"author":      hit.Author,
+			"description": hit.Description,
+		} {
+			if snippet, ok := highlight.Snippet(text, terms, snippetWidth); ok {
+				dto.Highlights[field] = snippet
+			}
+		}
+
+		list.Data = append(list.Data, dto)
+	}
+
+	return list, nil
 }
 
-func (b *BookService) UpdateBook(ctx context.Context, id uint, book *model.BookForm) error {
+// UpdateBook replaces the book. When versions are given the book is only
+// replaced if it's still at one of them. The returned book carries the new
+// version.
+func (b *BookService) UpdateBook(ctx context.Context, id uint, book *model.BookForm, versions []uint) (_ *model.BookDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.UpdateBook")
+	defer func() { endSpan(span, err) }()
+
 	bookModel, err := book.ToModel()
 	if err != nil {
-		return err
+		return &model.BookDto{}, apperror.Validation("invalid book form", err, apperror.FieldError{Name: "published_date", Reason: "must be a valid date"})
 	}
 
 	bookModel.ID = id
-	err = b.bookRepo.UpdateBook(ctx, bookModel)
+	err = audited(ctx, b.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		return updateBook(ctx, tx, bookModel, versions)
+	})
+	if err != nil {
+		return &model.BookDto{}, err
+	}
+
+	return bookModel.ToDto(), nil
+}
+
+// PatchBook applies patch to the current book and saves the result once it
+// validates like a full update would. When versions are given the book is
+// only patched if it's still at one of them.
+func (b *BookService) PatchBook(ctx context.Context, id uint, versions []uint, format model.PatchFormat, patch []byte) (_ *model.BookDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.PatchBook")
+	defer func() { endSpan(span, err) }()
+
+	var bookModel *model.Book
+	err = audited(ctx, b.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		book, err := tx.LockBook(ctx, id, false)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := authorizeBook(ctx, book, policy.BooksWrite); err != nil {
+			return nil, err
+		}
+
+		if !matchesVersion(book.Version, versions) {
+			return nil, apperror.PreconditionFailed("book has been modified", nil)
+		}
+
+		// The patch may address the authors, as read.
+		if book.AuthorIDs, err = tx.BookAuthorIDs(ctx, id); err != nil {
+			return nil, err
+		}
+
+		bookModel, err = patchedBook(book, format, patch, b.validator)
+		if err != nil {
+			return nil, err
+		}
+
+		// The book is locked: it is still at the version the patch was applied to.
+		bookModel.ID = id
+		bookModel.CreatedAt = book.CreatedAt
+		bookModel.CoverType = book.CoverType
+		bookModel.CoverKey = book.CoverKey
+		bookModel.CreatedBy = book.CreatedBy
+		if err := tx.UpdateBook(ctx, bookModel); err != nil {
+			return nil, err
+		}
+
+		if err := setAuthors(ctx, tx, bookModel, book); err != nil {
+			return nil, err
+		}
+
+		return []*model.BookAudit{newAudit(ctx, model.BookAuditUpdate, id, book, bookModel)}, nil
+	})
+	if err != nil {
+		return &model.BookDto{}, err
+	}
+
+	return bookModel.ToDto(), nil
+}
+
+// patchedBook applies patch to book and returns the result once it validates
+// like a full update would. The result is at the version of book.
+func patchedBook(book *model.Book, format model.PatchFormat, patch []byte, v *validator.Validate) (*model.Book, error) {
+	doc, err := json.Marshal(book.ToForm())
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := applyPatch(format, doc, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	form := &model.BookForm{}
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(form); err != nil {
+		return nil, apperror.Validation("invalid patched book", err)
+	}
+
+	if err := v.Struct(form); err != nil {
+		return nil, apperror.Validation("invalid patched book", err, vr.ToFieldErrors(err)...)
+	}
+
+	bookModel, err := form.ToModel()
+	if err != nil {
+		return nil, apperror.Validation("invalid patched book", err, apperror.FieldError{Name: "published_date", Reason: "must be a valid date"})
+	}
+
+	bookModel.Version = book.Version
+	keepImageURL(bookModel, book)
+
+	return bookModel, nil
+}
+
+func applyPatch(format model.PatchFormat, doc, patch []byte) ([]byte, error) {
+	switch format {
+	case model.PatchMerge:
+		patched, err := jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, apperror.Validation("invalid merge patch", err)
+		}
+
+		return patched, nil
+	case model.PatchJSON:
+		p, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, apperror.Validation("invalid JSON patch", err)
+		}
+
+		patched, err := p.Apply(doc)
+		if errors.Is(err, jsonpatch.ErrTestFailed) {
+			return nil, apperror.Conflict("JSON patch test failed", err)
+		}
+		if err != nil {
+			return nil, apperror.Validation("JSON patch can't be applied", err)
+		}
+
+		return patched, nil
+	default:
+		return nil, apperror.Validation(fmt.Sprintf("unsupported patch format %q", format), nil)
+	}
+}
+
+// DeleteBook deletes the book; when versions are given only if it's still at
+// one of them.
+func (b *BookService) DeleteBook(ctx context.Context, id uint, versions []uint) (err error) {
+	ctx, span := tracer.Start(ctx, "BookService.DeleteBook")
+	defer func() { endSpan(span, err) }()
+
+	return audited(ctx, b.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		return deleteBook(ctx, tx, id, versions)
+	})
+}
+
+// ListDeletedBooks returns a page of the books in the trash.
+func (b *BookService) ListDeletedBooks(ctx context.Context, query *model.PageQuery) (_ *model.BookTrashListDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.ListDeletedBooks")
+	defer func() { endSpan(span, err) }()
+
+	books, total, err := b.bookRepo.ListDeletedBooks(ctx, query)
+	if err != nil {
+		return &model.BookTrashListDto{}, err
+	}
+
+	return &model.BookTrashListDto{
+		Data:   books.ToDeletedDto(),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}, nil
+}
+
+// RestoreBook moves the book out of the trash and returns it with its new version.
+func (b *BookService) RestoreBook(ctx context.Context, id uint) (_ *model.BookDto, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.RestoreBook")
+	defer func() { endSpan(span, err) }()
+
+	var book *model.Book
+	err = audited(ctx, b.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		if err := tx.RestoreBook(ctx, id); err != nil {
+			return nil, err
+		}
+
+		if book, err = tx.ReadBook(ctx, id); err != nil {
+			return nil, err
+		}
+
+		return []*model.BookAudit{newAudit(ctx, model.BookAuditRestore, id, nil, book)}, nil
+	})
+	if err != nil {
+		return &model.BookDto{}, err
+	}
+
+	return book.ToDto(), nil
+}
+
+// PurgeBook permanently deletes a book of the trash.
+func (b *BookService) PurgeBook(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "BookService.PurgeBook")
+	defer func() { endSpan(span, err) }()
+
+	var coverKey string
+	err = audited(ctx, b.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		before, err := tx.LockBook(ctx, id, true)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.PurgeBooks(ctx, []uint{id}); err != nil {
+			return nil, err
+		}
+		coverKey = before.CoverKey
+
+		return []*model.BookAudit{newAudit(ctx, model.BookAuditPurge, id, before, nil)}, nil
+	})
 	if err != nil {
 		return err
 	}
 
+	if coverKey != "" {
+		deleteCoverBlobs(ctx, b.covers, coverKey)
+	}
+
 	return nil
 }
 
-func (b *BookService) DeleteBook(ctx context.Context, id uint) error {
-	err := b.bookRepo.DeleteBook(ctx, id)
+// PurgeDeletedBooks permanently deletes the books which have been in the
+// trash since before the given time, and returns how many were removed.
+func (b *BookService) PurgeDeletedBooks(ctx context.Context, before time.Time) (_ int64, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.PurgeDeletedBooks")
+	defer func() { endSpan(span, err) }()
+
+	var purged int64
+	for {
+		n := 0
+		var coverKeys []string
+		err = audited(ctx, b.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+			books, err := tx.LockDeletedBooks(ctx, before, purgeBatchSize)
+			if err != nil || len(books) == 0 {
+				return nil, err
+			}
+
+			ids := make([]uint, 0, len(books))
+			audits := make([]*model.BookAudit, 0, len(books))
+			coverKeys = coverKeys[:0]
+			for _, book := range books {
+				ids = append(ids, book.ID)
+				audits = append(audits, newAudit(ctx, model.BookAuditPurge, book.ID, book, nil))
+				if book.CoverKey != "" {
+					coverKeys = append(coverKeys, book.CoverKey)
+				}
+			}
+
+			if _, err := tx.PurgeBooks(ctx, ids); err != nil {
+				return nil, err
+			}
+			n = len(books)
+
+			return audits, nil
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		for _, coverKey := range coverKeys {
+			deleteCoverBlobs(ctx, b.covers, coverKey)
+		}
+
+		purged += int64(n)
+		if n < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// BatchBooks applies the operations of batch and reports the outcome of each.
+// Creates are inserted together, then updates and deletes are applied in order.
+// In atomic mode nothing is applied unless every operation succeeds; in best
+// effort mode creates which fail together are retried one by one.
+func (b *BookService) BatchBooks(ctx context.Context, batch *model.BookBatchForm) (_ *model.BookBatchResult, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.BatchBooks")
+	defer func() { endSpan(span, err) }()
+
+	mode := batch.Mode
+	if mode == "" {
+		mode = model.BookBatchAtomic
+	}
+	if mode != model.BookBatchAtomic && mode != model.BookBatchBestEffort {
+		return nil, apperror.Validation("invalid batch", nil, apperror.FieldError{Name: "mode", Reason: "must be one of atomic, best_effort"})
+	}
+
+	ops := batch.Operations
+	if len(ops) == 0 {
+		return nil, apperror.Validation("invalid batch", nil, apperror.FieldError{Name: "operations", Reason: "is required"})
+	}
+	if len(ops) > b.maxBatchSize {
+		return nil, apperror.Validation("invalid batch", nil, apperror.FieldError{Name: "operations", Reason: fmt.Sprintf("must contain at most %d items", b.maxBatchSize)})
+	}
+
+	logger.Ctx(ctx).Debug().Str("mode", string(mode)).Int("operations", len(ops)).Msg("Applying book batch")
+
+	result := &model.BookBatchResult{
+		Mode:    mode,
+		Results: make([]model.BookOperationResult, len(ops)),
+	}
+
+	books := make([]*model.Book, len(ops))
+	valid := true
+	for i, op := range ops {
+		result.Results[i] = model.BookOperationResult{Op: op.Op, ID: op.ID}
+		books[i], result.Results[i].Err = b.prepareOperation(op)
+		if result.Results[i].Err != nil {
+			valid = false
+		}
+	}
+
+	if mode == model.BookBatchBestEffort {
+		applyOperations(ctx, b.bookRepo, ops, books, result.Results, false)
+		result.Committed = true
+
+		return result, nil
+	}
+
+	if valid {
+		err = b.bookRepo.Transaction(ctx, func(repo repository.BookRepoInterface) error {
+			return applyOperations(ctx, repo, ops, books, result.Results, true)
+		})
+		result.Committed = err == nil
+	}
+
+	if !result.Committed && !abortOperations(result.Results) {
+		// Nothing in the batch explains the failure, e.g. the commit failed.
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// prepareOperation validates op and returns the book to create or update.
+func (b *BookService) prepareOperation(op model.BookOperation) (*model.Book, error) {
+	switch op.Op {
+	case model.BookOperationCreate, model.BookOperationUpdate:
+		if op.Op == model.BookOperationUpdate && op.ID == 0 {
+			return nil, apperror.Validation("invalid operation", nil, apperror.FieldError{Name: "id", Reason: "is required"})
+		}
+		if op.Book == nil {
+			return nil, apperror.Validation("invalid operation", nil, apperror.FieldError{Name: "book", Reason: "is required"})
+		}
+
+		if err := b.validator.Struct(op.Book); err != nil {
+			return nil, apperror.Validation("invalid book", err, vr.ToFieldErrors(err)...)
+		}
+
+		book, err := op.Book.ToModel()
+		if err != nil {
+			return nil, apperror.Validation("invalid book", err, apperror.FieldError{Name: "published_date", Reason: "must be a valid date"})
+		}
+
+		if op.Op == model.BookOperationUpdate {
+			book.ID = op.ID
+		}
+
+		return book, nil
+	case model.BookOperationDelete:
+		if op.ID == 0 {
+			return nil, apperror.Validation("invalid operation", nil, apperror.FieldError{Name: "id", Reason: "is required"})
+		}
+
+		return nil, nil
+	default:
+		return nil, apperror.Validation("invalid operation", nil, apperror.FieldError{Name: "op", Reason: "must be one of create, update, delete"})
+	}
+}
+
+// createBooks inserts the books and their authors together with their audit
+// entries, all or none of them.
+func createBooks(ctx context.Context, repo repository.BookRepoInterface, books []*model.Book) error {
+	for _, book := range books {
+		keepImageURL(book, nil)
+	}
+
+	err := audited(ctx, repo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		if err := tx.CreateBooks(ctx, books); err != nil {
+			return nil, err
+		}
+
+		audits := make([]*model.BookAudit, 0, len(books))
+		for _, book := range books {
+			if err := setAuthors(ctx, tx, book, nil); err != nil {
+				return nil, err
+			}
+			audits = append(audits, newAudit(ctx, model.BookAuditCreate, book.ID, nil, book))
+		}
+
+		return audits, nil
+	})
 	if err != nil {
-		return err
+		// The IDs of the books rolled back are no one's.
+		for _, book := range books {
+			book.ID = 0
+		}
+	}
+
+	return err
+}
+
+// applyOperations applies the valid operations and records their outcome in
+// results. When stopOnError is set it returns the first error instead of
+// carrying on.
+func applyOperations(ctx context.Context, repo repository.BookRepoInterface, ops []model.BookOperation, books []*model.Book, results []model.BookOperationResult, stopOnError bool) error {
+	var creates []*model.Book
+	var createIdx []int
+	for i, op := range ops {
+		if op.Op == model.BookOperationCreate && results[i].Err == nil {
+			books[i].CreatedBy = actor.FromContext(ctx)
+			creates = append(creates, books[i])
+			createIdx = append(createIdx, i)
+		}
+	}
+
+	if len(creates) > 0 {
+		err := createBooks(ctx, repo, creates)
+		if err != nil && stopOnError {
+			for _, i := range createIdx {
+				results[i].Err = err
+			}
+			return err
+		}
+
+		for j, i := range createIdx {
+			if err != nil {
+				// One book fails them all: retried one by one, only the
+				// failing ones fail.
+				results[i].Err = createBooks(ctx, repo, creates[j:j+1])
+			}
+			if results[i].Err == nil {
+				results[i].ID = creates[j].ID
+				results[i].Book = creates[j].ToDto()
+			}
+		}
+	}
+
+	for i, op := range ops {
+		if results[i].Err != nil || op.Op == model.BookOperationCreate {
+			continue
+		}
+
+		err := audited(ctx, repo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+			var versions []uint
+			if op.Version != 0 {
+				versions = []uint{op.Version}
+			}
+
+			if op.Op == model.BookOperationUpdate {
+				return updateBook(ctx, tx, books[i], versions)
+			}
+			return deleteBook(ctx, tx, op.ID, versions)
+		})
+		if err == nil && op.Op == model.BookOperationUpdate {
+			results[i].Book = books[i].ToDto()
+		}
+
+		results[i].Err = err
+		if err != nil && stopOnError {
+			return err
+		}
 	}
 
 	return nil
 }
+
+// abortOperations marks the operations of a rolled back batch which didn't
+// fail themselves, and reports whether one of them did.
+func abortOperations(results []model.BookOperationResult) bool {
+	failed := false
+	for i := range results {
+		if results[i].Err != nil {
+			failed = true
+			continue
+		}
+
+		results[i].Err = apperror.Aborted("not applied as another operation of the batch failed", nil)
+		results[i].Book = nil
+		if results[i].Op == model.BookOperationCreate {
+			results[i].ID = 0
+		}
+	}
+
+	return failed
+}
+
+// ExportBooks calls fn with every book of the catalog, in id order.
+func (b *BookService) ExportBooks(ctx context.Context, fn func(book *model.BookDto) error) (err error) {
+	ctx, span := tracer.Start(ctx, "BookService.ExportBooks")
+	defer func() { endSpan(span, err) }()
+
+	return b.bookRepo.EachBook(ctx, func(book *model.Book) error {
+		return fn(book.ToDto())
+	})
+}
+
+// ImportBooks validates the rows of an import file and, unless dryRun is set,
+// creates their books in a single transaction, importBatchSize at a time.
+// Nothing is imported when a row is invalid or its ISBN is taken; the invalid
+// fields are reported with their line.
+func (b *BookService) ImportBooks(ctx context.Context, rows []*model.BookImportRow, dryRun bool) (_ *model.BookImportResult, err error) {
+	ctx, span := tracer.Start(ctx, "BookService.ImportBooks")
+	defer func() { endSpan(span, err) }()
+
+	if len(rows) == 0 {
+		return nil, apperror.Validation("import file has no books", nil)
+	}
+
+	var fields []apperror.FieldError
+	invalid := 0
+	books := make([]*model.Book, 0, len(rows))
+	lines := make([]int, 0, len(rows))
+	isbnLines := make(map[string]int)
+	for _, row := range rows {
+		rowFields := b.validateImportRow(row)
+		if len(rowFields) > 0 {
+			invalid++
+			fields = append(fields, rowFields...)
+			continue
+		}
+
+		book, _ := row.Form.ToModel()
+		if book.ISBN != nil {
+			if line, ok := isbnLines[*book.ISBN]; ok {
+				invalid++
+				fields = append(fields, apperror.FieldError{Name: "isbn", Reason: fmt.Sprintf("duplicates the ISBN of line %d", line), Line: row.Line})
+				continue
+			}
+			isbnLines[*book.ISBN] = row.Line
+		}
+
+		book.CreatedBy = actor.FromContext(ctx)
+		books = append(books, book)
+		lines = append(lines, row.Line)
+	}
+
+	if invalid > 0 {
+		if len(fields) > maxImportErrors {
+			fields = fields[:maxImportErrors]
+		}
+		return nil, apperror.Validation(fmt.Sprintf("%d of %d rows are invalid", invalid, len(rows)), nil, fields...)
+	}
+
+	result := &model.BookImportResult{DryRun: dryRun, Rows: len(rows)}
+	if dryRun {
+		return result, nil
+	}
+
+	var failed int
+	err = b.bookRepo.Transaction(ctx, func(tx repository.BookRepoInterface) error {
+		for start := 0; start < len(books); start += importBatchSize {
+			end := start + importBatchSize
+			if end > len(books) {
+				end = len(books)
+			}
+
+			failed = start
+			if err := createBooks(ctx, tx, books[start:end]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, apperror.ErrConflict) {
+			end := failed + importBatchSize
+			if end > len(books) {
+				end = len(books)
+			}
+			return nil, b.explainImportConflict(ctx, err, books[failed:end], lines[failed:end])
+		}
+		return nil, err
+	}
+
+	result.Imported = len(books)
+	logger.Ctx(ctx).Debug().Int("books", result.Imported).Msg("Imported books")
+
+	return result, nil
+}
+
+// explainImportConflict tells the lines of the books whose ISBN is taken,
+// the conflict err was raised for while creating them.
+func (b *BookService) explainImportConflict(ctx context.Context, err error, books []*model.Book, lines []int) error {
+	isbns := make([]string, 0, len(books))
+	for _, book := range books {
+		if book.ISBN != nil {
+			isbns = append(isbns, *book.ISBN)
+		}
+	}
+	if len(isbns) == 0 {
+		return err
+	}
+
+	taken, lookupErr := b.bookRepo.TakenISBNs(ctx, isbns)
+	if lookupErr != nil || len(taken) == 0 {
+		return err
+	}
+
+	isTaken := make(map[string]bool, len(taken))
+	for _, isbn := range taken {
+		isTaken[isbn] = true
+	}
+
+	var fields []apperror.FieldError
+	for i, book := range books {
+		if book.ISBN != nil && isTaken[*book.ISBN] && len(fields) < maxImportErrors {
+			fields = append(fields, apperror.FieldError{Name: "isbn", Reason: "is the ISBN of another book", Line: lines[i]})
+		}
+	}
+
+	conflict := apperror.Conflict(fmt.Sprintf("%d books have the ISBN of another book", len(taken)), err)
+	conflict.Fields = fields
+
+	return conflict
+}
+
+// validateImportRow returns the invalid fields of row, tagged with its line.
+func (b *BookService) validateImportRow(row *model.BookImportRow) []apperror.FieldError {
+	var fields []apperror.FieldError
+	if row.Err != nil {
+		fields = []apperror.FieldError{{Reason: row.Err.Error()}}
+	} else if err := b.validator.Struct(row.Form); err != nil {
+		fields = vr.ToFieldErrors(err)
+	} else if _, err := row.Form.ToModel(); err != nil {
+		fields = []apperror.FieldError{{Name: "published_date", Reason: "must be a valid date"}}
+	}
+
+	for i := range fields {
+		fields[i].Line = row.Line
+	}
+
+	return fields
+}
+
+// keepImageURL replaces the URL of an uploaded cover, which is the image URL
+// books with one are read with, by the image URL of the book before the
+// change, none for a new book.
+func keepImageURL(book, before *model.Book) {
+	if !model.IsCoverURL(book.ImageUrl) {
+		return
+	}
+
+	book.ImageUrl = ""
+	if before != nil {
+		book.ImageUrl = before.ImageUrl
+	}
+}
+
+// setAuthors replaces the authors of book in tx by its AuthorIDs. Without
+// them, the author named by book.Author, created on first use, becomes the
+// author of a new book, before being nil, or of a book whose Author changes;
+// the authors of the others are left as they are.
+func setAuthors(ctx context.Context, tx repository.BookRepoInterface, book, before *model.Book) error {
+	if book.AuthorIDs == nil {
+		name := strings.TrimSpace(book.Author)
+		if before != nil && name == strings.TrimSpace(before.Author) {
+			return nil
+		}
+
+		book.AuthorIDs = []uint{}
+		if name != "" {
+			id, err := tx.EnsureAuthor(ctx, name)
+			if err != nil {
+				return err
+			}
+			book.AuthorIDs = []uint{id}
+		}
+	}
+
+	return tx.SetBookAuthors(ctx, book.ID, book.AuthorIDs)
+}
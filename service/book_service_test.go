@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"myapp/adapter/blob"
 	"myapp/model"
 	"testing"
 	"time"
@@ -12,6 +13,11 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	mock_repository "myapp/mocks/repository"
+	"myapp/repository"
+	"myapp/util/actor"
+	"myapp/util/apperror"
+	"myapp/util/requestid"
+	"myapp/util/validator"
 )
 
 var bookForm = &model.BookForm{
@@ -40,6 +46,18 @@ var booksDB = model.Books{
 	bookDB,
 }
 
+// expectTransactions runs the transactions of the service on mockRepo itself
+// and accepts their audit entries and the authors of their books, resolved as
+// author 7. Expectations set before take precedence.
+func expectTransactions(mockRepo *mock_repository.MockBookRepoInterface) {
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repository.BookRepoInterface) error) error {
+		return fn(mockRepo)
+	}).AnyTimes()
+	mockRepo.EXPECT().CreateAudits(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockRepo.EXPECT().EnsureAuthor(gomock.Any(), gomock.Any()).Return(uint(7), nil).AnyTimes()
+	mockRepo.EXPECT().SetBookAuthors(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+}
+
 func TestBookService_CreateBook(t *testing.T) {
 	type args struct {
 		ctx  context.Context
@@ -49,6 +67,7 @@ func TestBookService_CreateBook(t *testing.T) {
 		name        string
 		args        args
 		wantErr     bool
+		wantErrIs   error
 		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
 	}{
 		{
@@ -73,13 +92,71 @@ func TestBookService_CreateBook(t *testing.T) {
 				mockRepo.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
 			},
 		},
+		{
+			name: "unknown author",
+			args: args{
+				ctx:  context.Background(),
+				book: &model.BookForm{Title: "title", Author: "author", PublishedDate: "2006-01-02", AuthorIDs: []uint{7}},
+			},
+			wantErr:   true,
+			wantErrIs: apperror.ErrValidation,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) (*model.Book, error) {
+					book.ID = 1
+					return book, nil
+				})
+				mockRepo.EXPECT().SetBookAuthors(gomock.Any(), uint(1), []uint{7}).Return(apperror.Validation("unknown author", nil))
+			},
+		},
+		{
+			name: "author resolved by name",
+			args: args{
+				ctx:  context.Background(),
+				book: &model.BookForm{Title: "title", Author: " J. R. R. Tolkien ", PublishedDate: "2006-01-02"},
+			},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) (*model.Book, error) {
+					book.ID = 1
+					return book, nil
+				})
+				mockRepo.EXPECT().EnsureAuthor(gomock.Any(), "J. R. R. Tolkien").Return(uint(3), nil)
+				mockRepo.EXPECT().SetBookAuthors(gomock.Any(), uint(1), []uint{3}).Return(nil)
+			},
+		},
+		{
+			name: "isbn-10 stored as isbn-13",
+			args: args{
+				ctx:  context.Background(),
+				book: &model.BookForm{Title: "title", Author: "author", PublishedDate: "2006-01-02", ISBN: "0-306-40615-2"},
+			},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) (*model.Book, error) {
+					assert.Equal(t, "9780306406157", *book.ISBN)
+					book.ID = 1
+					return book, nil
+				})
+			},
+		},
+		{
+			name: "duplicate isbn",
+			args: args{
+				ctx:  context.Background(),
+				book: &model.BookForm{Title: "title", Author: "author", PublishedDate: "2006-01-02", ISBN: "9780306406157"},
+			},
+			wantErr:   true,
+			wantErrIs: apperror.ErrConflict,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(nil, apperror.Conflict("book already exists", nil))
+			},
+		},
 		{
 			name: "error parse book",
 			args: args{
 				ctx:  context.Background(),
 				book: &model.BookForm{},
 			},
-			wantErr: true,
+			wantErr:   true,
+			wantErrIs: apperror.ErrValidation,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
 				mockRepo.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 			},
@@ -94,10 +171,14 @@ func TestBookService_CreateBook(t *testing.T) {
 			if tt.prepareMock != nil {
 				tt.prepareMock(mockRepo)
 			}
+			expectTransactions(mockRepo)
 
-			svc := NewBookService(mockRepo)
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
 
 			resp, err := svc.CreateBook(tt.args.ctx, tt.args.book)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			}
 			if !tt.wantErr {
 				assert.NoError(t, err)
 				assert.NotEmpty(t, resp)
@@ -128,6 +209,7 @@ func TestBookService_GetBookByID(t *testing.T) {
 			wantErr: false,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
 				mockRepo.EXPECT().ReadBook(gomock.Any(), gomock.Any()).Return(bookDB, nil).AnyTimes()
+				mockRepo.EXPECT().BookAuthorIDs(gomock.Any(), gomock.Any()).Return([]uint{1, 2}, nil)
 			},
 		},
 		{
@@ -152,7 +234,7 @@ func TestBookService_GetBookByID(t *testing.T) {
 				tt.prepareMock(mockRepo)
 			}
 
-			svc := NewBookService(mockRepo)
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
 
 			resp, err := svc.GetBookByID(tt.args.ctx, tt.args.id)
 			if !tt.wantErr {
@@ -165,9 +247,65 @@ func TestBookService_GetBookByID(t *testing.T) {
 	}
 }
 
+func TestBookService_GetBookByISBN(t *testing.T) {
+	tests := []struct {
+		name        string
+		isbn        string
+		want        *model.BookDto
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "isbn-10",
+			isbn: "0-306-40615-2",
+			want: &model.BookDto{ID: 1, Title: "title", PublishedDate: "0001-01-01", ISBN13: "9780306406157", ISBN10: "0306406152", AuthorIDs: []uint{2}},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				isbn := "9780306406157"
+				mockRepo.EXPECT().ReadBookByISBN(gomock.Any(), "9780306406157").Return(&model.Book{Model: gorm.Model{ID: 1}, Title: "title", ISBN: &isbn}, nil)
+				mockRepo.EXPECT().BookAuthorIDs(gomock.Any(), uint(1)).Return([]uint{2}, nil)
+			},
+		},
+		{
+			name:      "unknown isbn",
+			isbn:      "9791032305690",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ReadBookByISBN(gomock.Any(), "9791032305690").Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+		{
+			name:      "invalid checksum",
+			isbn:      "0-306-40615-3",
+			wantErrIs: apperror.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			resp, err := svc.GetBookByISBN(context.Background(), tt.isbn)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
 func TestBookService_GetListBook(t *testing.T) {
 	type args struct {
-		ctx context.Context
+		ctx   context.Context
+		query *model.BookQuery
 	}
 	tests := []struct {
 		name        string
@@ -178,21 +316,23 @@ func TestBookService_GetListBook(t *testing.T) {
 		{
 			name: "success call",
 			args: args{
-				ctx: context.Background(),
+				ctx:   context.Background(),
+				query: &model.BookQuery{Limit: model.DefaultBookListLimit},
 			},
 			wantErr: false,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
-				mockRepo.EXPECT().ListBooks(gomock.Any()).Return(booksDB, nil).AnyTimes()
+				mockRepo.EXPECT().ListBooks(gomock.Any(), gomock.Any()).Return(booksDB, int64(1), nil).AnyTimes()
 			},
 		},
 		{
 			name: "error call",
 			args: args{
-				ctx: context.Background(),
+				ctx:   context.Background(),
+				query: &model.BookQuery{Limit: model.DefaultBookListLimit},
 			},
 			wantErr: true,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
-				mockRepo.EXPECT().ListBooks(gomock.Any()).Return(nil, errors.New("error")).AnyTimes()
+				mockRepo.EXPECT().ListBooks(gomock.Any(), gomock.Any()).Return(nil, int64(0), errors.New("error")).AnyTimes()
 			},
 		},
 	}
@@ -206,9 +346,9 @@ func TestBookService_GetListBook(t *testing.T) {
 				tt.prepareMock(mockRepo)
 			}
 
-			svc := NewBookService(mockRepo)
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
 
-			resp, err := svc.GetListBook(tt.args.ctx)
+			resp, err := svc.GetListBook(tt.args.ctx, tt.args.query)
 			if !tt.wantErr {
 				assert.NoError(t, err)
 				assert.NotEmpty(t, resp)
@@ -219,16 +359,103 @@ func TestBookService_GetListBook(t *testing.T) {
 	}
 }
 
+func TestBookService_GetListBookCursors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	second := *bookDB
+	second.ID = 2
+
+	mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+	mockRepo.EXPECT().ListBooks(gomock.Any(), gomock.Any()).Return(model.Books{bookDB, &second}, int64(3), nil)
+
+	svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+	resp, err := svc.GetListBook(context.Background(), &model.BookQuery{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Data, 1)
+	assert.Equal(t, int64(3), resp.Total)
+	assert.Empty(t, resp.PrevCursor)
+
+	next, err := model.DecodeBookCursor(resp.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, bookDB.ID, next.ID)
+	assert.False(t, next.Backward)
+}
+
+func TestBookService_SearchBooks(t *testing.T) {
+	type args struct {
+		ctx   context.Context
+		query *model.BookSearchQuery
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantErr     bool
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "success call",
+			args: args{
+				ctx:   context.Background(),
+				query: &model.BookSearchQuery{Q: "Title", Limit: model.DefaultBookListLimit},
+			},
+			wantErr: false,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				hits := model.BookSearchHits{{Book: *bookDB, Score: 2.5}}
+				mockRepo.EXPECT().SearchBooks(gomock.Any(), gomock.Any()).Return(hits, int64(1), nil).AnyTimes()
+			},
+		},
+		{
+			name: "error call",
+			args: args{
+				ctx:   context.Background(),
+				query: &model.BookSearchQuery{Q: "title", Limit: model.DefaultBookListLimit},
+			},
+			wantErr: true,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().SearchBooks(gomock.Any(), gomock.Any()).Return(nil, int64(0), errors.New("error")).AnyTimes()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			resp, err := svc.SearchBooks(tt.args.ctx, tt.args.query)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				assert.Len(t, resp.Data, 1)
+				assert.Equal(t, 2.5, resp.Data[0].Score)
+				assert.Equal(t, "<em>title</em>", resp.Data[0].Highlights["title"])
+				assert.NotContains(t, resp.Data[0].Highlights, "author")
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 func TestBookService_UpdateBook(t *testing.T) {
 	type args struct {
-		ctx  context.Context
-		id   uint
-		book *model.BookForm
+		ctx      context.Context
+		id       uint
+		book     *model.BookForm
+		versions []uint
 	}
 	tests := []struct {
 		name        string
 		args        args
 		wantErr     bool
+		wantErrIs   error
+		wantVersion uint
 		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
 	}{
 		{
@@ -238,9 +465,90 @@ func TestBookService_UpdateBook(t *testing.T) {
 				id:   1,
 				book: bookForm,
 			},
-			wantErr: false,
+			wantErr:     false,
+			wantVersion: 7,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
-				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, Version: 6}, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) error {
+					assert.Equal(t, uint(6), book.Version)
+					book.Version++
+					return nil
+				})
+			},
+		},
+		{
+			name: "cover URL keeps the image URL",
+			args: args{
+				ctx: context.Background(),
+				id:  1,
+				book: &model.BookForm{
+					Title:         "title",
+					Author:        "author",
+					PublishedDate: "2006-01-02",
+					ImageUrl:      "/api/v1/books/1/cover",
+				},
+			},
+			wantErr:     false,
+			wantVersion: 7,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, ImageUrl: "http://example.com/cover.png", CoverType: "image/png", Version: 6}, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) error {
+					assert.Equal(t, "http://example.com/cover.png", book.ImageUrl)
+					book.Version++
+					return nil
+				})
+			},
+		},
+		{
+			name: "conditional call",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				book:     bookForm,
+				versions: []uint{3},
+			},
+			wantErr:     false,
+			wantVersion: 4,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, Version: 3}, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) error {
+					assert.Equal(t, uint(3), book.Version)
+					book.Version++
+					return nil
+				})
+			},
+		},
+		{
+			name: "conditional call on one of several versions",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				book:     bookForm,
+				versions: []uint{2, 3},
+			},
+			wantErr:     false,
+			wantVersion: 4,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, Version: 3}, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) error {
+					assert.Equal(t, uint(3), book.Version)
+					book.Version++
+					return nil
+				})
+			},
+		},
+		{
+			name: "stale version",
+			args: args{
+				ctx:      context.Background(),
+				id:       1,
+				book:     bookForm,
+				versions: []uint{3},
+			},
+			wantErr:   true,
+			wantErrIs: apperror.ErrPreconditionFailed,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, Version: 4}, nil)
 			},
 		},
 		{
@@ -252,6 +560,7 @@ func TestBookService_UpdateBook(t *testing.T) {
 			},
 			wantErr: true,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, Version: 1}, nil)
 				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(errors.New("error")).AnyTimes()
 			},
 		},
@@ -277,12 +586,17 @@ func TestBookService_UpdateBook(t *testing.T) {
 			if tt.prepareMock != nil {
 				tt.prepareMock(mockRepo)
 			}
+			expectTransactions(mockRepo)
 
-			svc := NewBookService(mockRepo)
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
 
-			err := svc.UpdateBook(tt.args.ctx, tt.args.id, tt.args.book)
+			resp, err := svc.UpdateBook(tt.args.ctx, tt.args.id, tt.args.book, tt.args.versions)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+			}
 			if !tt.wantErr {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.wantVersion, resp.Version)
 			} else {
 				assert.Error(t, err)
 			}
@@ -310,7 +624,8 @@ func TestBookService_DeleteBook(t *testing.T) {
 			},
 			wantErr: false,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
-				mockRepo.EXPECT().DeleteBook(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, Version: 2}, nil)
+				mockRepo.EXPECT().DeleteBook(gomock.Any(), uint(1), uint(2)).Return(nil)
 			},
 		},
 		{
@@ -321,7 +636,8 @@ func TestBookService_DeleteBook(t *testing.T) {
 			},
 			wantErr: true,
 			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
-				mockRepo.EXPECT().DeleteBook(gomock.Any(), gomock.Any()).Return(errors.New("error")).AnyTimes()
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, Version: 2}, nil)
+				mockRepo.EXPECT().DeleteBook(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("error")).AnyTimes()
 			},
 		},
 	}
@@ -334,15 +650,781 @@ func TestBookService_DeleteBook(t *testing.T) {
 			if tt.prepareMock != nil {
 				tt.prepareMock(mockRepo)
 			}
+			expectTransactions(mockRepo)
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			err := svc.DeleteBook(tt.args.ctx, tt.args.id, nil)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBookService_PurgeBook(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantErr     bool
+		wantCover   bool
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "success call",
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), true).Return(&model.Book{Model: gorm.Model{ID: 1}, CoverType: "image/png", CoverKey: "covers/1/a"}, nil)
+				mockRepo.EXPECT().PurgeBooks(gomock.Any(), []uint{1}).Return(int64(1), nil)
+			},
+		},
+		{
+			name:      "error call",
+			wantErr:   true,
+			wantCover: true,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), true).Return(&model.Book{Model: gorm.Model{ID: 1}, CoverType: "image/png", CoverKey: "covers/1/a"}, nil)
+				mockRepo.EXPECT().PurgeBooks(gomock.Any(), []uint{1}).Return(int64(0), errors.New("error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
 
-			svc := NewBookService(mockRepo)
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+			tt.prepareMock(mockRepo)
+			expectTransactions(mockRepo)
+
+			covers := coverStore(t, "covers/1/a")
+			svc := NewBookService(mockRepo, covers, validator.New(), 10)
 
-			err := svc.DeleteBook(tt.args.ctx, tt.args.id)
+			err := svc.PurgeBook(ctx, 1)
 			if !tt.wantErr {
 				assert.NoError(t, err)
 			} else {
 				assert.Error(t, err)
 			}
+
+			// The cover of the book goes with it, only once it's purged.
+			for _, key := range model.CoverKeys("covers/1/a") {
+				_, err := covers.Get(ctx, key)
+				if tt.wantCover {
+					assert.NoError(t, err, key)
+				} else {
+					assert.ErrorIs(t, err, blob.ErrNotFound, key)
+				}
+			}
+		})
+	}
+}
+
+func TestBookService_PurgeDeletedBooks(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	before := time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+	gomock.InOrder(
+		mockRepo.EXPECT().LockDeletedBooks(gomock.Any(), before, purgeBatchSize).Return([]*model.Book{
+			{Model: gorm.Model{ID: 1}, CoverType: "image/png", CoverKey: "covers/1/a"},
+			{Model: gorm.Model{ID: 2}},
+		}, nil),
+		mockRepo.EXPECT().PurgeBooks(gomock.Any(), []uint{1, 2}).Return(int64(2), nil),
+	)
+	expectTransactions(mockRepo)
+
+	covers := coverStore(t, "covers/1/a")
+	svc := NewBookService(mockRepo, covers, validator.New(), 10)
+
+	purged, err := svc.PurgeDeletedBooks(ctx, before)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), purged)
+
+	for _, key := range model.CoverKeys("covers/1/a") {
+		_, err := covers.Get(ctx, key)
+		assert.ErrorIs(t, err, blob.ErrNotFound, key)
+	}
+}
+
+// coverStore returns a store holding the blobs of the cover uploaded under
+// coverKey.
+func coverStore(t *testing.T, coverKey string) blob.Store {
+	store, err := blob.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range model.CoverKeys(coverKey) {
+		if err := store.Put(context.Background(), key, "image/png", []byte("cover")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return store
+}
+
+func TestBookService_PatchBook(t *testing.T) {
+	current := &model.Book{
+		Model:         gorm.Model{ID: 1},
+		Title:         "title",
+		Author:        "author",
+		PublishedDate: time.Date(2006, time.January, 2, 0, 0, 0, 0, time.UTC),
+		ImageUrl:      "https://example.com/cover.jpg",
+		Description:   "description",
+		Version:       1,
+	}
+
+	// The repository bumps the version of the book it saves.
+	updated := func(ctx context.Context, book *model.Book) error {
+		book.Version++
+		return nil
+	}
+
+	type args struct {
+		versions []uint
+		format   model.PatchFormat
+		patch    string
+	}
+	tests := []struct {
+		name        string
+		args        args
+		want        *model.BookDto
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "merge patch",
+			args: args{
+				format: model.PatchMerge,
+				patch:  `{"description":"new description","image_url":null}`,
+			},
+			want: &model.BookDto{ID: 1, Title: "title", Author: "author", PublishedDate: "2006-01-02", Description: "new description", Version: 2},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(updated)
+			},
+		},
+		{
+			name: "json patch",
+			args: args{
+				format: model.PatchJSON,
+				patch:  `[{"op":"test","path":"/title","value":"title"},{"op":"replace","path":"/title","value":"new title"}]`,
+			},
+			want: &model.BookDto{ID: 1, Title: "new title", Author: "author", PublishedDate: "2006-01-02", ImageUrl: "https://example.com/cover.jpg", Description: "description", Version: 2},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(updated)
+			},
+		},
+		{
+			name: "authors",
+			args: args{
+				format: model.PatchJSON,
+				patch:  `[{"op":"add","path":"/author_ids/-","value":4}]`,
+			},
+			want: &model.BookDto{ID: 1, Title: "title", Author: "author", PublishedDate: "2006-01-02", ImageUrl: "https://example.com/cover.jpg", Description: "description", Version: 2, AuthorIDs: []uint{3, 4}},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+				mockRepo.EXPECT().BookAuthorIDs(gomock.Any(), uint(1)).Return([]uint{3}, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(updated)
+				mockRepo.EXPECT().SetBookAuthors(gomock.Any(), uint(1), []uint{3, 4}).Return(nil)
+			},
+		},
+		{
+			name: "matching version",
+			args: args{
+				versions: []uint{1},
+				format:   model.PatchMerge,
+				patch:    `{"title":"new title"}`,
+			},
+			want: &model.BookDto{ID: 1, Title: "new title", Author: "author", PublishedDate: "2006-01-02", ImageUrl: "https://example.com/cover.jpg", Description: "description", Version: 2},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(updated)
+			},
+		},
+		{
+			name: "stale version",
+			args: args{
+				versions: []uint{2},
+				format:   model.PatchMerge,
+				patch:    `{"title":"new title"}`,
+			},
+			wantErrIs: apperror.ErrPreconditionFailed,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+			},
+		},
+		{
+			name: "json patch test failed",
+			args: args{
+				format: model.PatchJSON,
+				patch:  `[{"op":"test","path":"/title","value":"other"},{"op":"replace","path":"/title","value":"new title"}]`,
+			},
+			wantErrIs: apperror.ErrConflict,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+			},
+		},
+		{
+			name: "malformed patch",
+			args: args{
+				format: model.PatchJSON,
+				patch:  `{"op":"remove"}`,
+			},
+			wantErrIs: apperror.ErrValidation,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+			},
+		},
+		{
+			name: "invalid result",
+			args: args{
+				format: model.PatchMerge,
+				patch:  `{"title":null}`,
+			},
+			wantErrIs: apperror.ErrValidation,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+			},
+		},
+		{
+			name: "unknown field",
+			args: args{
+				format: model.PatchMerge,
+				patch:  `{"isbn":"123"}`,
+			},
+			wantErrIs: apperror.ErrValidation,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(current, nil)
+			},
+		},
+		{
+			name: "not found",
+			args: args{
+				format: model.PatchMerge,
+				patch:  `{"title":"new title"}`,
+			},
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+			mockRepo.EXPECT().BookAuthorIDs(gomock.Any(), uint(1)).Return([]uint{}, nil).AnyTimes()
+			expectTransactions(mockRepo)
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			resp, err := svc.PatchBook(context.Background(), 1, tt.args.versions, tt.args.format, []byte(tt.args.patch))
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
+
+func TestBookService_BatchBooks(t *testing.T) {
+	validBook := &model.BookForm{
+		Title:         "title",
+		Author:        "author",
+		PublishedDate: "2006-01-02",
+		ImageUrl:      "https://example.com/cover.jpg",
+	}
+
+	// created assigns IDs like the repository does.
+	created := func(ctx context.Context, books []*model.Book) error {
+		for i, b := range books {
+			b.ID = uint(10 + i)
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name          string
+		batch         *model.BookBatchForm
+		wantErrIs     error
+		wantCommitted bool
+		wantIDs       []uint
+		wantErrs      []error
+		prepareMock   func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "atomic success",
+			batch: &model.BookBatchForm{Operations: []model.BookOperation{
+				{Op: model.BookOperationCreate, Book: validBook},
+				{Op: model.BookOperationUpdate, ID: 2, Book: validBook},
+				{Op: model.BookOperationDelete, ID: 3},
+				{Op: model.BookOperationCreate, Book: validBook},
+			}},
+			wantCommitted: true,
+			wantIDs:       []uint{10, 2, 3, 11},
+			wantErrs:      []error{nil, nil, nil, nil},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(2)).DoAndReturn(created)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(2), false).Return(&model.Book{Model: gorm.Model{ID: 2}, Version: 1}, nil)
+				mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(3), false).Return(&model.Book{Model: gorm.Model{ID: 3}, Version: 1}, nil)
+				mockRepo.EXPECT().DeleteBook(gomock.Any(), uint(3), uint(1)).Return(nil)
+			},
+		},
+		{
+			name: "atomic invalid operation",
+			batch: &model.BookBatchForm{Mode: model.BookBatchAtomic, Operations: []model.BookOperation{
+				{Op: model.BookOperationCreate, Book: validBook},
+				{Op: model.BookOperationUpdate, Book: validBook},
+			}},
+			wantCommitted: false,
+			wantIDs:       []uint{0, 0},
+			wantErrs:      []error{apperror.ErrAborted, apperror.ErrValidation},
+		},
+		{
+			name: "atomic rolled back",
+			batch: &model.BookBatchForm{Operations: []model.BookOperation{
+				{Op: model.BookOperationCreate, Book: validBook},
+				{Op: model.BookOperationDelete, ID: 3, Version: 2},
+				{Op: model.BookOperationDelete, ID: 4},
+			}},
+			wantCommitted: false,
+			wantIDs:       []uint{0, 3, 4},
+			wantErrs:      []error{apperror.ErrAborted, apperror.ErrPreconditionFailed, apperror.ErrAborted},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(1)).DoAndReturn(created)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(3), false).Return(&model.Book{Model: gorm.Model{ID: 3}, Version: 1}, nil)
+			},
+		},
+		{
+			name: "best effort",
+			batch: &model.BookBatchForm{Mode: model.BookBatchBestEffort, Operations: []model.BookOperation{
+				{Op: model.BookOperationCreate, Book: validBook},
+				{Op: model.BookOperationCreate, Book: &model.BookForm{}},
+				{Op: model.BookOperationDelete, ID: 3},
+				{Op: model.BookOperationUpdate, ID: 4, Book: validBook},
+				{Op: "upsert"},
+			}},
+			wantCommitted: true,
+			wantIDs:       []uint{10, 0, 3, 4, 0},
+			wantErrs:      []error{nil, apperror.ErrValidation, nil, apperror.ErrNotFound, apperror.ErrValidation},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(1)).DoAndReturn(created)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(3), false).Return(&model.Book{Model: gorm.Model{ID: 3}, Version: 1}, nil)
+				mockRepo.EXPECT().DeleteBook(gomock.Any(), uint(3), uint(1)).Return(nil)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(4), false).Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+		{
+			name: "best effort retries creates one by one",
+			batch: &model.BookBatchForm{Mode: model.BookBatchBestEffort, Operations: []model.BookOperation{
+				{Op: model.BookOperationCreate, Book: validBook},
+				{Op: model.BookOperationCreate, Book: validBook},
+				{Op: model.BookOperationCreate, Book: validBook},
+			}},
+			wantCommitted: true,
+			wantIDs:       []uint{10, 0, 10},
+			wantErrs:      []error{nil, apperror.ErrConflict, nil},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				conflict := apperror.Conflict("book already exists", nil)
+				gomock.InOrder(
+					mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(3)).DoAndReturn(func(ctx context.Context, books []*model.Book) error {
+						books[0].ID = 10
+						return conflict
+					}),
+					mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(1)).DoAndReturn(created),
+					mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(1)).Return(conflict),
+					mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(1)).DoAndReturn(created),
+				)
+			},
+		},
+		{
+			name:      "unknown mode",
+			batch:     &model.BookBatchForm{Mode: "eventual", Operations: []model.BookOperation{{Op: model.BookOperationDelete, ID: 3}}},
+			wantErrIs: apperror.ErrValidation,
+		},
+		{
+			name:      "empty batch",
+			batch:     &model.BookBatchForm{},
+			wantErrIs: apperror.ErrValidation,
+		},
+		{
+			name:      "too many operations",
+			batch:     &model.BookBatchForm{Operations: make([]model.BookOperation, 11)},
+			wantErrIs: apperror.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+			expectTransactions(mockRepo)
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			resp, err := svc.BatchBooks(context.Background(), tt.batch)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantCommitted, resp.Committed)
+			for i, res := range resp.Results {
+				assert.Equal(t, tt.wantIDs[i], res.ID, "operation %d", i)
+				if tt.wantErrs[i] == nil {
+					assert.NoError(t, res.Err, "operation %d", i)
+				} else {
+					assert.ErrorIs(t, res.Err, tt.wantErrs[i], "operation %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestBookService_ExportBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+	mockRepo.EXPECT().EachBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(*model.Book) error) error {
+		for _, b := range []*model.Book{{Model: gorm.Model{ID: 1}, Title: "first"}, {Model: gorm.Model{ID: 2}, Title: "second"}} {
+			if err := fn(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+	var titles []string
+	err := svc.ExportBooks(context.Background(), func(book *model.BookDto) error {
+		titles = append(titles, book.Title)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, titles)
+}
+
+func TestBookService_ImportBooks(t *testing.T) {
+	validRow := func(line int) *model.BookImportRow {
+		return &model.BookImportRow{Line: line, Form: &model.BookForm{
+			Title:         "title",
+			Author:        "author",
+			PublishedDate: "2006-01-02",
+		}}
+	}
+	isbnRow := func(line int, isbn string) *model.BookImportRow {
+		row := validRow(line)
+		row.Form.ISBN = isbn
+		return row
+	}
+
+	tests := []struct {
+		name        string
+		rows        []*model.BookImportRow
+		dryRun      bool
+		want        *model.BookImportResult
+		wantErrIs   error
+		wantFields  []apperror.FieldError
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "import",
+			rows: []*model.BookImportRow{validRow(2), validRow(3)},
+			want: &model.BookImportResult{Rows: 2, Imported: 2},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(2)).Return(nil)
+				mockRepo.EXPECT().CreateAudits(gomock.Any(), gomock.Len(2)).Return(nil)
+			},
+		},
+		{
+			name:   "dry run",
+			rows:   []*model.BookImportRow{validRow(2)},
+			dryRun: true,
+			want:   &model.BookImportResult{DryRun: true, Rows: 1},
+		},
+		{
+			name: "invalid rows",
+			rows: []*model.BookImportRow{
+				validRow(2),
+				{Line: 3, Form: &model.BookForm{Author: "author", PublishedDate: "2006-01-02"}},
+				{Line: 5, Err: errors.New("has 2 fields, expected 3")},
+			},
+			wantErrIs: apperror.ErrValidation,
+			wantFields: []apperror.FieldError{
+				{Name: "title", Reason: "is a required field", Line: 3},
+				{Reason: "has 2 fields, expected 3", Line: 5},
+			},
+		},
+		{
+			name:      "empty file",
+			wantErrIs: apperror.ErrValidation,
+		},
+		{
+			name: "import in chunks",
+			rows: func() []*model.BookImportRow {
+				rows := make([]*model.BookImportRow, importBatchSize+1)
+				for i := range rows {
+					rows[i] = validRow(i + 2)
+				}
+				return rows
+			}(),
+			want: &model.BookImportResult{Rows: importBatchSize + 1, Imported: importBatchSize + 1},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				gomock.InOrder(
+					mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(importBatchSize)).Return(nil),
+					mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(1)).Return(nil),
+				)
+			},
+		},
+		{
+			name:      "duplicate ISBN in file",
+			rows:      []*model.BookImportRow{isbnRow(2, "9780306406157"), validRow(3), isbnRow(4, "0-306-40615-2")},
+			wantErrIs: apperror.ErrValidation,
+			wantFields: []apperror.FieldError{
+				{Name: "isbn", Reason: "duplicates the ISBN of line 2", Line: 4},
+			},
+		},
+		{
+			name:      "ISBN taken",
+			rows:      []*model.BookImportRow{validRow(2), isbnRow(3, "9780306406157"), isbnRow(4, "9780804429573")},
+			wantErrIs: apperror.ErrConflict,
+			wantFields: []apperror.FieldError{
+				{Name: "isbn", Reason: "is the ISBN of another book", Line: 4},
+			},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(3)).Return(apperror.Conflict("book already exists", nil))
+				mockRepo.EXPECT().TakenISBNs(gomock.Any(), []string{"9780306406157", "9780804429573"}).Return([]string{"9780804429573"}, nil)
+			},
+		},
+		{
+			name:      "conflict without taken ISBN",
+			rows:      []*model.BookImportRow{isbnRow(2, "9780306406157")},
+			wantErrIs: apperror.ErrConflict,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().CreateBooks(gomock.Any(), gomock.Len(1)).Return(apperror.Conflict("book already exists", nil))
+				mockRepo.EXPECT().TakenISBNs(gomock.Any(), []string{"9780306406157"}).Return([]string{}, nil)
+			},
+		},
+		{
+			name:      "transaction failure",
+			rows:      []*model.BookImportRow{validRow(2)},
+			wantErrIs: apperror.ErrTimeout,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).Return(apperror.Timeout("query timed out", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+			expectTransactions(mockRepo)
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			got, err := svc.ImportBooks(context.Background(), tt.rows, tt.dryRun)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				if tt.wantFields != nil {
+					e, _ := apperror.As(err)
+					assert.Equal(t, tt.wantFields, e.Fields)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBookService_ListDeletedBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	deletedAt := time.Now()
+	query := &model.PageQuery{Limit: 20, Offset: 40}
+
+	mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+	mockRepo.EXPECT().ListDeletedBooks(gomock.Any(), query).
+		Return(model.Books{{Model: gorm.Model{ID: 1, DeletedAt: &deletedAt}, Title: "title", Version: 3}}, int64(41), nil)
+
+	svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+	got, err := svc.ListDeletedBooks(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(41), got.Total)
+	assert.Equal(t, 40, got.Offset)
+	assert.Len(t, got.Data, 1)
+	assert.Equal(t, uint(1), got.Data[0].ID)
+	assert.Equal(t, deletedAt, got.Data[0].DeletedAt)
+}
+
+func TestBookService_RestoreBook(t *testing.T) {
+	tests := []struct {
+		name        string
+		want        *model.BookDto
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "success call",
+			want: &model.BookDto{ID: 1, Title: "title", PublishedDate: "0001-01-01", Version: 4},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				gomock.InOrder(
+					mockRepo.EXPECT().RestoreBook(gomock.Any(), uint(1)).Return(nil),
+					mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}, Title: "title", Version: 4}, nil),
+				)
+			},
+		},
+		{
+			name:      "not in trash",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().RestoreBook(gomock.Any(), uint(1)).Return(apperror.NotFound("deleted book not found", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+			expectTransactions(mockRepo)
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			got, err := svc.RestoreBook(context.Background(), 1)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBookService_AuditTrail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+	before := &model.Book{Model: gorm.Model{ID: 1}, Title: "old", Author: "author", Version: 2}
+	gomock.InOrder(
+		mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repository.BookRepoInterface) error) error {
+			return fn(mockRepo)
+		}),
+		mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(before, nil),
+		mockRepo.EXPECT().UpdateBook(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, book *model.Book) error {
+			book.Version++
+			return nil
+		}),
+		mockRepo.EXPECT().CreateAudits(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, audits []*model.BookAudit) error {
+			assert.Len(t, audits, 1)
+			assert.Equal(t, model.BookAuditUpdate, audits[0].Action)
+			assert.Equal(t, uint(1), audits[0].BookID)
+			assert.Equal(t, "alice", audits[0].Actor)
+			assert.Equal(t, "req-1", audits[0].RequestID)
+			assert.Equal(t, "old", audits[0].Before.Title)
+			assert.Equal(t, uint(2), audits[0].Before.Version)
+			assert.Equal(t, "new", audits[0].After.Title)
+			assert.Equal(t, uint(3), audits[0].After.Version)
+			return nil
+		}),
+	)
+
+	svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+	ctx := actor.NewContext(requestid.NewContext(context.Background(), "req-1"), "alice")
+	_, err := svc.UpdateBook(ctx, 1, &model.BookForm{Title: "new", Author: "author", PublishedDate: "2020-01-01"}, []uint{2})
+	assert.NoError(t, err)
+}
+
+func TestBookService_GetBookHistory(t *testing.T) {
+	tests := []struct {
+		name        string
+		want        *model.BookHistoryDto
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "success call",
+			want: &model.BookHistoryDto{
+				Data:  []model.BookAuditDto{{ID: 2, Action: model.BookAuditDelete, Actor: "alice", Before: &model.BookSnapshot{Title: "title"}}},
+				Total: 2,
+				Limit: 1,
+			},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ListAudits(gomock.Any(), uint(1), &model.PageQuery{Limit: 1}).Return([]*model.BookAudit{
+					{ID: 2, BookID: 1, Action: model.BookAuditDelete, Actor: "alice", Before: &model.BookSnapshot{Title: "title"}},
+				}, int64(2), nil)
+			},
+		},
+		{
+			name: "no history yet",
+			want: &model.BookHistoryDto{Data: []model.BookAuditDto{}, Limit: 1},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ListAudits(gomock.Any(), uint(1), gomock.Any()).Return([]*model.BookAudit{}, int64(0), nil)
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}}, nil)
+			},
+		},
+		{
+			name:      "unknown book",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ListAudits(gomock.Any(), uint(1), gomock.Any()).Return([]*model.BookAudit{}, int64(0), nil)
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+
+			svc := NewBookService(mockRepo, nil, validator.New(), 10)
+
+			got, err := svc.GetBookHistory(context.Background(), 1, &model.PageQuery{Limit: 1})
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/actor"
+	"myapp/util/policy"
+)
+
+type CollectionService struct {
+	collectionRepo repository.CollectionRepoInterface
+}
+
+func NewCollectionService(collectionRepo repository.CollectionRepoInterface) *CollectionService {
+	return &CollectionService{collectionRepo: collectionRepo}
+}
+
+type CollectionServiceInterface interface {
+	CreateCollection(ctx context.Context, collection *model.CollectionForm) (*model.CollectionDto, error)
+	GetCollectionByID(ctx context.Context, id uint) (*model.CollectionDto, error)
+	GetListCollection(ctx context.Context, query *model.PageQuery) (*model.CollectionListDto, error)
+	UpdateCollection(ctx context.Context, id uint, collection *model.CollectionForm) error
+	DeleteCollection(ctx context.Context, id uint) error
+}
+
+// CreateCollection creates a collection owned by the actor of the request and
+// returns it with its books.
+func (c *CollectionService) CreateCollection(ctx context.Context, collection *model.CollectionForm) (_ *model.CollectionDto, err error) {
+	ctx, span := tracer.Start(ctx, "CollectionService.CreateCollection")
+	defer func() { endSpan(span, err) }()
+
+	collectionModel := collection.ToModel()
+	collectionModel.Owner = actor.FromContext(ctx)
+
+	var books model.Books
+	err = c.collectionRepo.Transaction(ctx, func(tx repository.CollectionRepoInterface) error {
+		if _, err := tx.CreateCollection(ctx, collectionModel); err != nil {
+			return err
+		}
+
+		if err := tx.SetCollectionBooks(ctx, collectionModel.ID, collectionModel.BookIDs); err != nil {
+			return err
+		}
+
+		books, err = tx.CollectionBooks(ctx, collectionModel.ID)
+		return err
+	})
+	if err != nil {
+		return &model.CollectionDto{}, err
+	}
+
+	dto := collectionModel.ToDto()
+	dto.Books = books.ToDto()
+
+	return dto, nil
+}
+
+// GetCollectionByID returns the collection with its books in order.
+func (c *CollectionService) GetCollectionByID(ctx context.Context, id uint) (_ *model.CollectionDto, err error) {
+	ctx, span := tracer.Start(ctx, "CollectionService.GetCollectionByID")
+	defer func() { endSpan(span, err) }()
+
+	collection, err := c.collectionRepo.ReadCollection(ctx, id)
+	if err != nil {
+		return &model.CollectionDto{}, err
+	}
+
+	books, err := c.collectionRepo.CollectionBooks(ctx, id)
+	if err != nil {
+		return &model.CollectionDto{}, err
+	}
+
+	dto := collection.ToDto()
+	dto.Books = books.ToDto()
+
+	return dto, nil
+}
+
+// GetListCollection returns a page of the collections, without their books.
+func (c *CollectionService) GetListCollection(ctx context.Context, query *model.PageQuery) (_ *model.CollectionListDto, err error) {
+	ctx, span := tracer.Start(ctx, "CollectionService.GetListCollection")
+	defer func() { endSpan(span, err) }()
+
+	collections, total, err := c.collectionRepo.ListCollections(ctx, query)
+	if err != nil {
+		return &model.CollectionListDto{}, err
+	}
+
+	return &model.CollectionListDto{
+		Data:   collections.ToDto(),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}, nil
+}
+
+// UpdateCollection replaces the collection, its books included. Collections
+// of others also require policy.BooksWriteAny.
+func (c *CollectionService) UpdateCollection(ctx context.Context, id uint, collection *model.CollectionForm) (err error) {
+	ctx, span := tracer.Start(ctx, "CollectionService.UpdateCollection")
+	defer func() { endSpan(span, err) }()
+
+	collectionModel := collection.ToModel()
+	collectionModel.ID = id
+
+	return c.collectionRepo.Transaction(ctx, func(tx repository.CollectionRepoInterface) error {
+		current, err := tx.LockCollection(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := authorizeCollection(ctx, current, policy.BooksWrite); err != nil {
+			return err
+		}
+
+		if err := tx.UpdateCollection(ctx, collectionModel); err != nil {
+			return err
+		}
+
+		return tx.SetCollectionBooks(ctx, id, collectionModel.BookIDs)
+	})
+}
+
+// DeleteCollection deletes the collection; collections of others also
+// require policy.BooksWriteAny.
+func (c *CollectionService) DeleteCollection(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "CollectionService.DeleteCollection")
+	defer func() { endSpan(span, err) }()
+
+	return c.collectionRepo.Transaction(ctx, func(tx repository.CollectionRepoInterface) error {
+		current, err := tx.LockCollection(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := authorizeCollection(ctx, current, policy.BooksDelete); err != nil {
+			return err
+		}
+
+		return tx.DeleteCollection(ctx, id)
+	})
+}
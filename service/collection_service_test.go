@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/actor"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	mock_repository "myapp/mocks/repository"
+	"myapp/util/apperror"
+	"myapp/util/policy"
+)
+
+// expectCollectionTransactions runs the transactions of the service on the
+// mock repository itself.
+func expectCollectionTransactions(mockRepo *mock_repository.MockCollectionRepoInterface) {
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(tx repository.CollectionRepoInterface) error) error {
+			return fn(mockRepo)
+		}).AnyTimes()
+}
+
+func TestCollectionService_CreateCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockCollectionRepoInterface(ctrl)
+	mockRepo.EXPECT().CreateCollection(gomock.Any(), &model.Collection{Name: "Favorites", Owner: "alice", BookIDs: []uint{3, 1}}).
+		DoAndReturn(func(ctx context.Context, collection *model.Collection) (*model.Collection, error) {
+			collection.ID = 1
+			return collection, nil
+		})
+	mockRepo.EXPECT().SetCollectionBooks(gomock.Any(), uint(1), []uint{3, 1}).Return(nil)
+	mockRepo.EXPECT().CollectionBooks(gomock.Any(), uint(1)).
+		Return(model.Books{{Model: gorm.Model{ID: 3}, Title: "The Hobbit"}, {Model: gorm.Model{ID: 1}, Title: "Dune"}}, nil)
+	expectCollectionTransactions(mockRepo)
+
+	svc := NewCollectionService(mockRepo)
+
+	got, err := svc.CreateCollection(actor.NewContext(context.Background(), "alice"), &model.CollectionForm{Name: "Favorites", BookIDs: []uint{3, 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.CollectionDto{
+		ID:    1,
+		Name:  "Favorites",
+		Owner: "alice",
+		Books: []model.BookDto{
+			{ID: 3, Title: "The Hobbit", PublishedDate: "0001-01-01"},
+			{ID: 1, Title: "Dune", PublishedDate: "0001-01-01"},
+		},
+	}, got)
+}
+
+func TestCollectionService_UpdateCollection(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockCollectionRepoInterface)
+	}{
+		{
+			name: "success call",
+			prepareMock: func(mockRepo *mock_repository.MockCollectionRepoInterface) {
+				mockRepo.EXPECT().LockCollection(gomock.Any(), uint(1)).Return(&model.Collection{Model: gorm.Model{ID: 1}, Owner: "alice"}, nil)
+				mockRepo.EXPECT().UpdateCollection(gomock.Any(), &model.Collection{Model: gorm.Model{ID: 1}, Name: "Favorites", BookIDs: []uint{2}}).Return(nil)
+				mockRepo.EXPECT().SetCollectionBooks(gomock.Any(), uint(1), []uint{2}).Return(nil)
+			},
+		},
+		{
+			name:      "unknown collection",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockCollectionRepoInterface) {
+				mockRepo.EXPECT().LockCollection(gomock.Any(), uint(1)).Return(nil, apperror.NotFound("collection not found", nil))
+			},
+		},
+		{
+			name:      "unknown book",
+			wantErrIs: apperror.ErrValidation,
+			prepareMock: func(mockRepo *mock_repository.MockCollectionRepoInterface) {
+				mockRepo.EXPECT().LockCollection(gomock.Any(), uint(1)).Return(&model.Collection{Model: gorm.Model{ID: 1}, Owner: "alice"}, nil)
+				mockRepo.EXPECT().UpdateCollection(gomock.Any(), gomock.Any()).Return(nil)
+				mockRepo.EXPECT().SetCollectionBooks(gomock.Any(), uint(1), []uint{2}).
+					Return(apperror.Validation("invalid collection", nil, apperror.FieldError{Name: "book_ids", Reason: "contains unknown books"}))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockCollectionRepoInterface(ctrl)
+			tt.prepareMock(mockRepo)
+			expectCollectionTransactions(mockRepo)
+
+			svc := NewCollectionService(mockRepo)
+
+			err := svc.UpdateCollection(context.Background(), 1, &model.CollectionForm{Name: "Favorites", BookIDs: []uint{2}})
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCollectionService_Authorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		owner     string
+		delete    bool
+		wantErrIs error
+	}{
+		{name: "editor updates own collection", ctx: withPrincipal("alice", policy.RoleEditor), owner: "alice"},
+		{name: "editor updates collection of another", ctx: withPrincipal("alice", policy.RoleEditor), owner: "bob", wantErrIs: apperror.ErrForbidden},
+		{name: "admin updates collection of another", ctx: withPrincipal("carol", policy.RoleAdmin), owner: "bob"},
+		{name: "reader updates own collection", ctx: withPrincipal("dave", policy.RoleReader), owner: "dave", wantErrIs: apperror.ErrForbidden},
+		{name: "editor deletes own collection", ctx: withPrincipal("alice", policy.RoleEditor), owner: "alice", delete: true, wantErrIs: apperror.ErrForbidden},
+		{name: "admin deletes collection of another", ctx: withPrincipal("carol", policy.RoleAdmin), owner: "bob", delete: true},
+		{name: "without authentication", ctx: context.Background(), owner: "bob", delete: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockCollectionRepoInterface(ctrl)
+			mockRepo.EXPECT().LockCollection(gomock.Any(), uint(1)).Return(&model.Collection{Model: gorm.Model{ID: 1}, Owner: tt.owner}, nil)
+			if tt.wantErrIs == nil {
+				mockRepo.EXPECT().UpdateCollection(gomock.Any(), gomock.Any()).Return(nil).MaxTimes(1)
+				mockRepo.EXPECT().SetCollectionBooks(gomock.Any(), uint(1), gomock.Any()).Return(nil).MaxTimes(1)
+				mockRepo.EXPECT().DeleteCollection(gomock.Any(), uint(1)).Return(nil).MaxTimes(1)
+			}
+			expectCollectionTransactions(mockRepo)
+
+			svc := NewCollectionService(mockRepo)
+
+			var err error
+			if tt.delete {
+				err = svc.DeleteCollection(tt.ctx, 1)
+			} else {
+				err = svc.UpdateCollection(tt.ctx, 1, &model.CollectionForm{Name: "Favorites"})
+			}
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
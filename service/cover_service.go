@@ -0,0 +1,267 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"myapp/adapter/blob"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/apperror"
+	"myapp/util/logger"
+	"myapp/util/policy"
+	"myapp/util/thumbnail"
+	"net/http"
+	"sort"
+
+	// Decoders of the cover types.
+	_ "image/gif"
+	_ "image/png"
+)
+
+// maxCoverPixels bounds the decoded size of covers, whose files may be far
+// smaller than the images they decode to.
+const maxCoverPixels = 25_000_000
+
+// thumbnailQuality is the JPEG quality of the cover thumbnails.
+const thumbnailQuality = 85
+
+type CoverService struct {
+	bookRepo repository.BookRepoInterface
+	store    blob.Store
+	maxSize  int64
+}
+
+// NewCoverService returns the service of the covers of the books, which are
+// kept with their thumbnails in store. Covers are at most maxSize bytes.
+func NewCoverService(bookRepo repository.BookRepoInterface, store blob.Store, maxSize int64) *CoverService {
+	return &CoverService{bookRepo: bookRepo, store: store, maxSize: maxSize}
+}
+
+type CoverServiceInterface interface {
+	SetCover(ctx context.Context, id uint, r io.Reader) (*model.BookDto, error)
+	GetCover(ctx context.Context, id uint, size string) (*model.Cover, error)
+	DeleteCover(ctx context.Context, id uint) error
+}
+
+// SetCover stores the image read from r as the cover of the book, together
+// with its thumbnails, and returns the book.
+func (c *CoverService) SetCover(ctx context.Context, id uint, r io.Reader) (_ *model.BookDto, err error) {
+	ctx, span := tracer.Start(ctx, "CoverService.SetCover")
+	defer func() { endSpan(span, err) }()
+
+	data, err := io.ReadAll(io.LimitReader(r, c.maxSize+1))
+	if err != nil {
+		return nil, apperror.Validation("invalid cover", err, apperror.FieldError{Name: "cover", Reason: "could not be read"})
+	}
+	if int64(len(data)) > c.maxSize {
+		return nil, apperror.Validation("cover too large", nil, apperror.FieldError{Name: "cover", Reason: fmt.Sprintf("must be a maximum of %d bytes", c.maxSize)})
+	}
+
+	contentType := http.DetectContentType(data)
+	if !model.CoverTypes[contentType] {
+		return nil, apperror.Validation("unsupported cover type", nil, apperror.FieldError{Name: "cover", Reason: "must be a JPEG, PNG or GIF image"})
+	}
+
+	// Checked before the cover is decoded and stored, and again once the
+	// book is locked.
+	current, err := c.bookRepo.ReadBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeBook(ctx, current, policy.BooksWrite); err != nil {
+		return nil, err
+	}
+
+	thumbnails, err := coverThumbnails(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// The blobs are stored under keys of their own before the book is
+	// locked, and the book switches to them in the transaction: concurrent
+	// uploads can't mix their blobs, and the last one to commit wins.
+	coverKey, err := model.NewCoverKey(id)
+	if err != nil {
+		return nil, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			deleteCoverBlobs(ctx, c.store, coverKey)
+		}
+	}()
+
+	for size, thumb := range thumbnails {
+		if err := c.store.Put(ctx, model.CoverKey(coverKey, size), model.CoverThumbnailType, thumb); err != nil {
+			return nil, fmt.Errorf("store cover thumbnail: %w", err)
+		}
+	}
+	if err := c.store.Put(ctx, model.CoverKey(coverKey, ""), contentType, data); err != nil {
+		return nil, fmt.Errorf("store cover: %w", err)
+	}
+
+	var before, book *model.Book
+	err = audited(ctx, c.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		var err error
+		if before, err = tx.LockBook(ctx, id, false); err != nil {
+			return nil, err
+		}
+		if err := authorizeBook(ctx, before, policy.BooksWrite); err != nil {
+			return nil, err
+		}
+
+		if err := tx.SetBookCover(ctx, id, contentType, coverKey); err != nil {
+			return nil, err
+		}
+
+		after := *before
+		after.CoverType = contentType
+		after.CoverKey = coverKey
+		after.Version++
+		if after.AuthorIDs, err = tx.BookAuthorIDs(ctx, id); err != nil {
+			return nil, err
+		}
+		book = &after
+
+		return []*model.BookAudit{newAudit(ctx, model.BookAuditUpdate, id, before, &after)}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	committed = true
+
+	// The book doesn't reference the blobs of the previous cover anymore.
+	if before.CoverKey != "" {
+		deleteCoverBlobs(ctx, c.store, before.CoverKey)
+	}
+
+	return book.ToDto(), nil
+}
+
+// coverThumbnails decodes the cover and returns its thumbnails by size, JPEG
+// encoded.
+func coverThumbnails(data []byte) (map[string][]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperror.Validation("invalid cover", err, apperror.FieldError{Name: "cover", Reason: "must be a valid image"})
+	}
+	if config.Width*config.Height > maxCoverPixels {
+		return nil, apperror.Validation("cover too large", nil, apperror.FieldError{Name: "cover", Reason: fmt.Sprintf("must be a maximum of %d pixels", maxCoverPixels)})
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, apperror.Validation("invalid cover", err, apperror.FieldError{Name: "cover", Reason: "must be a valid image"})
+	}
+
+	// Each thumbnail is scaled from the next larger one, which is much
+	// cheaper than scaling them all from the cover.
+	sizes := make([]string, 0, len(model.CoverSizes))
+	for size := range model.CoverSizes {
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return model.CoverSizes[sizes[i]] > model.CoverSizes[sizes[j]] })
+
+	thumbnails := make(map[string][]byte, len(sizes))
+	scaled := thumbnail.Flatten(img)
+	for _, size := range sizes {
+		scaled = thumbnail.Fit(scaled, model.CoverSizes[size])
+
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+			return nil, err
+		}
+		thumbnails[size] = buf.Bytes()
+	}
+
+	return thumbnails, nil
+}
+
+// GetCover returns the cover of the book, or its thumbnail of the size when
+// size isn't empty.
+func (c *CoverService) GetCover(ctx context.Context, id uint, size string) (_ *model.Cover, err error) {
+	ctx, span := tracer.Start(ctx, "CoverService.GetCover")
+	defer func() { endSpan(span, err) }()
+
+	if _, ok := model.CoverSizes[size]; size != "" && !ok {
+		return nil, apperror.NotFound("unknown cover size", nil)
+	}
+
+	book, err := c.bookRepo.ReadBook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if book.CoverType == "" {
+		return nil, apperror.NotFound("book has no cover", nil)
+	}
+
+	obj, err := c.store.Get(ctx, model.CoverKey(book.CoverKey, size))
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, apperror.NotFound("cover not found", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cover := &model.Cover{Body: obj.Body, Size: obj.Size, ContentType: book.CoverType}
+	if size != "" {
+		cover.ContentType = model.CoverThumbnailType
+	}
+
+	return cover, nil
+}
+
+// DeleteCover removes the cover of the book, which falls back to its ImageUrl.
+func (c *CoverService) DeleteCover(ctx context.Context, id uint) (err error) {
+	ctx, span := tracer.Start(ctx, "CoverService.DeleteCover")
+	defer func() { endSpan(span, err) }()
+
+	var coverKey string
+	err = audited(ctx, c.bookRepo, func(tx repository.BookRepoInterface) ([]*model.BookAudit, error) {
+		before, err := tx.LockBook(ctx, id, false)
+		if err != nil {
+			return nil, err
+		}
+		if err := authorizeBook(ctx, before, policy.BooksWrite); err != nil {
+			return nil, err
+		}
+		if before.CoverType == "" {
+			return nil, apperror.NotFound("book has no cover", nil)
+		}
+
+		if err := tx.SetBookCover(ctx, id, "", ""); err != nil {
+			return nil, err
+		}
+		coverKey = before.CoverKey
+
+		after := *before
+		after.CoverType = ""
+		after.CoverKey = ""
+		after.Version++
+
+		return []*model.BookAudit{newAudit(ctx, model.BookAuditUpdate, id, before, &after)}, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteCoverBlobs(ctx, c.store, coverKey)
+
+	return nil
+}
+
+// deleteCoverBlobs removes the blobs of the cover uploaded under the prefix
+// coverKey, once no book references them. Failures are only logged: the blobs
+// left behind are only wasted space.
+func deleteCoverBlobs(ctx context.Context, store blob.Store, coverKey string) {
+	for _, key := range model.CoverKeys(coverKey) {
+		if err := store.Delete(ctx, key); err != nil {
+			logger.Ctx(ctx).Warn().Err(err).Str("key", key).Msg("Cover blob deletion failed")
+		}
+	}
+}
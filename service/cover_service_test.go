@@ -0,0 +1,323 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"myapp/adapter/blob"
+	"myapp/model"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	mock_repository "myapp/mocks/repository"
+	"myapp/util/apperror"
+)
+
+// pngCover returns a PNG image of the size.
+func pngCover(t *testing.T, width, height int) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestCoverService_SetCover(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		want        *model.BookDto
+		wantErrIs   error
+		replaced    bool
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "success call",
+			data: pngCover(t, 1000, 500),
+			want: &model.BookDto{
+				ID:            1,
+				Title:         "title",
+				PublishedDate: "0001-01-01",
+				ImageUrl:      "/api/v1/books/1/cover",
+				Version:       3,
+				AuthorIDs:     []uint{},
+				Thumbnails: map[string]string{
+					"small":  "/api/v1/books/1/cover/small",
+					"medium": "/api/v1/books/1/cover/medium",
+					"large":  "/api/v1/books/1/cover/large",
+				},
+			},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				book := &model.Book{Model: gorm.Model{ID: 1}, Title: "title", ImageUrl: "http://example.com/cover.png", Version: 2}
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(book, nil)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(book, nil)
+				mockRepo.EXPECT().BookAuthorIDs(gomock.Any(), uint(1)).Return([]uint{}, nil)
+			},
+		},
+		{
+			name:     "replace cover",
+			data:     pngCover(t, 1000, 500),
+			replaced: true,
+			want: &model.BookDto{
+				ID:            1,
+				Title:         "title",
+				PublishedDate: "0001-01-01",
+				ImageUrl:      "/api/v1/books/1/cover",
+				Version:       3,
+				AuthorIDs:     []uint{},
+				Thumbnails: map[string]string{
+					"small":  "/api/v1/books/1/cover/small",
+					"medium": "/api/v1/books/1/cover/medium",
+					"large":  "/api/v1/books/1/cover/large",
+				},
+			},
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				book := &model.Book{Model: gorm.Model{ID: 1}, Title: "title", CoverType: "image/png", CoverKey: "covers/1/old", Version: 2}
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(book, nil)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(book, nil)
+				mockRepo.EXPECT().BookAuthorIDs(gomock.Any(), uint(1)).Return([]uint{}, nil)
+			},
+		},
+		{
+			name:      "failed switch",
+			data:      pngCover(t, 10, 10),
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}}, nil)
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+		{
+			name:      "not an image",
+			data:      []byte("%PDF-1.4"),
+			wantErrIs: apperror.ErrValidation,
+		},
+		{
+			name:      "too large",
+			data:      bytes.Repeat([]byte{0}, 1<<20+1),
+			wantErrIs: apperror.ErrValidation,
+		},
+		{
+			name:      "truncated image",
+			data:      pngCover(t, 100, 100)[:60],
+			wantErrIs: apperror.ErrValidation,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}}, nil)
+			},
+		},
+		{
+			name:      "unknown book",
+			data:      pngCover(t, 10, 10),
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+			expectTransactions(mockRepo)
+
+			dir := t.TempDir()
+			store, err := blob.NewFileStore(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range model.CoverKeys("covers/1/old") {
+				if err := store.Put(context.Background(), key, "image/png", []byte("old")); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var coverKey string
+			mockRepo.EXPECT().SetBookCover(gomock.Any(), uint(1), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, id uint, coverType, key string) error {
+					assert.Equal(t, "image/png", coverType)
+					coverKey = key
+					return nil
+				}).AnyTimes()
+
+			svc := NewCoverService(mockRepo, store, 1<<20)
+
+			got, err := svc.SetCover(context.Background(), 1, bytes.NewReader(tt.data))
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				// The blobs of a failed upload are removed, not those of
+				// the current cover.
+				assert.ElementsMatch(t, model.CoverKeys("covers/1/old"), storedKeys(t, dir))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Regexp(t, `^covers/1/[0-9a-f]{16}$`, coverKey)
+			// The blobs of the replaced cover are removed, the others are
+			// kept.
+			wantKeys := model.CoverKeys(coverKey)
+			if !tt.replaced {
+				wantKeys = append(wantKeys, model.CoverKeys("covers/1/old")...)
+			}
+			assert.ElementsMatch(t, wantKeys, storedKeys(t, dir))
+
+			for size, side := range model.CoverSizes {
+				obj, err := store.Get(context.Background(), model.CoverKey(coverKey, size))
+				if err != nil {
+					t.Fatal(err)
+				}
+				thumb, err := jpeg.Decode(obj.Body)
+				obj.Body.Close()
+				assert.NoError(t, err)
+				assert.Equal(t, image.Rect(0, 0, side, side/2), thumb.Bounds(), size)
+			}
+		})
+	}
+}
+
+func TestCoverService_GetCover(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := blob.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put(ctx, model.CoverKey("covers/1/a", "small"), model.CoverThumbnailType, []byte("small")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		size        string
+		want        string
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockBookRepoInterface)
+	}{
+		{
+			name: "thumbnail",
+			size: "small",
+			want: "small",
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}, CoverType: "image/png", CoverKey: "covers/1/a"}, nil)
+			},
+		},
+		{
+			name:      "unknown size",
+			size:      "huge",
+			wantErrIs: apperror.ErrNotFound,
+		},
+		{
+			name:      "no cover",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}}, nil)
+			},
+		},
+		{
+			name:      "missing blob",
+			size:      "large",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockBookRepoInterface) {
+				mockRepo.EXPECT().ReadBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}, CoverType: "image/png", CoverKey: "covers/1/a"}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+
+			svc := NewCoverService(mockRepo, store, 1<<20)
+
+			cover, err := svc.GetCover(ctx, 1, tt.size)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+			defer cover.Body.Close()
+
+			data, err := io.ReadAll(cover.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+			assert.Equal(t, model.CoverThumbnailType, cover.ContentType)
+		})
+	}
+}
+
+func TestCoverService_DeleteCover(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	store, err := blob.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []string{"", "small"} {
+		if err := store.Put(ctx, model.CoverKey("covers/1/a", size), "image/png", []byte("cover")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mockRepo := mock_repository.NewMockBookRepoInterface(ctrl)
+	mockRepo.EXPECT().LockBook(gomock.Any(), uint(1), false).Return(&model.Book{Model: gorm.Model{ID: 1}, CoverType: "image/png", CoverKey: "covers/1/a", Version: 2}, nil)
+	mockRepo.EXPECT().SetBookCover(gomock.Any(), uint(1), "", "").Return(nil)
+	mockRepo.EXPECT().CreateAudits(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, audits []*model.BookAudit) error {
+		assert.Equal(t, "image/png", audits[0].Before.CoverType)
+		assert.Equal(t, "", audits[0].After.CoverType)
+		assert.Equal(t, uint(3), audits[0].After.Version)
+		return nil
+	})
+	expectTransactions(mockRepo)
+
+	svc := NewCoverService(mockRepo, store, 1<<20)
+
+	assert.NoError(t, svc.DeleteCover(ctx, 1))
+
+	for _, size := range []string{"", "small"} {
+		_, err := store.Get(ctx, model.CoverKey("covers/1/a", size))
+		assert.ErrorIs(t, err, blob.ErrNotFound, size)
+	}
+}
+
+// storedKeys returns the keys of the blobs of the file store in dir.
+func storedKeys(t *testing.T, dir string) []string {
+	var keys []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		key, err := filepath.Rel(dir, path)
+		keys = append(keys, filepath.ToSlash(key))
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return keys
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/logger"
+	"time"
+)
+
+// healthCheckTimeout bounds each readiness check so a hung database can't stall the probe.
+const healthCheckTimeout = 2 * time.Second
+
+// healthCheckFailed is the reason reported for a failed check. The probe is
+// unauthenticated, so the underlying error is only logged.
+const healthCheckFailed = "check failed"
+
+type HealthService struct {
+	healthRepo repository.HealthRepoInterface
+}
+
+func NewHealthService(healthRepo repository.HealthRepoInterface) *HealthService {
+	return &HealthService{healthRepo: healthRepo}
+}
+
+type HealthServiceInterface interface {
+	Liveness(ctx context.Context) *model.HealthDto
+	Readiness(ctx context.Context) *model.HealthDto
+}
+
+func (h *HealthService) Liveness(ctx context.Context) *model.HealthDto {
+	_, span := tracer.Start(ctx, "HealthService.Liveness")
+	defer span.End()
+
+	return &model.HealthDto{Status: model.HealthStatusUp}
+}
+
+func (h *HealthService) Readiness(ctx context.Context) *model.HealthDto {
+	ctx, span := tracer.Start(ctx, "HealthService.Readiness")
+	defer span.End()
+
+	health := &model.HealthDto{Status: model.HealthStatusUp}
+
+	checks := []struct {
+		name string
+		fn   func(ctx context.Context) error
+	}{
+		{"database", h.healthRepo.Ping},
+		{"migrations", h.checkMigrations},
+	}
+
+	for _, c := range checks {
+		check := runCheck(ctx, c.name, c.fn)
+		if check.Status != model.HealthStatusUp {
+			health.Status = model.HealthStatusDown
+		}
+
+		health.Checks = append(health.Checks, check)
+	}
+
+	return health
+}
+
+func (h *HealthService) checkMigrations(ctx context.Context) error {
+	current, err := h.healthRepo.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	latest, err := h.healthRepo.LatestMigration()
+	if err != nil {
+		return err
+	}
+
+	if current < latest {
+		return fmt.Errorf("database schema is at version %d, expected %d", current, latest)
+	}
+
+	return nil
+}
+
+func runCheck(ctx context.Context, name string, fn func(ctx context.Context) error) model.HealthCheckDto {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	ctx, span := tracer.Start(ctx, "HealthService.check."+name)
+	start := time.Now()
+	err := fn(ctx)
+	endSpan(span, err)
+
+	check := model.HealthCheckDto{
+		Name:      name,
+		Status:    model.HealthStatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		logger.Ctx(ctx).Warn().Err(err).Str("check", name).Msg("Readiness check failed")
+		check.Status = model.HealthStatusDown
+		check.Error = healthCheckFailed
+	}
+
+	return check
+}
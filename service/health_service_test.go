@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"myapp/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock_repository "myapp/mocks/repository"
+)
+
+func TestHealthService_Readiness(t *testing.T) {
+	tests := []struct {
+		name        string
+		wantStatus  string
+		wantErrors  map[string]string
+		prepareMock func(mockRepo *mock_repository.MockHealthRepoInterface)
+	}{
+		{
+			name:       "ready",
+			wantStatus: model.HealthStatusUp,
+			prepareMock: func(mockRepo *mock_repository.MockHealthRepoInterface) {
+				mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+				mockRepo.EXPECT().MigrationVersion(gomock.Any()).Return(int64(2), nil)
+				mockRepo.EXPECT().LatestMigration().Return(int64(2), nil)
+			},
+		},
+		{
+			name:       "database down",
+			wantStatus: model.HealthStatusDown,
+			wantErrors: map[string]string{"database": healthCheckFailed, "migrations": healthCheckFailed},
+			prepareMock: func(mockRepo *mock_repository.MockHealthRepoInterface) {
+				mockRepo.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused"))
+				mockRepo.EXPECT().MigrationVersion(gomock.Any()).Return(int64(0), errors.New("connection refused"))
+			},
+		},
+		{
+			name:       "pending migrations",
+			wantStatus: model.HealthStatusDown,
+			wantErrors: map[string]string{"migrations": healthCheckFailed},
+			prepareMock: func(mockRepo *mock_repository.MockHealthRepoInterface) {
+				mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+				mockRepo.EXPECT().MigrationVersion(gomock.Any()).Return(int64(1), nil)
+				mockRepo.EXPECT().LatestMigration().Return(int64(2), nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockHealthRepoInterface(ctrl)
+
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+
+			svc := NewHealthService(mockRepo)
+
+			resp := svc.Readiness(context.Background())
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			assert.Len(t, resp.Checks, 2)
+
+			for _, check := range resp.Checks {
+				assert.Equal(t, tt.wantErrors[check.Name], check.Error)
+			}
+		})
+	}
+}
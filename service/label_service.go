@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"myapp/model"
+	"myapp/repository"
+	"myapp/util/apperror"
+	"myapp/util/policy"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLabelName is the length of the name columns of tags and genres.
+const maxLabelName = 64
+
+type LabelService struct {
+	labelRepo repository.LabelRepoInterface
+}
+
+func NewLabelService(labelRepo repository.LabelRepoInterface) *LabelService {
+	return &LabelService{labelRepo: labelRepo}
+}
+
+type LabelServiceInterface interface {
+	GetListLabel(ctx context.Context, kind model.LabelKind, query *model.PageQuery) (*model.LabelListDto, error)
+	CreateLabel(ctx context.Context, kind model.LabelKind, label *model.LabelForm) (*model.LabelDto, error)
+	DeleteLabel(ctx context.Context, kind model.LabelKind, name string) error
+	GetBookLabels(ctx context.Context, kind model.LabelKind, bookID uint) (*model.BookLabelListDto, error)
+	AttachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) error
+	DetachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) error
+}
+
+func (l *LabelService) GetListLabel(ctx context.Context, kind model.LabelKind, query *model.PageQuery) (_ *model.LabelListDto, err error) {
+	ctx, span := tracer.Start(ctx, "LabelService.GetListLabel")
+	defer func() { endSpan(span, err) }()
+
+	labels, total, err := l.labelRepo.ListLabels(ctx, kind, query)
+	if err != nil {
+		return &model.LabelListDto{}, err
+	}
+
+	return &model.LabelListDto{
+		Data:   labels.ToDto(),
+		Total:  total,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}, nil
+}
+
+func (l *LabelService) CreateLabel(ctx context.Context, kind model.LabelKind, label *model.LabelForm) (_ *model.LabelDto, err error) {
+	ctx, span := tracer.Start(ctx, "LabelService.CreateLabel")
+	defer func() { endSpan(span, err) }()
+
+	name, err := labelName(label.Name)
+	if err != nil {
+		return &model.LabelDto{}, err
+	}
+
+	labelModel, err := l.labelRepo.CreateLabel(ctx, kind, &model.Label{Name: name})
+	if err != nil {
+		return &model.LabelDto{}, err
+	}
+
+	dto := labelModel.ToDto()
+
+	return &dto, nil
+}
+
+// DeleteLabel deletes the label and detaches it from its books.
+func (l *LabelService) DeleteLabel(ctx context.Context, kind model.LabelKind, name string) (err error) {
+	ctx, span := tracer.Start(ctx, "LabelService.DeleteLabel")
+	defer func() { endSpan(span, err) }()
+
+	return l.labelRepo.DeleteLabel(ctx, kind, strings.TrimSpace(name))
+}
+
+func (l *LabelService) GetBookLabels(ctx context.Context, kind model.LabelKind, bookID uint) (_ *model.BookLabelListDto, err error) {
+	ctx, span := tracer.Start(ctx, "LabelService.GetBookLabels")
+	defer func() { endSpan(span, err) }()
+
+	labels, err := l.labelRepo.BookLabels(ctx, kind, bookID)
+	if err != nil {
+		return &model.BookLabelListDto{}, err
+	}
+
+	return &model.BookLabelListDto{Data: labels.ToDto()}, nil
+}
+
+// AttachLabel attaches the label to the book. Tags are created on first use,
+// genres must have been created before.
+func (l *LabelService) AttachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) (err error) {
+	ctx, span := tracer.Start(ctx, "LabelService.AttachLabel")
+	defer func() { endSpan(span, err) }()
+
+	name, err = labelName(name)
+	if err != nil {
+		return err
+	}
+
+	return l.labelRepo.Transaction(ctx, func(tx repository.LabelRepoInterface) error {
+		if err := lockBookForLabels(ctx, tx, bookID); err != nil {
+			return err
+		}
+
+		return tx.AttachLabel(ctx, kind, bookID, name, kind == model.LabelTag)
+	})
+}
+
+func (l *LabelService) DetachLabel(ctx context.Context, kind model.LabelKind, bookID uint, name string) (err error) {
+	ctx, span := tracer.Start(ctx, "LabelService.DetachLabel")
+	defer func() { endSpan(span, err) }()
+
+	return l.labelRepo.Transaction(ctx, func(tx repository.LabelRepoInterface) error {
+		if err := lockBookForLabels(ctx, tx, bookID); err != nil {
+			return err
+		}
+
+		return tx.DetachLabel(ctx, kind, bookID, strings.TrimSpace(name))
+	})
+}
+
+// lockBookForLabels locks the book until the end of the transaction tx, once
+// the principal of the request may change it.
+func lockBookForLabels(ctx context.Context, tx repository.LabelRepoInterface, bookID uint) error {
+	book, err := tx.LockBook(ctx, bookID)
+	if err != nil {
+		return err
+	}
+
+	return authorizeBook(ctx, book, policy.BooksWrite)
+}
+
+// labelName returns the name of a label without surrounding spaces, once it
+// fits the name column.
+func labelName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", apperror.Validation("invalid label", nil, apperror.FieldError{Name: "name", Reason: "is a required field"})
+	}
+
+	if utf8.RuneCountInString(name) > maxLabelName {
+		return "", apperror.Validation("invalid label", nil, apperror.FieldError{Name: "name", Reason: fmt.Sprintf("must be a maximum of %d in length", maxLabelName)})
+	}
+
+	return name, nil
+}
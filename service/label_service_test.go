@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"myapp/model"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jinzhu/gorm"
+	"github.com/stretchr/testify/assert"
+
+	mock_repository "myapp/mocks/repository"
+	"myapp/repository"
+	"myapp/util/apperror"
+	"myapp/util/policy"
+)
+
+// expectLabelTransactions runs the transactions of the service on the mock
+// repository itself.
+func expectLabelTransactions(mockRepo *mock_repository.MockLabelRepoInterface) {
+	mockRepo.EXPECT().Transaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(tx repository.LabelRepoInterface) error) error {
+			return fn(mockRepo)
+		}).AnyTimes()
+}
+
+func TestLabelService_AttachLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		kind        model.LabelKind
+		label       string
+		wantErrIs   error
+		prepareMock func(mockRepo *mock_repository.MockLabelRepoInterface)
+	}{
+		{
+			name:  "tag created on first use",
+			kind:  model.LabelTag,
+			label: " classic ",
+			prepareMock: func(mockRepo *mock_repository.MockLabelRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}}, nil)
+				mockRepo.EXPECT().AttachLabel(gomock.Any(), model.LabelTag, uint(1), "classic", true).Return(nil)
+			},
+		},
+		{
+			name:  "genre must exist",
+			kind:  model.LabelGenre,
+			label: "fantasy",
+			prepareMock: func(mockRepo *mock_repository.MockLabelRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}}, nil)
+				mockRepo.EXPECT().AttachLabel(gomock.Any(), model.LabelGenre, uint(1), "fantasy", false).Return(nil)
+			},
+		},
+		{
+			name:      "unknown book",
+			kind:      model.LabelTag,
+			label:     "classic",
+			wantErrIs: apperror.ErrNotFound,
+			prepareMock: func(mockRepo *mock_repository.MockLabelRepoInterface) {
+				mockRepo.EXPECT().LockBook(gomock.Any(), uint(1)).Return(nil, apperror.NotFound("book not found", nil))
+			},
+		},
+		{
+			name:      "blank name",
+			kind:      model.LabelTag,
+			label:     "  ",
+			wantErrIs: apperror.ErrValidation,
+		},
+		{
+			name:      "name too long",
+			kind:      model.LabelTag,
+			label:     strings.Repeat("a", maxLabelName+1),
+			wantErrIs: apperror.ErrValidation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockLabelRepoInterface(ctrl)
+			if tt.prepareMock != nil {
+				tt.prepareMock(mockRepo)
+			}
+
+			expectLabelTransactions(mockRepo)
+
+			svc := NewLabelService(mockRepo)
+
+			err := svc.AttachLabel(context.Background(), tt.kind, 1, tt.label)
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLabelService_Authorization(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		createdBy string
+		detach    bool
+		wantErrIs error
+	}{
+		{name: "editor tags own book", ctx: withPrincipal("alice", policy.RoleEditor), createdBy: "alice"},
+		{name: "editor tags book of another", ctx: withPrincipal("alice", policy.RoleEditor), createdBy: "bob", wantErrIs: apperror.ErrForbidden},
+		{name: "editor untags book of another", ctx: withPrincipal("alice", policy.RoleEditor), createdBy: "bob", detach: true, wantErrIs: apperror.ErrForbidden},
+		{name: "admin untags book of another", ctx: withPrincipal("carol", policy.RoleAdmin), createdBy: "bob", detach: true},
+		{name: "reader tags own book", ctx: withPrincipal("dave", policy.RoleReader), createdBy: "dave", wantErrIs: apperror.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock_repository.NewMockLabelRepoInterface(ctrl)
+			mockRepo.EXPECT().LockBook(gomock.Any(), uint(1)).Return(&model.Book{Model: gorm.Model{ID: 1}, CreatedBy: tt.createdBy}, nil)
+			if tt.wantErrIs == nil {
+				mockRepo.EXPECT().AttachLabel(gomock.Any(), model.LabelTag, uint(1), "classic", true).Return(nil).MaxTimes(1)
+				mockRepo.EXPECT().DetachLabel(gomock.Any(), model.LabelTag, uint(1), "classic").Return(nil).MaxTimes(1)
+			}
+			expectLabelTransactions(mockRepo)
+
+			svc := NewLabelService(mockRepo)
+
+			var err error
+			if tt.detach {
+				err = svc.DetachLabel(tt.ctx, model.LabelTag, 1, "classic")
+			} else {
+				err = svc.AttachLabel(tt.ctx, model.LabelTag, 1, "classic")
+			}
+
+			if tt.wantErrIs != nil {
+				assert.ErrorIs(t, err, tt.wantErrIs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLabelService_GetListLabel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockRepo := mock_repository.NewMockLabelRepoInterface(ctrl)
+	mockRepo.EXPECT().ListLabels(gomock.Any(), model.LabelGenre, &model.PageQuery{Limit: 1, Offset: 1}).
+		Return(model.Labels{{ID: 2, Name: "science fiction"}}, int64(2), nil)
+
+	svc := NewLabelService(mockRepo)
+
+	got, err := svc.GetListLabel(context.Background(), model.LabelGenre, &model.PageQuery{Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, &model.LabelListDto{
+		Data:   []model.LabelDto{{ID: 2, Name: "science fiction"}},
+		Total:  2,
+		Limit:  1,
+		Offset: 1,
+	}, got)
+}
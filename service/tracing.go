@@ -0,0 +1,24 @@
+package service
+
+import (
+	"myapp/util/apperror"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("myapp/service")
+
+// endSpan records err, if any, and ends span. Domain errors such as not found
+// are expected outcomes, so only internal errors mark the span as failed.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		if apperror.CodeOf(err) == apperror.CodeInternal {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+
+	span.End()
+}
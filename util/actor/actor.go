@@ -0,0 +1,26 @@
+package actor
+
+import "context"
+
+const (
+	// Anonymous is the actor of requests made without credentials.
+	Anonymous = "anonymous"
+
+	// System is the actor of changes made by the application itself, e.g. by scheduled jobs.
+	System = "system"
+)
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, actor)
+}
+
+// FromContext returns the actor stored in ctx, Anonymous when there is none.
+func FromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(ctxKey{}).(string); ok && actor != "" {
+		return actor
+	}
+
+	return Anonymous
+}
@@ -0,0 +1,144 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code is a machine-readable error code exposed to API clients.
+type Code string
+
+const (
+	CodeInternal           Code = "internal"
+	CodeNotFound           Code = "not_found"
+	CodeValidation         Code = "validation_failed"
+	CodeConflict           Code = "conflict"
+	CodePreconditionFailed Code = "precondition_failed"
+	CodeCanceled           Code = "canceled"
+	CodeTimeout            Code = "timeout"
+	CodeAborted            Code = "aborted"
+	CodeUnauthorized       Code = "unauthorized"
+	CodeForbidden          Code = "forbidden"
+	CodeRateLimited        Code = "rate_limited"
+)
+
+// FieldError describes why a single input field is invalid.
+type FieldError struct {
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+
+	// Line is the line of an uploaded file the field is on, for file imports.
+	Line int `json:"line,omitempty"`
+}
+
+// Error is a domain error raised by the repository and service layers.
+// Handlers map its Code to an HTTP status instead of inspecting ORM errors.
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// Sentinels to be used with errors.Is; they match any Error of the same Code.
+var (
+	ErrNotFound           = &Error{Code: CodeNotFound, Message: "resource not found"}
+	ErrValidation         = &Error{Code: CodeValidation, Message: "validation failed"}
+	ErrConflict           = &Error{Code: CodeConflict, Message: "resource conflict"}
+	ErrPreconditionFailed = &Error{Code: CodePreconditionFailed, Message: "precondition failed"}
+	ErrCanceled           = &Error{Code: CodeCanceled, Message: "request canceled"}
+	ErrTimeout            = &Error{Code: CodeTimeout, Message: "request timed out"}
+	ErrAborted            = &Error{Code: CodeAborted, Message: "operation aborted"}
+	ErrUnauthorized       = &Error{Code: CodeUnauthorized, Message: "authentication required"}
+	ErrForbidden          = &Error{Code: CodeForbidden, Message: "permission denied"}
+	ErrRateLimited        = &Error{Code: CodeRateLimited, Message: "rate limit exceeded"}
+)
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func New(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func NotFound(message string, err error) *Error {
+	return New(CodeNotFound, message, err)
+}
+
+func Validation(message string, err error, fields ...FieldError) *Error {
+	e := New(CodeValidation, message, err)
+	e.Fields = fields
+	return e
+}
+
+func Conflict(message string, err error) *Error {
+	return New(CodeConflict, message, err)
+}
+
+func PreconditionFailed(message string, err error) *Error {
+	return New(CodePreconditionFailed, message, err)
+}
+
+// Canceled reports that the caller gave up on the request, e.g. the client disconnected.
+func Canceled(message string, err error) *Error {
+	return New(CodeCanceled, message, err)
+}
+
+// Timeout reports that the request didn't complete within the deadline of the server.
+func Timeout(message string, err error) *Error {
+	return New(CodeTimeout, message, err)
+}
+
+// Aborted reports an operation which wasn't applied because another operation
+// of the same transaction failed.
+func Aborted(message string, err error) *Error {
+	return New(CodeAborted, message, err)
+}
+
+// Unauthorized reports a request without valid credentials.
+func Unauthorized(message string, err error) *Error {
+	return New(CodeUnauthorized, message, err)
+}
+
+// Forbidden reports a request its principal isn't allowed to make.
+func Forbidden(message string, err error) *Error {
+	return New(CodeForbidden, message, err)
+}
+
+// RateLimited reports a request of a client which exhausted its rate limit.
+func RateLimited(message string, err error) *Error {
+	return New(CodeRateLimited, message, err)
+}
+
+// As returns the domain error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+
+	return nil, false
+}
+
+// CodeOf returns the code of the domain error in err's chain, or CodeInternal.
+func CodeOf(err error) Code {
+	if e, ok := As(err); ok {
+		return e.Code
+	}
+
+	return CodeInternal
+}
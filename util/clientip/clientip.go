@@ -0,0 +1,98 @@
+package clientip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const Header = "X-Forwarded-For"
+
+// Resolver finds the IP of the client of a request. The X-Forwarded-For
+// header is only believed when the request comes from a trusted proxy, since
+// clients can send anything in it.
+type Resolver struct {
+	trusted []*net.IPNet
+}
+
+// New returns a resolver trusting the proxies of the IPs or CIDRs in trusted.
+func New(trusted []string) (*Resolver, error) {
+	r := &Resolver{}
+	for _, s := range trusted {
+		cidr := s
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy %q: invalid IP address", s)
+			}
+
+			if ip.To4() != nil {
+				cidr += "/32"
+			} else {
+				cidr += "/128"
+			}
+		}
+
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", s, err)
+		}
+		r.trusted = append(r.trusted, ipNet)
+	}
+
+	return r, nil
+}
+
+// IP returns the IP of the client of req: the peer of the connection, unless
+// it's a trusted proxy. Then it's the last address of X-Forwarded-For which
+// isn't a trusted proxy, proxies appending the address of their peer.
+func (r *Resolver) IP(req *http.Request) string {
+	ip := hostIP(req.RemoteAddr)
+	if !r.isTrusted(ip) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(req.Header.Values(Header), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			// The header is malformed from here on, its peer is as far as
+			// it can be trusted.
+			break
+		}
+
+		ip = hop
+		if !r.isTrusted(ip) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func (r *Resolver) isTrusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, ipNet := range r.trusted {
+		if ipNet.Contains(parsed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hostIP returns the IP of the host:port address addr, or addr itself when it
+// has no port.
+func hostIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
@@ -0,0 +1,51 @@
+package clientip_test
+
+import (
+	"myapp/util/clientip"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolver_IP(t *testing.T) {
+	resolver, err := clientip.New([]string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		want         string
+	}{
+		{name: "direct", remoteAddr: "203.0.113.7:51234", want: "203.0.113.7"},
+		{name: "untrusted peer", remoteAddr: "203.0.113.7:51234", forwardedFor: []string{"198.51.100.1"}, want: "203.0.113.7"},
+		{name: "trusted proxy", remoteAddr: "10.0.0.2:51234", forwardedFor: []string{"198.51.100.1"}, want: "198.51.100.1"},
+		{name: "spoofed hops", remoteAddr: "10.0.0.2:51234", forwardedFor: []string{"1.2.3.4, 198.51.100.1, 192.168.1.1"}, want: "198.51.100.1"},
+		{name: "several headers", remoteAddr: "10.0.0.2:51234", forwardedFor: []string{"1.2.3.4", "198.51.100.1"}, want: "198.51.100.1"},
+		{name: "malformed hop", remoteAddr: "10.0.0.2:51234", forwardedFor: []string{"198.51.100.1, unknown"}, want: "10.0.0.2"},
+		{name: "trusted proxy without header", remoteAddr: "10.0.0.2:51234", want: "10.0.0.2"},
+		{name: "ipv6", remoteAddr: "[fd00::1]:51234", forwardedFor: []string{"2001:db8::1"}, want: "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := http.NewRequest("GET", "/api/v1/books", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for _, v := range tt.forwardedFor {
+				r.Header.Add(clientip.Header, v)
+			}
+
+			assert.Equal(t, tt.want, resolver.IP(r))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := clientip.New([]string{"proxy"})
+	assert.EqualError(t, err, `trusted proxy "proxy": invalid IP address`)
+
+	_, err = clientip.New([]string{"10.0.0.0/33"})
+	assert.EqualError(t, err, `trusted proxy "10.0.0.0/33": invalid CIDR address: 10.0.0.0/33`)
+}
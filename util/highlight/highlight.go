@@ -0,0 +1,114 @@
+package highlight
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	openTag  = "<em>"
+	closeTag = "</em>"
+)
+
+// Snippet returns a window of at most width runes of text around the first matched term,
+// HTML-escaped, with every occurrence of the terms wrapped in <em> tags. Terms
+// only match whole words, as they do in the full-text search.
+// The second value reports whether any term matched.
+func Snippet(text string, terms []string, width int) (string, bool) {
+	first := -1
+	for i := 0; i < len(text) && first < 0; {
+		if matchAt(text, i, terms) > 0 {
+			first = i
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	if first < 0 {
+		return "", false
+	}
+
+	start, end := window(text, first, width)
+
+	snippet := mark(text[start:end], terms)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+
+	return snippet, true
+}
+
+// window picks byte offsets of a rune-aligned window of width runes that starts a little
+// before pos so the match is shown with some leading context.
+func window(text string, pos, width int) (int, int) {
+	if utf8.RuneCountInString(text) <= width {
+		return 0, len(text)
+	}
+
+	start := pos
+	for lead := width / 4; lead > 0 && start > 0; lead-- {
+		_, size := utf8.DecodeLastRuneInString(text[:start])
+		start -= size
+	}
+
+	end := start
+	for n := 0; n < width && end < len(text); n++ {
+		_, size := utf8.DecodeRuneInString(text[end:])
+		end += size
+	}
+
+	return start, end
+}
+
+// matchAt returns the byte length of the longest term matching a word of text
+// at i, ignoring case.
+func matchAt(text string, i int, terms []string) int {
+	if prev, _ := utf8.DecodeLastRuneInString(text[:i]); i > 0 && isWordRune(prev) {
+		return 0
+	}
+
+	matched := 0
+	for _, term := range terms {
+		n := len(term)
+		if n <= matched || i+n > len(text) || !strings.EqualFold(text[i:i+n], term) {
+			continue
+		}
+
+		if next, _ := utf8.DecodeRuneInString(text[i+n:]); i+n < len(text) && isWordRune(next) {
+			continue
+		}
+
+		matched = n
+	}
+
+	return matched
+}
+
+// isWordRune reports whether r is part of a word, as the full-text parser of
+// InnoDB splits them.
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func mark(text string, terms []string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		if matched := matchAt(text, i, terms); matched > 0 {
+			b.WriteString(openTag)
+			b.WriteString(html.EscapeString(text[i : i+matched]))
+			b.WriteString(closeTag)
+			i += matched
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[i:])
+		b.WriteString(html.EscapeString(text[i : i+size]))
+		i += size
+	}
+
+	return b.String()
+}
@@ -0,0 +1,148 @@
+package highlight_test
+
+import (
+	"myapp/util/highlight"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnippet(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		terms       []string
+		width       int
+		want        string
+		wantMatched bool
+	}{
+		{
+			name:        "single term",
+			text:        "learning go the hard way",
+			terms:       []string{"go"},
+			width:       100,
+			want:        "learning <em>go</em> the hard way",
+			wantMatched: true,
+		},
+		{
+			name:        "every occurrence",
+			text:        "go, go, Go!",
+			terms:       []string{"go"},
+			width:       100,
+			want:        "<em>go</em>, <em>go</em>, <em>Go</em>!",
+			wantMatched: true,
+		},
+		{
+			name:        "case folding",
+			text:        "Go and GO and gO",
+			terms:       []string{"go"},
+			width:       100,
+			want:        "<em>Go</em> and <em>GO</em> and <em>gO</em>",
+			wantMatched: true,
+		},
+		{
+			name:        "case folding of non-ASCII",
+			text:        "ÉTÉ",
+			terms:       []string{"été"},
+			width:       100,
+			want:        "<em>ÉTÉ</em>",
+			wantMatched: true,
+		},
+		{
+			name:        "overlapping terms prefer the longest",
+			text:        "a bookstore",
+			terms:       []string{"book", "bookstore"},
+			width:       100,
+			want:        "a <em>bookstore</em>",
+			wantMatched: true,
+		},
+		{
+			name:        "overlapping terms are not nested",
+			text:        "an e-book, a book",
+			terms:       []string{"book", "e-book"},
+			width:       100,
+			want:        "an <em>e-book</em>, a <em>book</em>",
+			wantMatched: true,
+		},
+		{
+			name:  "term inside another word",
+			text:  "the other hero",
+			terms: []string{"he"},
+			width: 100,
+		},
+		{
+			name:        "term beside other words",
+			text:        "then he said",
+			terms:       []string{"he"},
+			width:       100,
+			want:        "then <em>he</em> said",
+			wantMatched: true,
+		},
+		{
+			name:        "term inside a non-ASCII word",
+			text:        "déjà go goé",
+			terms:       []string{"go", "j"},
+			width:       100,
+			want:        "déjà <em>go</em> goé",
+			wantMatched: true,
+		},
+		{
+			name:        "HTML escaping",
+			text:        `<script>alert("go")</script> & more`,
+			terms:       []string{"go"},
+			width:       100,
+			want:        "&lt;script&gt;alert(&#34;<em>go</em>&#34;)&lt;/script&gt; &amp; more",
+			wantMatched: true,
+		},
+		{
+			name:        "HTML escaping within a match",
+			text:        "R&D",
+			terms:       []string{"r&d"},
+			width:       100,
+			want:        "<em>R&amp;D</em>",
+			wantMatched: true,
+		},
+		{
+			name:        "window",
+			text:        "aaaaaaaaaa go bbbbbbbbbb",
+			terms:       []string{"go"},
+			width:       8,
+			want:        "…a <em>go</em> bbb…",
+			wantMatched: true,
+		},
+		{
+			name:        "window at the end",
+			text:        "0123456789abcdefghi XYZ",
+			terms:       []string{"xyz"},
+			width:       10,
+			want:        "…i <em>XYZ</em>",
+			wantMatched: true,
+		},
+		{
+			name:        "window over multibyte runes",
+			text:        "éééé go éééé",
+			terms:       []string{"go"},
+			width:       8,
+			want:        "…é <em>go</em> ééé…",
+			wantMatched: true,
+		},
+		{
+			name:  "no match",
+			text:  "learning go",
+			terms: []string{"rust"},
+			width: 100,
+		},
+		{
+			name:  "no terms",
+			text:  "learning go",
+			width: 100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matched := highlight.Snippet(tt.text, tt.terms, tt.width)
+			assert.Equal(t, tt.wantMatched, matched)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
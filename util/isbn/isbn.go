@@ -0,0 +1,103 @@
+package isbn
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInvalid is returned for strings which are neither an ISBN-10 nor an ISBN-13.
+var ErrInvalid = errors.New("invalid ISBN")
+
+// bookland is the prefix of the ISBN-13s an ISBN-10 converts to.
+const bookland = "978"
+
+// Normalize returns the ISBN-13 of an ISBN-10 or ISBN-13, which may be
+// written with hyphens or spaces between its groups.
+func Normalize(s string) (string, error) {
+	s = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(strings.TrimSpace(s)))
+
+	switch {
+	case len(s) == 10 && valid10(s):
+		isbn := bookland + s[:9]
+		return isbn + string(checkDigit13(isbn)), nil
+	case len(s) == 13 && valid13(s):
+		return s, nil
+	}
+
+	return "", ErrInvalid
+}
+
+// Valid reports whether s is an ISBN-10 or ISBN-13 with a correct check digit.
+func Valid(s string) bool {
+	_, err := Normalize(s)
+	return err == nil
+}
+
+// To10 returns the ISBN-10 of a normalized ISBN-13, when it has one: only
+// ISBN-13s starting with 978 do.
+func To10(isbn13 string) (string, bool) {
+	if len(isbn13) != 13 || !strings.HasPrefix(isbn13, bookland) {
+		return "", false
+	}
+
+	isbn := isbn13[3:12]
+	return isbn + string(checkDigit10(isbn)), true
+}
+
+func valid10(s string) bool {
+	for i := 0; i < 9; i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	last := s[9]
+	if !isDigit(last) && last != 'X' {
+		return false
+	}
+
+	return checkDigit10(s[:9]) == last
+}
+
+func valid13(s string) bool {
+	for i := 0; i < 13; i++ {
+		if !isDigit(s[i]) {
+			return false
+		}
+	}
+
+	return checkDigit13(s[:12]) == s[12]
+}
+
+// checkDigit10 returns the check digit of the first nine digits of an ISBN-10.
+func checkDigit10(digits string) byte {
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(digits[i]-'0') * (10 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return 'X'
+	}
+
+	return byte('0' + check)
+}
+
+// checkDigit13 returns the check digit of the first twelve digits of an ISBN-13.
+func checkDigit13(digits string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(digits[i]-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
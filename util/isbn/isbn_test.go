@@ -0,0 +1,67 @@
+package isbn_test
+
+import (
+	"myapp/util/isbn"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr error
+	}{
+		{name: "ISBN-10", input: "0306406152", want: "9780306406157"},
+		{name: "ISBN-10 with hyphens", input: "0-306-40615-2", want: "9780306406157"},
+		{name: "ISBN-10 with spaces", input: "0 306 40615 2", want: "9780306406157"},
+		{name: "ISBN-10 check digit X", input: "080442957X", want: "9780804429573"},
+		{name: "ISBN-10 lowercase x", input: "0-8044-2957-x", want: "9780804429573"},
+		{name: "ISBN-10 with mixed separators", input: "0 19-853453 1", want: "9780198534532"},
+		{name: "ISBN-13", input: "9780306406157", want: "9780306406157"},
+		{name: "ISBN-13 with hyphens", input: "978-0-306-40615-7", want: "9780306406157"},
+		{name: "ISBN-13 with spaces", input: " 978 0 306 40615 7 ", want: "9780306406157"},
+		{name: "ISBN-13 979 prefix", input: "979-10-90636-07-1", want: "9791090636071"},
+		{name: "ISBN-10 wrong check digit", input: "0306406153", wantErr: isbn.ErrInvalid},
+		{name: "ISBN-10 X not last", input: "03064X6152", wantErr: isbn.ErrInvalid},
+		{name: "ISBN-13 wrong check digit", input: "9780306406158", wantErr: isbn.ErrInvalid},
+		{name: "ISBN-13 check digit X", input: "978030640615X", wantErr: isbn.ErrInvalid},
+		{name: "letters", input: "97803064O6157", wantErr: isbn.ErrInvalid},
+		{name: "too short", input: "030640615", wantErr: isbn.ErrInvalid},
+		{name: "too long", input: "97803064061570", wantErr: isbn.ErrInvalid},
+		{name: "empty", input: "", wantErr: isbn.ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isbn.Normalize(tt.input)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantErr == nil, isbn.Valid(tt.input))
+		})
+	}
+}
+
+func TestTo10(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   string
+		wantOk bool
+	}{
+		{name: "978 prefix", input: "9780306406157", want: "0306406152", wantOk: true},
+		{name: "check digit X", input: "9780804429573", want: "080442957X", wantOk: true},
+		{name: "979 prefix", input: "9791090636071"},
+		{name: "not normalized", input: "978-0-306-40615-7"},
+		{name: "ISBN-10", input: "0306406152"},
+		{name: "empty", input: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := isbn.To10(tt.input)
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
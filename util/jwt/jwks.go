@@ -0,0 +1,234 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// jwksRefreshInterval bounds how often a JWKS URL is fetched again for a key
+// it didn't have, so tokens with made up key IDs can't hammer the issuer.
+const jwksRefreshInterval = time.Minute
+
+// jwksFetchTimeout bounds the fetches of a JWKS URL, which requests wait for.
+const jwksFetchTimeout = 10 * time.Second
+
+// maxJWKSSize bounds the size of a key set.
+const maxJWKSSize = 1 << 20
+
+// JWKS is the RSA keys of a JSON Web Key Set, read from a file or fetched
+// from an http(s) URL. A key set fetched from a URL is fetched again when a
+// token is signed with a key it doesn't have, which follows key rotations.
+type JWKS struct {
+	source string
+	client *http.Client
+	now    func() time.Time
+
+	// mu guards the fields below, but isn't held during fetches. keys is
+	// replaced by each fetch, never modified, so it's read without mu once
+	// taken.
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+	fetching  *jwksFetch
+}
+
+// jwksFetch is a fetch of the key set under way, which requests needing it
+// wait for rather than fetching it themselves.
+type jwksFetch struct {
+	done chan struct{}
+	keys map[string]*rsa.PublicKey
+	err  error
+}
+
+// NewJWKS loads the key set of source, a file path or an http(s) URL.
+func NewJWKS(ctx context.Context, source string) (*JWKS, error) {
+	j := &JWKS{source: source, client: &http.Client{Timeout: jwksFetchTimeout}, now: time.Now}
+
+	keys, err := j.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	j.keys, j.fetchedAt = keys, j.now()
+
+	return j, nil
+}
+
+func (j *JWKS) remote() bool {
+	return strings.HasPrefix(j.source, "http://") || strings.HasPrefix(j.source, "https://")
+}
+
+// Key returns the key of ID kid. A token without key ID can be verified by
+// a key set of a single key.
+func (j *JWKS) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	j.mu.Lock()
+	keys := j.keys
+	j.mu.Unlock()
+
+	if key, ok := lookup(keys, kid); ok {
+		return key, nil
+	}
+
+	if j.remote() {
+		keys, err := j.refresh(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if key, ok := lookup(keys, kid); ok {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+// refresh returns the key set fetched again, unless it was fetched less than
+// jwksRefreshInterval ago. Concurrent requests share a single fetch, which
+// carries on when the request that started it is cancelled.
+func (j *JWKS) refresh(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	j.mu.Lock()
+	f := j.fetching
+	if f == nil {
+		if j.now().Sub(j.fetchedAt) < jwksRefreshInterval {
+			keys := j.keys
+			j.mu.Unlock()
+			return keys, nil
+		}
+
+		f = &jwksFetch{done: make(chan struct{})}
+		j.fetching = f
+		go j.fetchKeys(f)
+	}
+	j.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.keys, f.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetchKeys runs the fetch f. A failed fetch counts as one too, so an issuer
+// which is down isn't fetched from on every request.
+func (j *JWKS) fetchKeys(f *jwksFetch) {
+	f.keys, f.err = j.load(context.Background())
+
+	j.mu.Lock()
+	if f.err == nil {
+		j.keys = f.keys
+	}
+	j.fetchedAt = j.now()
+	j.fetching = nil
+	j.mu.Unlock()
+
+	close(f.done)
+}
+
+func lookup(keys map[string]*rsa.PublicKey, kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, ok := keys[kid]
+	return key, ok
+}
+
+// load reads or fetches the key set of the source.
+func (j *JWKS) load(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	var data []byte
+	var err error
+	if j.remote() {
+		data, err = j.fetch(ctx)
+	} else {
+		data, err = os.ReadFile(j.source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwks %s: %w", j.source, err)
+	}
+
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, fmt.Errorf("jwks %s: %w", j.source, err)
+	}
+
+	return keys, nil
+}
+
+func (j *JWKS) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// parseJWKS returns the RSA signature keys of a key set by ID; other keys are
+// ignored.
+func parseJWKS(data []byte) (map[string]*rsa.PublicKey, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") || (k.Alg != "" && k.Alg != AlgRS256) {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: invalid modulus", k.Kid)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("key %q: invalid exponent", k.Kid)
+		}
+
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("no RSA signature key")
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,197 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// ErrInvalidToken is wrapped by the errors of tokens which are malformed,
+// badly signed or whose claims don't hold. Other errors, e.g. of the retrieval
+// of keys, are the server's.
+var ErrInvalidToken = errors.New("invalid token")
+
+// Config is the keys and the expected claims of the tokens of a Verifier.
+type Config struct {
+	// Secret is the key of HS256 tokens; they are refused when it's empty.
+	Secret []byte
+
+	// JWKS holds the keys of RS256 tokens; they are refused when it's nil.
+	JWKS *JWKS
+
+	// Issuer and Audience, when not empty, must be the iss claim and one of
+	// the aud claims of the tokens.
+	Issuer   string
+	Audience string
+
+	// Leeway is the clock skew tolerated on exp and nbf.
+	Leeway time.Duration
+}
+
+// Verifier checks the signature and the claims of JSON Web Tokens.
+type Verifier struct {
+	conf Config
+	now  func() time.Time
+}
+
+func NewVerifier(conf Config) *Verifier {
+	return &Verifier{conf: conf, now: time.Now}
+}
+
+// Claims are the claims of a token the application uses.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Issuer    string   `json:"iss"`
+	Audience  Audience `json:"aud"`
+	ExpiresAt float64  `json:"exp"`
+	NotBefore float64  `json:"nbf"`
+	IssuedAt  float64  `json:"iat"`
+
+	// Roles and Scope, space separated as in OAuth 2.0, grant the permissions
+	// of the subject.
+	Roles []string `json:"roles"`
+	Scope string   `json:"scope"`
+}
+
+// Scopes returns the scopes of the scope claim.
+func (c *Claims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// Audience is the aud claim, a single string or an array of them.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]string)(a))
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*a = Audience{s}
+
+	return nil
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Verify returns the claims of token once its signature and its exp, nbf, iss
+// and aud claims are checked. Tokens must expire and have a subject.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidToken)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	// The algorithm of the header only selects among the keys of the
+	// configuration, so a public key can't be used as an HMAC secret.
+	signed := parts[0] + "." + parts[1]
+	switch {
+	case h.Alg == AlgHS256 && len(v.conf.Secret) > 0:
+		mac := hmac.New(sha256.New, v.conf.Secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	case h.Alg == AlgRS256 && v.conf.JWKS != nil:
+		key, err := v.conf.JWKS.Key(ctx, h.Kid)
+		if err != nil {
+			return nil, err
+		}
+		digest := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, h.Alg)
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidToken)
+	}
+	if err := v.checkClaims(&claims); err != nil {
+		return nil, err
+	}
+
+	return &claims, nil
+}
+
+func (v *Verifier) checkClaims(c *Claims) error {
+	now := v.now()
+
+	if c.Subject == "" {
+		return fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	if c.ExpiresAt == 0 {
+		return fmt.Errorf("%w: missing expiration", ErrInvalidToken)
+	}
+	if now.After(unixTime(c.ExpiresAt).Add(v.conf.Leeway)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+	if c.NotBefore != 0 && now.Before(unixTime(c.NotBefore).Add(-v.conf.Leeway)) {
+		return fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+
+	if v.conf.Issuer != "" && c.Issuer != v.conf.Issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+	if v.conf.Audience != "" && !c.Audience.contains(v.conf.Audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+func (a Audience) contains(audience string) bool {
+	for _, aud := range a {
+		if aud == audience {
+			return true
+		}
+	}
+
+	return false
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// unixTime converts a NumericDate, which may have a fraction of seconds.
+func unixTime(date float64) time.Time {
+	return time.Unix(0, int64(date*float64(time.Second)))
+}
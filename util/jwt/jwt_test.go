@@ -0,0 +1,297 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testNow    = time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+	testSecret = []byte("secret")
+)
+
+// sign returns a token of the claims signed with key, a secret for HS256 or
+// an RSA private key for RS256.
+func sign(t *testing.T, alg, kid string, claims map[string]interface{}, key interface{}) string {
+	h, err := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub":   "alice",
+		"iss":   "https://issuer.example",
+		"aud":   []string{"other", "myapp"},
+		"exp":   testNow.Add(time.Hour).Unix(),
+		"roles": []string{"editor"},
+		"scope": "books:read books:write",
+	}
+}
+
+// jwks returns the key set of the public keys by ID.
+func jwks(keys map[string]*rsa.PrivateKey) []byte {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	data, _ := json.Marshal(set)
+
+	return data
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, jwks(map[string]*rsa.PrivateKey{"k1": rsaKey}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := NewJWKS(context.Background(), file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := NewVerifier(Config{Secret: testSecret, JWKS: keys, Issuer: "https://issuer.example", Audience: "myapp", Leeway: time.Minute})
+	v.now = func() time.Time { return testNow }
+
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := validClaims()
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "HS256", token: sign(t, AlgHS256, "", validClaims(), testSecret)},
+		{name: "RS256", token: sign(t, AlgRS256, "k1", validClaims(), rsaKey)},
+		{name: "single audience", token: sign(t, AlgHS256, "", with("aud", "myapp"), testSecret)},
+		{name: "expired within leeway", token: sign(t, AlgHS256, "", with("exp", testNow.Add(-30*time.Second).Unix()), testSecret)},
+		{name: "malformed", token: "abc.def", wantErr: "malformed token"},
+		{name: "bad HMAC signature", token: sign(t, AlgHS256, "", validClaims(), []byte("other")), wantErr: "bad signature"},
+		{name: "bad RSA signature", token: sign(t, AlgRS256, "k1", validClaims(), otherKey), wantErr: "bad signature"},
+		{name: "unknown key", token: sign(t, AlgRS256, "k2", validClaims(), rsaKey), wantErr: `unknown key "k2"`},
+		{name: "none algorithm", token: sign(t, "none", "", validClaims(), nil), wantErr: `unsupported algorithm "none"`},
+		{name: "expired", token: sign(t, AlgHS256, "", with("exp", testNow.Add(-2*time.Minute).Unix()), testSecret), wantErr: "token expired"},
+		{name: "without expiration", token: sign(t, AlgHS256, "", with("exp", nil), testSecret), wantErr: "missing expiration"},
+		{name: "not valid yet", token: sign(t, AlgHS256, "", with("nbf", testNow.Add(2*time.Minute).Unix()), testSecret), wantErr: "token not valid yet"},
+		{name: "without subject", token: sign(t, AlgHS256, "", with("sub", nil), testSecret), wantErr: "missing subject"},
+		{name: "wrong issuer", token: sign(t, AlgHS256, "", with("iss", "https://evil.example"), testSecret), wantErr: "unexpected issuer"},
+		{name: "wrong audience", token: sign(t, AlgHS256, "", with("aud", "other"), testSecret), wantErr: "unexpected audience"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(context.Background(), tt.token)
+			if tt.wantErr != "" {
+				assert.ErrorIs(t, err, ErrInvalidToken)
+				assert.EqualError(t, err, "invalid token: "+tt.wantErr)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, "alice", claims.Subject)
+				assert.Equal(t, []string{"editor"}, claims.Roles)
+				assert.Equal(t, []string{"books:read", "books:write"}, claims.Scopes())
+			}
+		})
+	}
+}
+
+func TestVerifier_AlgorithmOfKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a secret, HS256 tokens are refused even when signed with the
+	// modulus of the public key, which is no secret.
+	v := NewVerifier(Config{})
+	v.now = func() time.Time { return testNow }
+
+	_, err = v.Verify(context.Background(), sign(t, AlgHS256, "", validClaims(), rsaKey.N.Bytes()))
+	assert.EqualError(t, err, `invalid token: unsupported algorithm "HS256"`)
+}
+
+func TestJWKS_Rotation(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	set := map[string]*rsa.PrivateKey{"old": oldKey}
+	fetches := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		w.Write(jwks(set))
+	}))
+	defer srv.Close()
+
+	keys, err := NewJWKS(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := testNow
+	keys.now = func() time.Time { return now }
+	keys.fetchedAt = now
+
+	// The issuer rotates its keys.
+	set = map[string]*rsa.PrivateKey{"old": oldKey, "new": newKey}
+
+	_, err = keys.Key(context.Background(), "new")
+	assert.ErrorIs(t, err, ErrInvalidToken, "fetched again too soon")
+	assert.Equal(t, 1, fetches)
+
+	now = now.Add(jwksRefreshInterval)
+	key, err := keys.Key(context.Background(), "new")
+	if assert.NoError(t, err) {
+		assert.Equal(t, newKey.N, key.N)
+	}
+	assert.Equal(t, 2, fetches)
+
+	_, err = keys.Key(context.Background(), "old")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fetches)
+}
+
+func TestJWKS_ConcurrentRefresh(t *testing.T) {
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fetches int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&fetches, 1) == 1 {
+			w.Write(jwks(map[string]*rsa.PrivateKey{"old": oldKey}))
+			return
+		}
+		<-release
+		w.Write(jwks(map[string]*rsa.PrivateKey{"old": oldKey, "new": newKey}))
+	}))
+	defer srv.Close()
+
+	keys, err := NewJWKS(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys.fetchedAt = time.Time{}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := keys.Key(context.Background(), "new")
+			errs <- err
+		}()
+	}
+
+	// While the key set is fetched, the known keys are still served and
+	// requests which give up don't wait for the fetch.
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&fetches) == 2 }, time.Second, time.Millisecond)
+	_, err = keys.Key(context.Background(), "old")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = keys.Key(ctx, "new")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestNewJWKS_Errors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	_, err := NewJWKS(context.Background(), srv.URL)
+	assert.EqualError(t, err, fmt.Sprintf("jwks %s: status 503 Service Unavailable", srv.URL))
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, []byte(`{"keys":[{"kty":"EC","kid":"k1"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = NewJWKS(context.Background(), file)
+	assert.True(t, err != nil && !errors.Is(err, ErrInvalidToken))
+	assert.EqualError(t, err, fmt.Sprintf("jwks %s: no RSA signature key", file))
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+type ctxKey struct{}
+
+var nop = func() *Logger {
+	logger := zerolog.Nop()
+	return &Logger{logger: &logger}
+}()
+
+// NewContext returns a copy of ctx which carries l.
+func NewContext(ctx context.Context, l LoggerInterface) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// Ctx returns the logger carried by ctx, or a logger which discards everything.
+// It lets the service and repository layers log with the request ID without
+// having a logger injected.
+func Ctx(ctx context.Context) LoggerInterface {
+	if l, ok := ctx.Value(ctxKey{}).(LoggerInterface); ok {
+		return l
+	}
+
+	return nop
+}
@@ -1,14 +1,18 @@
 package logger
 
 import (
+	"context"
 	"io"
+	"myapp/util/requestid"
 	"os"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Logger struct {
 	logger *zerolog.Logger
+	out    *os.File
 }
 
 func New(isDebug bool) *Logger {
@@ -21,7 +25,7 @@ func New(isDebug bool) *Logger {
 
 	logger := zerolog.New(os.Stderr).With().Timestamp().Logger()
 
-	return &Logger{logger: &logger}
+	return &Logger{logger: &logger, out: os.Stderr}
 }
 
 func NewConsole(isDebug bool) *Logger {
@@ -33,7 +37,7 @@ func NewConsole(isDebug bool) *Logger {
 	zerolog.SetGlobalLevel(logLevel)
 	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
 
-	return &Logger{logger: &logger}
+	return &Logger{logger: &logger, out: os.Stdout}
 }
 
 // Output duplicates the global logger and sets w as its output.
@@ -41,6 +45,49 @@ func (l *Logger) Output(w io.Writer) zerolog.Logger {
 	return l.logger.Output(w)
 }
 
+// Sync flushes the log output to disk when it is redirected to a file.
+// Terminals and pipes are unbuffered, so there is nothing to flush.
+func (l *Logger) Sync() error {
+	if l.out == nil {
+		return nil
+	}
+
+	info, err := l.out.Stat()
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+
+	return l.out.Sync()
+}
+
+// WithContext returns a child logger which adds the request ID and trace ID
+// of ctx, if any, to every message.
+func (l *Logger) WithContext(ctx context.Context) LoggerInterface {
+	id := requestid.FromContext(ctx)
+	sc := trace.SpanContextFromContext(ctx)
+	if id == "" && !sc.HasTraceID() {
+		return l
+	}
+
+	c := l.logger.With()
+	if id != "" {
+		c = c.Str("request_id", id)
+	}
+	if sc.HasTraceID() {
+		c = c.Str("trace_id", sc.TraceID().String())
+	}
+	logger := c.Logger()
+
+	return &Logger{logger: &logger, out: l.out}
+}
+
+// Debug starts a new message with debug level.
+//
+// You must call Msg on the returned event in order to send the event.
+func (l *Logger) Debug() *zerolog.Event {
+	return l.logger.Debug()
+}
+
 // Info starts a new message with info level.
 //
 // You must call Msg on the returned event in order to send the event.
@@ -64,6 +111,8 @@ func (l *Logger) Warn() *zerolog.Event {
 }
 
 type LoggerInterface interface {
+	WithContext(ctx context.Context) LoggerInterface
+	Debug() *zerolog.Event
 	Info() *zerolog.Event
 	Fatal() *zerolog.Event
 	Warn() *zerolog.Event
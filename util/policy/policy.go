@@ -0,0 +1,119 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Permission allows a kind of operation on the catalog.
+type Permission string
+
+const (
+	// BooksRead allows reading the catalog.
+	BooksRead Permission = "books:read"
+
+	// BooksWrite allows creating books, authors, labels and collections, and
+	// updating them; books and collections only when the principal's.
+	BooksWrite Permission = "books:write"
+
+	// BooksWriteAny extends BooksWrite and BooksDelete to the books created by
+	// others and to their collections.
+	BooksWriteAny Permission = "books:write_any"
+
+	// BooksDelete allows deleting and restoring books, and deleting the other
+	// resources of the catalog.
+	BooksDelete Permission = "books:delete"
+
+	// BooksPurge allows removing books from the trash for good.
+	BooksPurge Permission = "books:purge"
+
+	// ApiKeysManage allows minting, scoping and revoking API keys.
+	ApiKeysManage Permission = "api_keys:manage"
+)
+
+// scopes are the permissions API keys can be scoped to: all but the
+// management of the keys themselves.
+var scopes = []Permission{BooksRead, BooksWrite, BooksWriteAny, BooksDelete, BooksPurge}
+
+var permissions = map[Permission]bool{
+	BooksRead:     true,
+	BooksWrite:    true,
+	BooksWriteAny: true,
+	BooksDelete:   true,
+	BooksPurge:    true,
+	ApiKeysManage: true,
+}
+
+// Scopes returns the permissions API keys can be scoped to.
+func Scopes() []Permission {
+	return append([]Permission(nil), scopes...)
+}
+
+// IsScope reports whether API keys can be scoped to perm.
+func IsScope(perm Permission) bool {
+	for _, scope := range scopes {
+		if scope == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	RoleReader = "reader"
+	RoleEditor = "editor"
+	RoleAdmin  = "admin"
+)
+
+// Policy grants permissions to roles.
+type Policy map[string][]Permission
+
+// Default is the policy used unless a policy file is configured.
+var Default = Policy{
+	RoleReader: {BooksRead},
+	RoleEditor: {BooksRead, BooksWrite},
+	RoleAdmin:  {BooksRead, BooksWrite, BooksWriteAny, BooksDelete, BooksPurge, ApiKeysManage},
+}
+
+// Load reads a policy from a JSON file, an object mapping roles to the array
+// of their permissions, e.g. {"reader": ["books:read"]}.
+func Load(path string) (Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("policy: %w", err)
+	}
+
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("policy %s: %w", path, err)
+	}
+
+	for role, perms := range p {
+		for _, perm := range perms {
+			if !permissions[perm] {
+				return nil, fmt.Errorf("policy %s: unknown permission %q of role %q", path, perm, role)
+			}
+		}
+	}
+
+	return p, nil
+}
+
+// Permissions returns the permissions granted to roles; unknown roles are
+// granted none.
+func (p Policy) Permissions(roles []string) []Permission {
+	seen := make(map[Permission]bool)
+	var perms []Permission
+	for _, role := range roles {
+		for _, perm := range p[role] {
+			if !seen[perm] {
+				seen[perm] = true
+				perms = append(perms, perm)
+			}
+		}
+	}
+
+	return perms
+}
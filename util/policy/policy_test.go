@@ -0,0 +1,62 @@
+package policy_test
+
+import (
+	"fmt"
+	"myapp/util/policy"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    policy.Policy
+		wantErr string
+	}{
+		{
+			name:    "valid",
+			content: `{"reader":["books:read"],"curator":["books:read","books:write","books:write_any"]}`,
+			want: policy.Policy{
+				"reader":  {policy.BooksRead},
+				"curator": {policy.BooksRead, policy.BooksWrite, policy.BooksWriteAny},
+			},
+		},
+		{
+			name:    "unknown permission",
+			content: `{"reader":["books:read","books:burn"]}`,
+			wantErr: `unknown permission "books:burn" of role "reader"`,
+		},
+		{
+			name:    "malformed",
+			content: `["reader"]`,
+			wantErr: "json: cannot unmarshal array into Go value of type policy.Policy",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.json")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := policy.Load(path)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, fmt.Sprintf("policy %s: %s", path, tt.wantErr))
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPolicy_Permissions(t *testing.T) {
+	assert.Equal(t, []policy.Permission{policy.BooksRead, policy.BooksWrite}, policy.Default.Permissions([]string{"reader", "editor", "unknown"}))
+	assert.Empty(t, policy.Default.Permissions(nil))
+}
@@ -0,0 +1,41 @@
+package principal
+
+import (
+	"context"
+	"myapp/util/policy"
+)
+
+// Principal is the authenticated client of a request.
+type Principal struct {
+	// Subject identifies the client, e.g. the sub claim of its token.
+	Subject string
+
+	Roles  []string
+	Scopes []string
+
+	// Permissions are those the policy grants to the roles.
+	Permissions []policy.Permission
+}
+
+// Can reports whether the principal has perm.
+func (p *Principal) Can(perm policy.Permission) bool {
+	for _, granted := range p.Permissions {
+		if granted == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, nil for anonymous requests.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(ctxKey{}).(*Principal)
+	return p
+}
@@ -0,0 +1,52 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const Header = "X-Request-ID"
+
+// maxLength bounds client-supplied IDs so they can't bloat every log line.
+const maxLength = 128
+
+type ctxKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or an empty string.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// IsValid reports whether a client-supplied ID is safe to propagate:
+// short and limited to characters commonly used in IDs.
+func IsValid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,72 @@
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Flatten returns a copy of img drawn over a white background, the format
+// thumbnails are scaled from: JPEG, which they're encoded to, has no alpha.
+func Flatten(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+
+	return dst
+}
+
+// Fit scales src down so that neither side is longer than size, keeping its
+// aspect ratio. Each pixel is the average of the pixels it covers, which is
+// right for the downscaling of thumbnails. An image which already fits is
+// returned as is. src has its origin at 0,0, as the images of Flatten.
+func Fit(src *image.RGBA, size int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	if w <= size && h <= size {
+		return src
+	}
+
+	dw, dh := size, size
+	if w > h {
+		dh = max(1, h*size/w)
+	} else {
+		dw = max(1, w*size/h)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*h/dh, max((y+1)*h/dh, y*h/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*w/dw, max((x+1)*w/dw, x*w/dw+1)
+
+			var r, g, b, a, n int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += int(row[i])
+					g += int(row[i+1])
+					b += int(row[i+2])
+					a += int(row[i+3])
+					n++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+
+	return dst
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,99 @@
+package thumbnail_test
+
+import (
+	"image"
+	"image/color"
+	"myapp/util/thumbnail"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uniform returns an image of the size filled with c.
+func uniform(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func TestFit(t *testing.T) {
+	c := color.RGBA{R: 10, G: 20, B: 30, A: 255}
+
+	tests := []struct {
+		name          string
+		width, height int
+		size          int
+		want          image.Rectangle
+	}{
+		{name: "landscape", width: 1000, height: 500, size: 160, want: image.Rect(0, 0, 160, 80)},
+		{name: "portrait", width: 500, height: 1000, size: 160, want: image.Rect(0, 0, 80, 160)},
+		{name: "square", width: 1000, height: 1000, size: 160, want: image.Rect(0, 0, 160, 160)},
+		{name: "not divisible", width: 1001, height: 333, size: 160, want: image.Rect(0, 0, 160, 53)},
+		{name: "one pixel wide", width: 1, height: 1000, size: 160, want: image.Rect(0, 0, 1, 160)},
+		{name: "one pixel high", width: 1000, height: 1, size: 160, want: image.Rect(0, 0, 160, 1)},
+		{name: "extreme landscape", width: 10000, height: 3, size: 160, want: image.Rect(0, 0, 160, 1)},
+		{name: "extreme portrait", width: 3, height: 10000, size: 160, want: image.Rect(0, 0, 1, 160)},
+		{name: "fits", width: 100, height: 50, size: 160, want: image.Rect(0, 0, 100, 50)},
+		{name: "exact fit", width: 160, height: 160, size: 160, want: image.Rect(0, 0, 160, 160)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := uniform(tt.width, tt.height, c)
+
+			got := thumbnail.Fit(src, tt.size)
+			assert.Equal(t, tt.want, got.Bounds())
+
+			// Every pixel averages pixels of the source, none is left
+			// blank.
+			for y := 0; y < got.Bounds().Dy(); y++ {
+				for x := 0; x < got.Bounds().Dx(); x++ {
+					if got.RGBAAt(x, y) != c {
+						t.Fatalf("pixel %d,%d = %v, want %v", x, y, got.RGBAAt(x, y), c)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFit_Unscaled(t *testing.T) {
+	src := uniform(100, 50, color.RGBA{A: 255})
+
+	assert.Same(t, src, thumbnail.Fit(src, 100))
+}
+
+func TestFit_Average(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	src.SetRGBA(0, 0, color.RGBA{R: 0, G: 40, B: 255, A: 255})
+	src.SetRGBA(1, 0, color.RGBA{R: 100, G: 40, B: 255, A: 255})
+	src.SetRGBA(0, 1, color.RGBA{R: 50, G: 80, B: 0, A: 255})
+	src.SetRGBA(1, 1, color.RGBA{R: 120, G: 80, B: 0, A: 127})
+	for y := 0; y < 2; y++ {
+		for x := 2; x < 4; x++ {
+			src.SetRGBA(x, y, color.RGBA{R: 200, G: 200, B: 200, A: 255})
+		}
+	}
+
+	got := thumbnail.Fit(src, 2)
+
+	assert.Equal(t, image.Rect(0, 0, 2, 1), got.Bounds())
+	assert.Equal(t, color.RGBA{R: 67, G: 60, B: 127, A: 223}, got.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 200, G: 200, B: 200, A: 255}, got.RGBAAt(1, 0))
+}
+
+func TestFlatten(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(10, 10, 12, 11))
+	src.SetNRGBA(10, 10, color.NRGBA{R: 255, A: 255})
+	src.SetNRGBA(11, 10, color.NRGBA{})
+
+	got := thumbnail.Flatten(src)
+
+	assert.Equal(t, image.Rect(0, 0, 2, 1), got.Bounds())
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, got.RGBAAt(0, 0))
+	assert.Equal(t, color.RGBA{R: 255, G: 255, B: 255, A: 255}, got.RGBAAt(1, 0))
+}
@@ -1,77 +1,149 @@
 package validator
 
-// import (
-// 	"fmt"
-// 	"reflect"
-// 	"regexp"
-// 	"strings"
-
-// 	"gopkg.in/go-playground/validator.v9"
-// )
-
-// const (
-// 	alphaSpaceRegexString string = "^[a-zA-Z ]+$"
-// 	dateRegexString       string = "^(((19|20)([2468][048]|[13579][26]|0[48])|2000)[/-]02[/-]29|((19|20)[0-9]{2}[/-](0[469]|11)[/-](0[1-9]|[12][0-9]|30)|(19|20)[0-9]{2}[/-](0[13578]|1[02])[/-](0[1-9]|[12][0-9]|3[01])|(19|20)[0-9]{2}[/-]02[/-](0[1-9]|1[0-9]|2[0-8])))$"
-// )
-
-// type ErrResponse struct {
-// 	Errors []string `json:"errors"`
-// }
-
-// func New() *validator.Validate {
-// 	validate := validator.New()
-// 	validate.SetTagName("form")
-
-// 	// Using the names which have been specified for JSON representations of structs, rather than normal Go field names
-// 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-// 		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-// 		if name == "-" {
-// 			return ""
-// 		}
-// 		return name
-// 	})
-
-// 	validate.RegisterValidation("alpha_space", isAlphaSpace)
-// 	validate.RegisterValidation("date", isDate)
-
-// 	return validate
-// }
-
-// func isAlphaSpace(fl validator.FieldLevel) bool {
-// 	reg := regexp.MustCompile(alphaSpaceRegexString)
-// 	return reg.MatchString(fl.Field().String())
-// }
-
-// func isDate(fl validator.FieldLevel) bool {
-// 	reg := regexp.MustCompile(dateRegexString)
-// 	return reg.MatchString(fl.Field().String())
-// }
-
-// func ToErrResponse(err error) *ErrResponse {
-// 	if fieldErrors, ok := err.(validator.ValidationErrors); ok {
-// 		resp := ErrResponse{
-// 			Errors: make([]string, len(fieldErrors)),
-// 		}
-
-// 		for i, err := range fieldErrors {
-// 			switch err.Tag() {
-// 			case "required":
-// 				resp.Errors[i] = fmt.Sprintf("%s is a required field", err.Field())
-// 			case "max":
-// 				resp.Errors[i] = fmt.Sprintf("%s must be a maximum of %s in length", err.Field(), err.Param())
-// 			case "url":
-// 				resp.Errors[i] = fmt.Sprintf("%s must be a valid URL", err.Field())
-// 			case "alpha_space":
-// 				resp.Errors[i] = fmt.Sprintf("%s can only contain alphabetic and space characters", err.Field())
-// 			case "date":
-// 				resp.Errors[i] = fmt.Sprintf("%s must be a valid date", err.Field())
-// 			default:
-// 				resp.Errors[i] = fmt.Sprintf("something wrong on %s; %s", err.Field(), err.Tag())
-// 			}
-// 		}
-
-// 		return &resp
-// 	}
-
-// 	return nil
-// }
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"myapp/model"
+	"myapp/util/apperror"
+	"myapp/util/isbn"
+	"myapp/util/policy"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+const dateLayout = "2006-01-02"
+
+// minDate is the earliest publication date accepted by the "date" validation.
+var minDate = time.Date(1000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+func New() *validator.Validate {
+	validate := validator.New()
+	validate.SetTagName("form")
+
+	// Using the names which have been specified for JSON representations of structs, rather than normal Go field names
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	validate.RegisterValidation("http_url", isHTTPURL)
+	validate.RegisterValidation("cover_url", isCoverURL)
+	validate.RegisterValidation("date", isDate)
+	validate.RegisterValidation("not_future", isNotFuture)
+	validate.RegisterValidation("isbn", isISBN)
+	validate.RegisterValidation("scope", isScope)
+	validate.RegisterValidation("max_bytes", isMaxBytes)
+
+	return validate
+}
+
+func isHTTPURL(fl validator.FieldLevel) bool {
+	u, err := url.ParseRequestURI(fl.Field().String())
+	if err != nil {
+		return false
+	}
+
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func isCoverURL(fl validator.FieldLevel) bool {
+	return model.IsCoverURL(fl.Field().String())
+}
+
+func isDate(fl validator.FieldLevel) bool {
+	date, err := time.Parse(dateLayout, fl.Field().String())
+	if err != nil {
+		return false
+	}
+
+	return !date.Before(minDate)
+}
+
+func isNotFuture(fl validator.FieldLevel) bool {
+	date, err := time.Parse(dateLayout, fl.Field().String())
+	if err != nil {
+		return true
+	}
+
+	return !date.After(time.Now())
+}
+
+// isISBN replaces the validation of the same name, which doesn't accept the
+// hyphens ISBNs are usually printed with.
+func isISBN(fl validator.FieldLevel) bool {
+	return isbn.Valid(fl.Field().String())
+}
+
+func isScope(fl validator.FieldLevel) bool {
+	return policy.IsScope(policy.Permission(fl.Field().String()))
+}
+
+// isMaxBytes limits the length of a string in bytes rather than runes, as
+// the sizes of MySQL TEXT columns are.
+func isMaxBytes(fl validator.FieldLevel) bool {
+	max, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		panic(fmt.Sprintf("max_bytes: invalid parameter %q", fl.Param()))
+	}
+
+	return len(fl.Field().String()) <= max
+}
+
+// ToFieldErrors converts validation errors into the field errors reported to clients.
+func ToFieldErrors(err error) []apperror.FieldError {
+	fieldErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil
+	}
+
+	resp := make([]apperror.FieldError, len(fieldErrors))
+	for i, err := range fieldErrors {
+		resp[i].Name = err.Field()
+
+		switch err.Tag() {
+		case "required":
+			resp[i].Reason = "is a required field"
+		case "max":
+			resp[i].Reason = fmt.Sprintf("must be a maximum of %s in length", err.Param())
+		case "max_bytes":
+			resp[i].Reason = fmt.Sprintf("must be a maximum of %s bytes in length", err.Param())
+		case "min":
+			resp[i].Reason = fmt.Sprintf("must be at least %s", err.Param())
+		case "unique":
+			resp[i].Reason = "must not contain duplicates"
+		case "url", "http_url", "http_url|cover_url":
+			resp[i].Reason = "must be a valid URL"
+		case "date":
+			resp[i].Reason = fmt.Sprintf("must be a valid date in YYYY-MM-DD format, not before %s", minDate.Format(dateLayout))
+		case "not_future":
+			resp[i].Reason = "must not be in the future"
+		case "isbn":
+			resp[i].Reason = "must be a valid ISBN-10 or ISBN-13"
+		case "scope":
+			resp[i].Reason = fmt.Sprintf("must be one of %s", scopeList())
+		default:
+			resp[i].Reason = fmt.Sprintf("something wrong; %s", err.Tag())
+		}
+	}
+
+	return resp
+}
+
+// scopeList returns the scopes of API keys, comma separated.
+func scopeList() string {
+	scopes := policy.Scopes()
+	names := make([]string, len(scopes))
+	for i, scope := range scopes {
+		names[i] = string(scope)
+	}
+
+	return strings.Join(names, ", ")
+}
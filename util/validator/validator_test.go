@@ -0,0 +1,161 @@
+package validator_test
+
+import (
+	"myapp/util/apperror"
+	"myapp/util/validator"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_HTTPURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "http", value: "http://example.com/cover.jpg", valid: true},
+		{name: "https", value: "https://example.com/cover.jpg?size=large", valid: true},
+		{name: "uppercase scheme", value: "HTTPS://example.com", valid: true},
+		{name: "ftp", value: "ftp://example.com/cover.jpg", valid: false},
+		{name: "javascript", value: "javascript:alert(1)", valid: false},
+		{name: "no host", value: "http:///cover.jpg", valid: false},
+		{name: "relative", value: "/cover.jpg", valid: false},
+		{name: "not a URL", value: "cover", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.New().Var(tt.value, "http_url")
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestNew_ImageURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "http", value: "http://example.com/cover.jpg", valid: true},
+		{name: "cover", value: "/api/v1/books/12/cover", valid: true},
+		{name: "thumbnail", value: "/api/v1/books/12/cover/small", valid: false},
+		{name: "leading zero", value: "/api/v1/books/012/cover", valid: false},
+		{name: "other path", value: "/api/v1/books/12", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.New().Var(tt.value, "http_url|cover_url")
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestNew_Date(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "date", value: "2020-02-29", valid: true},
+		{name: "earliest", value: "1000-01-01", valid: true},
+		{name: "before earliest", value: "0999-12-31", valid: false},
+		{name: "invalid day", value: "2019-02-29", valid: false},
+		{name: "time", value: "2020-02-29T00:00:00Z", valid: false},
+		{name: "other format", value: "29/02/2020", valid: false},
+		{name: "empty", value: "", valid: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.New().Var(tt.value, "date")
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestNew_NotFuture(t *testing.T) {
+	today := time.Now()
+
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "past", value: "2000-01-01", valid: true},
+		{name: "yesterday", value: today.AddDate(0, 0, -1).Format("2006-01-02"), valid: true},
+		{name: "next year", value: today.AddDate(1, 0, 0).Format("2006-01-02"), valid: false},
+		// Malformed dates are left to the "date" validation.
+		{name: "not a date", value: "soon", valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.New().Var(tt.value, "not_future")
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestNew_MaxBytes(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{name: "empty", value: "", valid: true},
+		{name: "at limit", value: strings.Repeat("a", 8), valid: true},
+		{name: "over limit", value: strings.Repeat("a", 9), valid: false},
+		// Four runes, but twelve bytes.
+		{name: "multibyte over limit", value: "日本語の", valid: false},
+		{name: "multibyte at limit", value: "日本", valid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.New().Var(tt.value, "max_bytes=8")
+			assert.Equal(t, tt.valid, err == nil)
+		})
+	}
+}
+
+func TestToFieldErrors(t *testing.T) {
+	type form struct {
+		ImageUrl      string `json:"image_url" form:"omitempty,http_url|cover_url"`
+		PublishedDate string `json:"published_date" form:"required,date,not_future"`
+		Description   string `json:"description" form:"max_bytes=4"`
+	}
+
+	tests := []struct {
+		name string
+		form form
+		want []apperror.FieldError
+	}{
+		{
+			name: "valid",
+			form: form{ImageUrl: "https://example.com", PublishedDate: "2000-01-01", Description: "abcd"},
+			want: nil,
+		},
+		{
+			name: "invalid",
+			form: form{ImageUrl: "ftp://example.com", PublishedDate: "2000-13-01", Description: "ééé"},
+			want: []apperror.FieldError{
+				{Name: "image_url", Reason: "must be a valid URL"},
+				{Name: "published_date", Reason: "must be a valid date in YYYY-MM-DD format, not before 1000-01-01"},
+				{Name: "description", Reason: "must be a maximum of 4 bytes in length"},
+			},
+		},
+		{
+			name: "future",
+			form: form{PublishedDate: time.Now().AddDate(1, 0, 0).Format("2006-01-02")},
+			want: []apperror.FieldError{
+				{Name: "published_date", Reason: "must not be in the future"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.New().Struct(tt.form)
+			assert.Equal(t, tt.want, validator.ToFieldErrors(err))
+		})
+	}
+}